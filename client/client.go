@@ -1701,6 +1701,19 @@ func HistoryFromJSON(r io.Reader, options HistoryJSONOptions) (*historypb.Histor
 	return internal.HistoryFromJSON(r, options.LastEventID)
 }
 
+// CollectHistoryExternalStorageClaims consumes iter and returns the external
+// storage claims referenced by the payloads of every history event it yields,
+// de-duplicated and in the order they were first encountered. Obtain iter from
+// Client.GetWorkflowHistory, which returns events as persisted by the server
+// with storage references left unresolved. Pass the result to
+// converter.DeleteExternalStorageClaims to purge the payloads of a closed
+// workflow execution.
+//
+// NOTE: Experimental
+func CollectHistoryExternalStorageClaims(ctx context.Context, iter HistoryEventIterator) ([]converter.ExternalStorageClaim, error) {
+	return internal.CollectHistoryExternalStorageClaims(ctx, iter)
+}
+
 // NewAPIKeyStaticCredentials creates credentials that can be provided to
 // ClientOptions to use a fixed API key.
 //
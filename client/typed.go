@@ -0,0 +1,89 @@
+package client
+
+import (
+	"context"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// TypedWorkflowUpdateHandle is a [WorkflowUpdateHandle] of an update with a result of type O.
+//
+// NOTE: Experimental
+type TypedWorkflowUpdateHandle[O any] interface {
+	// WorkflowID observes the update's workflow ID.
+	WorkflowID() string
+
+	// RunID observes the update's run ID.
+	RunID() string
+
+	// UpdateID observes the update's ID.
+	UpdateID() string
+
+	// Get blocks on the outcome of the update and returns its result.
+	Get(ctx context.Context) (O, error)
+
+	// Handle returns the untyped WorkflowUpdateHandle backing this handle.
+	Handle() WorkflowUpdateHandle
+}
+
+// SignalTypedWorkflow sends the signal defined by signal to a workflow, like
+// [Client.SignalWorkflow].
+//
+//	// In an API package shared with the workflow
+//	var ApproveSignal = temporal.NewSignalDefinition[Approval]("approve")
+//
+//	// In the caller
+//	err := client.SignalTypedWorkflow(ctx, c, workflowID, "", api.ApproveSignal, Approval{By: "me"})
+//
+// NOTE: Experimental
+func SignalTypedWorkflow[T any](ctx context.Context, c Client, workflowID, runID string, signal temporal.SignalDefinition[T], arg T) error {
+	return c.SignalWorkflow(ctx, workflowID, runID, signal.Name(), arg)
+}
+
+// QueryTypedWorkflow queries a workflow with the query defined by query, like
+// [Client.QueryWorkflow], and returns its result.
+//
+// NOTE: Experimental
+func QueryTypedWorkflow[I, O any](ctx context.Context, c Client, workflowID, runID string, query temporal.QueryDefinition[I, O], arg I) (O, error) {
+	var result O
+	value, err := c.QueryWorkflow(ctx, workflowID, runID, query.Name(), arg)
+	if err != nil {
+		return result, err
+	}
+	err = value.Get(&result)
+	return result, err
+}
+
+// UpdateTypedWorkflow issues the update defined by update to a workflow, like
+// [Client.UpdateWorkflow]. The UpdateName and Args of options are set from update and arg.
+//
+// NOTE: Experimental
+func UpdateTypedWorkflow[I, O any](
+	ctx context.Context,
+	c Client,
+	options UpdateWorkflowOptions,
+	update temporal.UpdateDefinition[I, O],
+	arg I,
+) (TypedWorkflowUpdateHandle[O], error) {
+	options.UpdateName = update.Name()
+	options.Args = []interface{}{arg}
+	handle, err := c.UpdateWorkflow(ctx, options)
+	if err != nil {
+		return nil, err
+	}
+	return typedWorkflowUpdateHandle[O]{handle}, nil
+}
+
+type typedWorkflowUpdateHandle[O any] struct {
+	WorkflowUpdateHandle
+}
+
+func (h typedWorkflowUpdateHandle[O]) Get(ctx context.Context) (O, error) {
+	var result O
+	err := h.WorkflowUpdateHandle.Get(ctx, &result)
+	return result, err
+}
+
+func (h typedWorkflowUpdateHandle[O]) Handle() WorkflowUpdateHandle {
+	return h.WorkflowUpdateHandle
+}
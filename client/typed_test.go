@@ -0,0 +1,59 @@
+package client_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/mocks"
+	"go.temporal.io/sdk/temporal"
+)
+
+type approval struct {
+	By string
+}
+
+func TestTypedDefinitions(t *testing.T) {
+	ctx := context.Background()
+	c := mocks.NewClient(t)
+
+	approve := temporal.NewSignalDefinition[approval]("approve")
+	c.On("SignalWorkflow", ctx, "wid", "", "approve", approval{By: "me"}).Return(nil).Once()
+	require.NoError(t, client.SignalTypedWorkflow(ctx, c, "wid", "", approve, approval{By: "me"}))
+
+	status := temporal.NewQueryDefinition[string, int]("status")
+	value := mocks.NewEncodedValue(t)
+	value.On("Get", mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(0).(*int) = 3
+	}).Return(nil).Once()
+	c.On("QueryWorkflow", ctx, "wid", "rid", "status", "arg").Return(value, nil).Once()
+	result, err := client.QueryTypedWorkflow(ctx, c, "wid", "rid", status, "arg")
+	require.NoError(t, err)
+	require.Equal(t, 3, result)
+
+	add := temporal.NewUpdateDefinition[int, int]("add")
+	handle := mocks.NewWorkflowUpdateHandle(t)
+	handle.On("UpdateID").Return("uid").Once()
+	handle.On("Get", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		*args.Get(1).(*int) = 7
+	}).Return(nil).Once()
+	c.On("UpdateWorkflow", ctx, client.UpdateWorkflowOptions{
+		WorkflowID:   "wid",
+		UpdateName:   "add",
+		Args:         []interface{}{7},
+		WaitForStage: client.WorkflowUpdateStageCompleted,
+	}).Return(handle, nil).Once()
+	typedHandle, err := client.UpdateTypedWorkflow(ctx, c, client.UpdateWorkflowOptions{
+		WorkflowID:   "wid",
+		WaitForStage: client.WorkflowUpdateStageCompleted,
+	}, add, 7)
+	require.NoError(t, err)
+	require.Equal(t, "uid", typedHandle.UpdateID())
+	updateResult, err := typedHandle.Get(ctx)
+	require.NoError(t, err)
+	require.Equal(t, 7, updateResult)
+	require.Same(t, handle, typedHandle.Handle())
+}
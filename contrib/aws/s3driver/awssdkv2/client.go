@@ -10,8 +10,10 @@ import (
 	"bytes"
 	"context"
 	"errors"
+	"fmt"
 	"io"
 
+	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/aws/aws-sdk-go-v2/service/s3/types"
 	"github.com/aws/smithy-go"
@@ -22,7 +24,15 @@ type s3Client struct {
 	client *s3.Client
 }
 
-// NewClient creates an s3driver.Client backed by an AWS SDK v2 S3 client.
+var (
+	_ s3driver.DeleterClient   = (*s3Client)(nil)
+	_ s3driver.MultipartClient = (*s3Client)(nil)
+	_ s3driver.RangeClient     = (*s3Client)(nil)
+)
+
+// NewClient creates an s3driver.Client backed by an AWS SDK v2 S3 client. The
+// returned client also implements s3driver.MultipartClient and
+// s3driver.RangeClient.
 //
 // NOTE: Experimental
 func NewClient(client *s3.Client) s3driver.Client {
@@ -59,6 +69,15 @@ func (c *s3Client) ObjectExists(ctx context.Context, bucket, key string) (bool,
 	return true, nil
 }
 
+func (c *s3Client) DeleteObject(ctx context.Context, bucket, key string) error {
+	// S3 reports success when deleting a key that does not exist.
+	_, err := c.client.DeleteObject(ctx, &s3.DeleteObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	return err
+}
+
 func (c *s3Client) Describe() map[string]string {
 	region := c.client.Options().Region
 	if region == "" {
@@ -78,3 +97,81 @@ func (c *s3Client) GetObject(ctx context.Context, bucket, key string) ([]byte, e
 	defer output.Body.Close()
 	return io.ReadAll(output.Body)
 }
+
+func (c *s3Client) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	output, err := c.client.HeadObject(ctx, &s3.HeadObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if output.ContentLength == nil {
+		return 0, errors.New("object size unknown")
+	}
+	return *output.ContentLength, nil
+}
+
+func (c *s3Client) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (io.ReadCloser, error) {
+	rng := fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)
+	output, err := c.client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &bucket,
+		Key:    &key,
+		Range:  &rng,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output.Body, nil
+}
+
+func (c *s3Client) CreateMultipartUpload(ctx context.Context, bucket, key string) (string, error) {
+	output, err := c.client.CreateMultipartUpload(ctx, &s3.CreateMultipartUploadInput{
+		Bucket: &bucket,
+		Key:    &key,
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.UploadId), nil
+}
+
+func (c *s3Client) UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, data []byte) (string, error) {
+	output, err := c.client.UploadPart(ctx, &s3.UploadPartInput{
+		Bucket:     &bucket,
+		Key:        &key,
+		UploadId:   &uploadID,
+		PartNumber: &partNumber,
+		Body:       bytes.NewReader(data),
+	})
+	if err != nil {
+		return "", err
+	}
+	return aws.ToString(output.ETag), nil
+}
+
+func (c *s3Client) CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []s3driver.CompletedPart) error {
+	completed := make([]types.CompletedPart, len(parts))
+	for i, p := range parts {
+		completed[i] = types.CompletedPart{
+			PartNumber: aws.Int32(p.PartNumber),
+			ETag:       aws.String(p.ETag),
+		}
+	}
+	_, err := c.client.CompleteMultipartUpload(ctx, &s3.CompleteMultipartUploadInput{
+		Bucket:          &bucket,
+		Key:             &key,
+		UploadId:        &uploadID,
+		MultipartUpload: &types.CompletedMultipartUpload{Parts: completed},
+	})
+	return err
+}
+
+func (c *s3Client) AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error {
+	_, err := c.client.AbortMultipartUpload(ctx, &s3.AbortMultipartUploadInput{
+		Bucket:   &bucket,
+		Key:      &key,
+		UploadId: &uploadID,
+	})
+	return err
+}
@@ -1,7 +1,9 @@
 package awssdkv2_test
 
 import (
+	"bytes"
 	"context"
+	"io"
 	"net/http/httptest"
 	"testing"
 
@@ -117,6 +119,25 @@ func TestAwsSdkClient_GetObject_BucketNotFound(t *testing.T) {
 	assert.Contains(t, err.Error(), "NoSuchBucket")
 }
 
+func TestAwsSdkClient_DeleteObject(t *testing.T) {
+	client := newFakeS3(t, "test-bucket")
+	ctx := context.Background()
+
+	err := client.PutObject(ctx, "test-bucket", "my/key", []byte("data"))
+	require.NoError(t, err)
+
+	err = client.(s3driver.DeleterClient).DeleteObject(ctx, "test-bucket", "my/key")
+	require.NoError(t, err)
+
+	exists, err := client.ObjectExists(ctx, "test-bucket", "my/key")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Deleting a missing key is not an error.
+	err = client.(s3driver.DeleterClient).DeleteObject(ctx, "test-bucket", "my/key")
+	assert.NoError(t, err)
+}
+
 func TestAwsSdkClient_LargeObject(t *testing.T) {
 	client := newFakeS3(t, "test-bucket")
 	ctx := context.Background()
@@ -135,6 +156,65 @@ func TestAwsSdkClient_LargeObject(t *testing.T) {
 	assert.Equal(t, data, got)
 }
 
+func TestAwsSdkClient_GetObjectRange(t *testing.T) {
+	client := newFakeS3(t, "test-bucket")
+	ctx := context.Background()
+
+	err := client.PutObject(ctx, "test-bucket", "my/key", []byte("0123456789"))
+	require.NoError(t, err)
+
+	size, err := client.(s3driver.RangeClient).ObjectSize(ctx, "test-bucket", "my/key")
+	require.NoError(t, err)
+	assert.Equal(t, int64(10), size)
+
+	r, err := client.(s3driver.RangeClient).GetObjectRange(ctx, "test-bucket", "my/key", 3, 4)
+	require.NoError(t, err)
+	defer func() { _ = r.Close() }()
+	got, err := io.ReadAll(r)
+	require.NoError(t, err)
+	assert.Equal(t, []byte("3456"), got)
+}
+
+func TestAwsSdkClient_MultipartUpload(t *testing.T) {
+	client := newFakeS3(t, "test-bucket")
+	mc := client.(s3driver.MultipartClient)
+	ctx := context.Background()
+
+	part1 := bytes.Repeat([]byte("a"), 5*1024*1024)
+	part2 := []byte("tail")
+	uploadID, err := mc.CreateMultipartUpload(ctx, "test-bucket", "mp")
+	require.NoError(t, err)
+	etag1, err := mc.UploadPart(ctx, "test-bucket", "mp", uploadID, 1, part1)
+	require.NoError(t, err)
+	etag2, err := mc.UploadPart(ctx, "test-bucket", "mp", uploadID, 2, part2)
+	require.NoError(t, err)
+	err = mc.CompleteMultipartUpload(ctx, "test-bucket", "mp", uploadID, []s3driver.CompletedPart{
+		{PartNumber: 1, ETag: etag1},
+		{PartNumber: 2, ETag: etag2},
+	})
+	require.NoError(t, err)
+
+	got, err := client.GetObject(ctx, "test-bucket", "mp")
+	require.NoError(t, err)
+	assert.Equal(t, append(part1, part2...), got)
+}
+
+func TestAwsSdkClient_AbortMultipartUpload(t *testing.T) {
+	client := newFakeS3(t, "test-bucket")
+	mc := client.(s3driver.MultipartClient)
+	ctx := context.Background()
+
+	uploadID, err := mc.CreateMultipartUpload(ctx, "test-bucket", "mp")
+	require.NoError(t, err)
+	_, err = mc.UploadPart(ctx, "test-bucket", "mp", uploadID, 1, []byte("data"))
+	require.NoError(t, err)
+	require.NoError(t, mc.AbortMultipartUpload(ctx, "test-bucket", "mp", uploadID))
+
+	exists, err := client.ObjectExists(ctx, "test-bucket", "mp")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
 func TestAwsSdkClient_Describe_ReturnsClientRegion(t *testing.T) {
 	client := newFakeS3(t)
 	assert.Equal(t, map[string]string{"client_region": "ap-southeast-2"}, client.Describe())
@@ -186,3 +266,42 @@ func TestAwsSdkClient_FullDriverRoundTrip(t *testing.T) {
 		assert.Equal(t, payloads[i].Data, restored[i].Data)
 	}
 }
+
+// TestAwsSdkClient_MultipartDriverRoundTrip exercises multipart upload and
+// ranged retrieval end-to-end through the fake S3 backend.
+func TestAwsSdkClient_MultipartDriverRoundTrip(t *testing.T) {
+	client := newFakeS3(t, "driver-bucket")
+
+	const partSize = 5 * 1024 * 1024
+	d, err := s3driver.NewDriver(s3driver.Options{
+		Client:             client,
+		Bucket:             s3driver.StaticBucket("driver-bucket"),
+		MultipartThreshold: partSize,
+		PartSize:           partSize,
+	})
+	require.NoError(t, err)
+
+	data := make([]byte, 2*partSize+123)
+	for i := range data {
+		data[i] = byte(i % 251)
+	}
+	payloads := []*commonpb.Payload{{Data: data}}
+
+	claims, err := d.Store(
+		converter.StorageDriverStoreContext{Context: context.Background()},
+		payloads,
+	)
+	require.NoError(t, err)
+	manifestKey, chunked := converter.StorageChunkManifestKey(claims[0])
+	require.True(t, chunked)
+	exists, err := client.ObjectExists(context.Background(), "driver-bucket", manifestKey)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	restored, err := d.Retrieve(
+		converter.StorageDriverRetrieveContext{Context: context.Background()},
+		claims,
+	)
+	require.NoError(t, err)
+	assert.Equal(t, data, restored[0].Data)
+}
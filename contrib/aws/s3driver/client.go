@@ -1,9 +1,12 @@
 package s3driver
 
-import "context"
+import (
+	"context"
+	"io"
+)
 
 // Client is the interface that the driver uses to interact with S3. It covers
-// the three operations the driver needs: put, existence check, and get.
+// the operations the driver needs: put, existence check, and get.
 // Use [go.temporal.io/sdk/contrib/aws/s3driver/awssdkv2.NewClient] to obtain
 // an implementation backed by the AWS SDK v2, or supply a custom
 // implementation for testing or alternative S3-compatible storage.
@@ -30,3 +33,67 @@ type Client interface {
 	// messages. Return nil or an empty map if no metadata is available.
 	Describe() map[string]string
 }
+
+// DeleterClient is an optional interface that a [Client] may implement to let
+// the driver delete stored payloads, see
+// [go.temporal.io/sdk/converter.DeleteExternalStorageClaims]. When the
+// configured client does not implement it, deleting fails with an error
+// wrapping [errors.ErrUnsupported].
+//
+// NOTE: Experimental
+type DeleterClient interface {
+	// DeleteObject removes the object stored at the given bucket and key. It
+	// should return nil if the object does not exist.
+	DeleteObject(ctx context.Context, bucket, key string) error
+}
+
+// CompletedPart identifies a part uploaded with MultipartClient.UploadPart.
+//
+// NOTE: Experimental
+type CompletedPart struct {
+	// PartNumber is the 1-based number of the part.
+	PartNumber int32
+	// ETag is the entity tag returned when the part was uploaded.
+	ETag string
+}
+
+// MultipartClient is an optional interface that a [Client] may implement to
+// let the driver upload large payloads in parts. When the configured client
+// implements it, payloads at or above [Options.MultipartThreshold] are
+// uploaded with a multipart upload instead of a single PutObject call.
+//
+// NOTE: Experimental
+type MultipartClient interface {
+	// CreateMultipartUpload starts a multipart upload to the given bucket and
+	// key and returns its upload ID.
+	CreateMultipartUpload(ctx context.Context, bucket, key string) (uploadID string, err error)
+
+	// UploadPart uploads one part of a multipart upload and returns its ETag.
+	// Implementations must be safe to call concurrently for different parts.
+	UploadPart(ctx context.Context, bucket, key, uploadID string, partNumber int32, data []byte) (etag string, err error)
+
+	// CompleteMultipartUpload assembles the uploaded parts, given in ascending
+	// part number order, into the final object.
+	CompleteMultipartUpload(ctx context.Context, bucket, key, uploadID string, parts []CompletedPart) error
+
+	// AbortMultipartUpload discards an unfinished multipart upload and its
+	// uploaded parts.
+	AbortMultipartUpload(ctx context.Context, bucket, key, uploadID string) error
+}
+
+// RangeClient is an optional interface that a [Client] may implement to let
+// the driver download large payloads as concurrent byte ranges. When the
+// configured client implements it, payloads stored in chunks are downloaded
+// one range per chunk, and each chunk is verified once it is downloaded. The
+// chunks are assembled in memory, so the whole payload is still held at once.
+//
+// NOTE: Experimental
+type RangeClient interface {
+	// ObjectSize returns the size in bytes of the object at the given bucket
+	// and key.
+	ObjectSize(ctx context.Context, bucket, key string) (int64, error)
+
+	// GetObjectRange returns a reader over length bytes of the object at the
+	// given bucket and key, starting at offset. The caller closes the reader.
+	GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (io.ReadCloser, error)
+}
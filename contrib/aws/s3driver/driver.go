@@ -1,29 +1,30 @@
 package s3driver
 
 import (
-	"crypto/sha256"
-	"encoding/hex"
+	"context"
 	"errors"
 	"fmt"
-	"net/url"
+	"io"
 
 	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
 	"golang.org/x/sync/errgroup"
-	"google.golang.org/protobuf/proto"
 )
 
 const (
 	defaultMaxPayloadSize = 50 * 1024 * 1024 // 50 MiB
 	driverType            = "aws.s3driver"
 	defaultDriverName     = "aws.s3driver"
-	hashAlgorithm         = "sha256"
-	keyVersion            = "v0"
 
-	claimKeyBucket        = "bucket"
-	claimKeyKey           = "key"
-	claimKeyHashAlgorithm = "hash_algorithm"
-	claimKeyHashValue     = "hash_value"
+	defaultMultipartThreshold = 32 * 1024 * 1024 // 32 MiB
+	defaultPartSize           = 16 * 1024 * 1024 // 16 MiB
+	defaultPartConcurrency    = 4
+	minPartSize               = 5 * 1024 * 1024 // S3 minimum for all but the last part
+	maxPartCount              = 10000           // S3 maximum parts per upload
+
+	claimKeyBucket = "bucket"
+	claimKeyKey    = "key"
 )
 
 // BucketFunc resolves the target S3 bucket for a given payload. Use
@@ -55,23 +56,62 @@ type Options struct {
 	DriverName string
 
 	// MaxPayloadSize is the maximum serialized payload size in bytes that
-	// the driver will accept. Defaults to 50 MiB.
+	// the driver will accept. Defaults to 50 MiB. Payloads are held in memory
+	// in full when they are stored and retrieved, including those uploaded in
+	// parts, so raise it only as far as workers can afford.
 	MaxPayloadSize int
+
+	// MultipartThreshold is the serialized payload size in bytes at or above
+	// which payloads are uploaded in parts of PartSize bytes. Such payloads
+	// are also downloaded as concurrent byte ranges if Client implements
+	// RangeClient, with each part verified against its own SHA-256 hash once
+	// it is downloaded. The hashes of the parts are stored in an object next to the
+	// payload rather than in the claim.
+	// Only used if Client implements MultipartClient. Defaults to 32 MiB.
+	MultipartThreshold int
+
+	// PartSize is the size in bytes of each part of a multipart upload and of
+	// each range of a ranged download. Must be at least 5 MiB, the S3 minimum.
+	// Defaults to 16 MiB.
+	PartSize int
+
+	// PartConcurrency is the maximum number of parts of a single payload that
+	// are uploaded or downloaded concurrently. Defaults to 4.
+	PartConcurrency int
 }
 
-// s3StorageDriver implements converter.StorageDriver by storing payloads in
-// Amazon S3 using content-addressable keys based on SHA-256 hashes.
-type s3StorageDriver struct {
+// objectStore adapts a Client to the object store the driver is built on,
+// uploading large payloads in parts and downloading them as byte ranges when
+// the client supports it.
+type objectStore struct {
 	client         Client
-	bucketFunc     BucketFunc
-	driverName     string
 	maxPayloadSize int
+
+	// multipartClient is nil if client does not implement MultipartClient.
+	multipartClient    MultipartClient
+	multipartThreshold int
+	partSize           int
+	partConcurrency    int
 }
 
-// Compile-time check that s3StorageDriver implements converter.StorageDriver.
-var _ converter.StorageDriver = (*s3StorageDriver)(nil)
+// deleterObjectStore is used in place of objectStore when the client
+// implements DeleterClient, so that the driver supports deletion.
+type deleterObjectStore struct {
+	*objectStore
+	deleter DeleterClient
+}
+
+// Compile-time checks that the object stores support storage and deletion.
+var (
+	_ extstore.ObjectStore            = (*objectStore)(nil)
+	_ extstore.ObjectStoreClaimWriter = (*objectStore)(nil)
+	_ extstore.ObjectStoreDeleter     = deleterObjectStore{}
+)
 
-// NewDriver creates a new S3 StorageDriver with the given options.
+// NewDriver creates a new S3 StorageDriver with the given options. The driver
+// stores payloads in Amazon S3 using content-addressable keys based on SHA-256
+// hashes. It implements converter.StorageDriverDeleter, which returns an error
+// wrapping errors.ErrUnsupported if Client does not implement DeleterClient.
 //
 // NOTE: Experimental
 func NewDriver(opts Options) (converter.StorageDriver, error) {
@@ -81,10 +121,33 @@ func NewDriver(opts Options) (converter.StorageDriver, error) {
 	if opts.Bucket == nil {
 		return nil, errors.New("Bucket is required")
 	}
+	store, err := newObjectStore(opts)
+	if err != nil {
+		return nil, err
+	}
 	name := opts.DriverName
 	if name == "" {
 		name = defaultDriverName
 	}
+	var s extstore.ObjectStore = store
+	if deleter, ok := opts.Client.(DeleterClient); ok {
+		s = deleterObjectStore{objectStore: store, deleter: deleter}
+	}
+	return extstore.NewObjectStoreDriver(extstore.ObjectStoreDriverOptions{
+		Store:             s,
+		Container:         opts.Bucket,
+		DriverName:        name,
+		DriverType:        driverType,
+		MaxPayloadSize:    store.maxPayloadSize,
+		ContainerClaimKey: claimKeyBucket,
+		NameClaimKey:      claimKeyKey,
+	}), nil
+}
+
+// newObjectStore validates the size and part options and applies their
+// defaults.
+func newObjectStore(opts Options) (*objectStore, error) {
+	multipartClient, _ := opts.Client.(MultipartClient)
 	maxSize := opts.MaxPayloadSize
 	if maxSize == 0 {
 		maxSize = defaultMaxPayloadSize
@@ -92,186 +155,209 @@ func NewDriver(opts Options) (converter.StorageDriver, error) {
 	if maxSize < 0 {
 		return nil, fmt.Errorf("MaxPayloadSize must be positive, got %d", maxSize)
 	}
-	return &s3StorageDriver{
-		client:         opts.Client,
-		bucketFunc:     opts.Bucket,
-		driverName:     name,
-		maxPayloadSize: maxSize,
+	threshold := opts.MultipartThreshold
+	if threshold == 0 {
+		threshold = defaultMultipartThreshold
+	}
+	if threshold < 0 {
+		return nil, fmt.Errorf("MultipartThreshold must be positive, got %d", threshold)
+	}
+	partSize := opts.PartSize
+	if partSize == 0 {
+		partSize = defaultPartSize
+	}
+	if partSize < minPartSize {
+		return nil, fmt.Errorf("PartSize must be at least %d, got %d", minPartSize, partSize)
+	}
+	if multipartClient != nil && maxSize > partSize*maxPartCount {
+		return nil, fmt.Errorf("MaxPayloadSize %d exceeds %d parts of PartSize %d", maxSize, maxPartCount, partSize)
+	}
+	concurrency := opts.PartConcurrency
+	if concurrency == 0 {
+		concurrency = defaultPartConcurrency
+	}
+	if concurrency < 0 {
+		return nil, fmt.Errorf("PartConcurrency must be positive, got %d", concurrency)
+	}
+	return &objectStore{
+		client:             opts.Client,
+		maxPayloadSize:     maxSize,
+		multipartClient:    multipartClient,
+		multipartThreshold: threshold,
+		partSize:           partSize,
+		partConcurrency:    concurrency,
 	}, nil
 }
 
-// Name returns the unique identifier for this driver instance.
-func (d *s3StorageDriver) Name() string { return d.driverName }
+func (s *objectStore) multipart(data []byte) bool {
+	return s.multipartClient != nil && len(data) >= s.multipartThreshold
+}
 
-// Type returns the driver implementation type.
-func (d *s3StorageDriver) Type() string { return driverType }
+func (s *objectStore) ObjectExists(ctx context.Context, bucket, key string) (bool, error) {
+	return s.client.ObjectExists(ctx, bucket, key)
+}
 
-type preparedPayload struct {
-	data      []byte
-	hexDigest string
-	bucket    string
+func (s *objectStore) PutObject(ctx context.Context, bucket, key string, data []byte) error {
+	if s.multipart(data) {
+		return s.putObjectMultipart(ctx, bucket, key, data)
+	}
+	return s.client.PutObject(ctx, bucket, key, data)
 }
 
-// Store serializes each payload, validates sizes, then uploads concurrently to
-// S3 if not already present, and returns a claim per payload.
-//
-// Two phases are used to avoid partial S3 uploads when validation fails:
-//  1. Marshal and validate all payloads sequentially.
-//  2. Upload concurrently — only reached if all payloads passed validation.
-func (d *s3StorageDriver) Store(
-	ctx converter.StorageDriverStoreContext,
-	payloads []*commonpb.Payload,
-) ([]converter.StorageDriverClaim, error) {
-	prepared := make([]preparedPayload, len(payloads))
-	for i, p := range payloads {
-		data, err := proto.Marshal(p)
-		if err != nil {
-			return nil, fmt.Errorf("failed to marshal payload: %w", err)
-		}
-		if len(data) > d.maxPayloadSize {
-			return nil, fmt.Errorf(
-				"payload size %d exceeds maximum %d",
-				len(data), d.maxPayloadSize,
-			)
-		}
-		prepared[i] = preparedPayload{
-			data:      data,
-			hexDigest: sha256Hex(data),
-			bucket:    d.bucketFunc(ctx, p),
+// AddToClaim records the chunk manifest of payloads uploaded in parts, which
+// lets them be downloaded as ranges.
+func (s *objectStore) AddToClaim(ctx context.Context, bucket, key string, data []byte, claimData map[string]string) error {
+	if !s.multipart(data) {
+		return nil
+	}
+	return s.putChunkManifest(ctx, bucket, key, data, claimData)
+}
+
+func (s *objectStore) GetObject(ctx context.Context, bucket, key string, claim converter.StorageDriverClaim) ([]byte, error) {
+	if rangeClient, ok := s.client.(RangeClient); ok {
+		if _, chunked := converter.StorageChunkManifestKey(claim); chunked {
+			return s.getObjectRanges(ctx, rangeClient, bucket, key, claim)
 		}
 	}
+	return s.client.GetObject(ctx, bucket, key)
+}
 
-	claims := make([]converter.StorageDriverClaim, len(payloads))
-	g, gctx := errgroup.WithContext(ctx.Context)
-	for i, pp := range prepared {
-		g.Go(func() error {
-			key := objectKey(ctx.Target, pp.hexDigest)
-			exists, err := d.client.ObjectExists(gctx, pp.bucket, key)
-			if err != nil {
-				return fmt.Errorf("existence check failed [bucket=%s, key=%s%s]: %w", pp.bucket, key, describeClient(d.client), err)
-			}
-			if !exists {
-				if err := d.client.PutObject(gctx, pp.bucket, key, pp.data); err != nil {
-					return fmt.Errorf("upload failed [bucket=%s, key=%s%s]: %w", pp.bucket, key, describeClient(d.client), err)
-				}
-			}
-			claims[i] = converter.StorageDriverClaim{
-				ClaimData: map[string]string{
-					claimKeyBucket:        pp.bucket,
-					claimKeyKey:           key,
-					claimKeyHashAlgorithm: hashAlgorithm,
-					claimKeyHashValue:     pp.hexDigest,
-				},
-			}
-			return nil
-		})
+func (s *objectStore) Describe() map[string]string {
+	return s.client.Describe()
+}
+
+// DeleteObject deletes the object and its chunk manifest, if any.
+func (s deleterObjectStore) DeleteObject(ctx context.Context, bucket, key string, claim converter.StorageDriverClaim) error {
+	if err := s.deleter.DeleteObject(ctx, bucket, key); err != nil {
+		return err
 	}
-	if err := g.Wait(); err != nil {
-		return nil, err
+	if manifestKey, ok := converter.StorageChunkManifestKey(claim); ok {
+		if err := s.deleter.DeleteObject(ctx, bucket, manifestKey); err != nil {
+			return fmt.Errorf("chunk manifest %s: %w", manifestKey, err)
+		}
 	}
-	return claims, nil
+	return nil
 }
 
-// Retrieve downloads payloads from S3 using the given claims, verifies their
-// integrity via SHA-256, and returns the deserialized payloads. Claims are
-// processed concurrently.
-func (d *s3StorageDriver) Retrieve(
-	ctx converter.StorageDriverRetrieveContext,
-	claims []converter.StorageDriverClaim,
-) ([]*commonpb.Payload, error) {
-	payloads := make([]*commonpb.Payload, len(claims))
-	g, gctx := errgroup.WithContext(ctx.Context)
-
-	for i, c := range claims {
-		g.Go(func() error {
-			bucket, ok := c.ClaimData[claimKeyBucket]
-			if !ok {
-				return fmt.Errorf("claim missing field %q", claimKeyBucket)
-			}
-			key, ok := c.ClaimData[claimKeyKey]
-			if !ok {
-				return fmt.Errorf("claim missing field %q", claimKeyKey)
+// putObjectMultipart uploads data in parts of s.partSize bytes, at most
+// s.partConcurrency at a time. The upload is aborted if any part fails.
+func (s *objectStore) putObjectMultipart(ctx context.Context, bucket, key string, data []byte) (err error) {
+	uploadID, err := s.multipartClient.CreateMultipartUpload(ctx, bucket, key)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err != nil {
+			// Abort even if ctx was canceled so no orphaned parts are left behind.
+			if abortErr := s.multipartClient.AbortMultipartUpload(context.WithoutCancel(ctx), bucket, key, uploadID); abortErr != nil {
+				err = errors.Join(err, fmt.Errorf("abort failed: %w", abortErr))
 			}
+		}
+	}()
 
-			data, err := d.client.GetObject(gctx, bucket, key)
+	partCount := (len(data) + s.partSize - 1) / s.partSize
+	parts := make([]CompletedPart, partCount)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.partConcurrency)
+	for i := range partCount {
+		g.Go(func() error {
+			start := i * s.partSize
+			end := min(start+s.partSize, len(data))
+			partNumber := int32(i + 1)
+			etag, err := s.multipartClient.UploadPart(gctx, bucket, key, uploadID, partNumber, data[start:end])
 			if err != nil {
-				return fmt.Errorf("download failed [bucket=%s, key=%s%s]: %w", bucket, key, describeClient(d.client), err)
-			}
-
-			algo, ok := c.ClaimData[claimKeyHashAlgorithm]
-			if !ok {
-				return fmt.Errorf("claim missing field %q", claimKeyHashAlgorithm)
-			}
-			if algo != hashAlgorithm {
-				return fmt.Errorf("unsupported hash algorithm %q", algo)
-			}
-
-			expectedHash, ok := c.ClaimData[claimKeyHashValue]
-			if !ok {
-				return fmt.Errorf("claim missing field %q", claimKeyHashValue)
-			}
-			if actualHash := sha256Hex(data); actualHash != expectedHash {
-				return fmt.Errorf(
-					"integrity check failed [bucket=%s, key=%s]: expected hash %s, got %s",
-					bucket, key, expectedHash, actualHash,
-				)
+				return fmt.Errorf("part %d: %w", partNumber, err)
 			}
-
-			var payload commonpb.Payload
-			if err := proto.Unmarshal(data, &payload); err != nil {
-				return fmt.Errorf("failed to unmarshal payload [bucket=%s, key=%s]: %w", bucket, key, err)
-			}
-			payloads[i] = &payload
+			parts[i] = CompletedPart{PartNumber: partNumber, ETag: etag}
 			return nil
 		})
 	}
-
 	if err := g.Wait(); err != nil {
-		return nil, err
+		return err
 	}
-	return payloads, nil
+	return s.multipartClient.CompleteMultipartUpload(ctx, bucket, key, uploadID, parts)
 }
 
-func objectKey(target converter.StorageDriverTargetInfo, hexDigest string) string {
-	digestSegment := "/d/" + hashAlgorithm + "/" + hexDigest
-	switch t := target.(type) {
-	case converter.StorageDriverWorkflowInfo:
-		return keyVersion +
-			"/ns/" + pathEscape(t.Namespace) +
-			"/wt/" + pathEscape(t.WorkflowType) +
-			"/wi/" + pathEscape(t.WorkflowID) +
-			"/ri/" + pathEscape(t.RunID) +
-			digestSegment
-	case converter.StorageDriverActivityInfo:
-		return keyVersion +
-			"/ns/" + pathEscape(t.Namespace) +
-			"/at/" + pathEscape(t.ActivityType) +
-			"/ai/" + pathEscape(t.ActivityID) +
-			"/ri/" + pathEscape(t.RunID) +
-			digestSegment
-	default:
-		return keyVersion + digestSegment
+// putChunkManifest stores the chunk manifest of data, chunked in parts of
+// s.partSize bytes, as an object next to key and records it in claimData.
+// Keeping the manifest out of the claim keeps the claim small no matter how
+// many parts the payload has.
+func (s *objectStore) putChunkManifest(ctx context.Context, bucket, key string, data []byte, claimData map[string]string) error {
+	manifest, err := converter.NewStorageChunkManifest(data, int64(s.partSize))
+	if err != nil {
+		return err
 	}
-}
-
-// describeClient returns ", k=v, k=v" diagnostic info from the client's
-// Describe method, or "" if Describe returns nil/empty.
-func describeClient(c Client) string {
-	var s string
-	for k, v := range c.Describe() {
-		s += ", " + k + "=" + v
+	encoded, err := manifest.Marshal()
+	if err != nil {
+		return err
+	}
+	// The part size is part of the key since drivers configured with
+	// different part sizes produce different manifests for the same data.
+	manifestKey := fmt.Sprintf("%s.chunks-%d", key, s.partSize)
+	exists, err := s.client.ObjectExists(ctx, bucket, manifestKey)
+	if err != nil {
+		return fmt.Errorf("existence check failed [bucket=%s, key=%s]: %w", bucket, manifestKey, err)
 	}
-	return s
+	if !exists {
+		if err := s.client.PutObject(ctx, bucket, manifestKey, encoded); err != nil {
+			return fmt.Errorf("upload failed [bucket=%s, key=%s]: %w", bucket, manifestKey, err)
+		}
+	}
+	manifest.AddToClaim(claimData, manifestKey, encoded)
+	return nil
 }
 
-func pathEscape(s string) string {
-	if s == "" {
-		return "null"
+// getObjectRanges downloads the chunks described by the manifest recorded in
+// claim as concurrent byte ranges, at most s.partConcurrency at a time,
+// reading each directly into its place in the result and verifying it against
+// its hash. The whole payload is held in memory, so the size claimed by the
+// manifest must match the object and not exceed s.maxPayloadSize.
+func (s *objectStore) getObjectRanges(
+	ctx context.Context,
+	client RangeClient,
+	bucket, key string,
+	claim converter.StorageDriverClaim,
+) ([]byte, error) {
+	manifestKey, _ := converter.StorageChunkManifestKey(claim)
+	encoded, err := s.client.GetObject(ctx, bucket, manifestKey)
+	if err != nil {
+		return nil, fmt.Errorf("chunk manifest: %w", err)
+	}
+	manifest, err := converter.DecodeStorageChunkManifest(claim, encoded)
+	if err != nil {
+		return nil, err
+	}
+	if manifest.Size > int64(s.maxPayloadSize) {
+		return nil, fmt.Errorf("payload size %d exceeds maximum %d", manifest.Size, s.maxPayloadSize)
+	}
+	size, err := client.ObjectSize(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	if size != manifest.Size {
+		return nil, fmt.Errorf("object size %d does not match chunk manifest size %d", size, manifest.Size)
 	}
-	return url.PathEscape(s)
-}
 
-func sha256Hex(data []byte) string {
-	h := sha256.Sum256(data)
-	return hex.EncodeToString(h[:])
+	data := make([]byte, manifest.Size)
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(s.partConcurrency)
+	for i := range manifest.ChunkCount() {
+		g.Go(func() error {
+			offset, length := manifest.ChunkRange(i)
+			r, err := client.GetObjectRange(gctx, bucket, key, offset, length)
+			if err != nil {
+				return fmt.Errorf("chunk %d: %w", i, err)
+			}
+			defer func() { _ = r.Close() }()
+			chunk := data[offset : offset+length]
+			if _, err := io.ReadFull(r, chunk); err != nil {
+				return fmt.Errorf("chunk %d: %w", i, err)
+			}
+			return manifest.VerifyChunk(i, chunk)
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return data, nil
 }
@@ -1,17 +1,20 @@
 package s3driver
 
 import (
+	"bytes"
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
 	"errors"
 	"fmt"
+	"io"
 	"sync"
 	"sync/atomic"
 	"testing"
 
 	commonpb "go.temporal.io/api/common/v1"
 	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -64,6 +67,13 @@ func (m *memClient) GetObject(_ context.Context, bucket, key string) ([]byte, er
 	return cp, nil
 }
 
+func (m *memClient) DeleteObject(_ context.Context, bucket, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, memKey(bucket, key))
+	return nil
+}
+
 func (m *memClient) Describe() map[string]string { return m.describe }
 
 func testPayload(data string) *commonpb.Payload {
@@ -87,14 +97,14 @@ func storeCtx() converter.StorageDriverStoreContext {
 	return converter.StorageDriverStoreContext{Context: context.Background()}
 }
 
-func storeCtxWithTarget(target converter.StorageDriverTargetInfo) converter.StorageDriverStoreContext {
-	return converter.StorageDriverStoreContext{Context: context.Background(), Target: target}
-}
-
 func retrieveCtx() converter.StorageDriverRetrieveContext {
 	return converter.StorageDriverRetrieveContext{Context: context.Background()}
 }
 
+func deleteCtx() converter.StorageDriverDeleteContext {
+	return converter.StorageDriverDeleteContext{Context: context.Background()}
+}
+
 // --- Constructor tests ---
 
 func TestNewS3StorageDriver_Defaults(t *testing.T) {
@@ -104,11 +114,11 @@ func TestNewS3StorageDriver_Defaults(t *testing.T) {
 		Bucket: StaticBucket("b"),
 	})
 	require.NoError(t, err)
-	typedDriver, ok := d.(*s3StorageDriver)
-	require.True(t, ok, "expected *s3Driver, got %T", d)
+	typedDriver, ok := d.(*extstore.ObjectStoreDriver)
+	require.True(t, ok, "expected *extstore.ObjectStoreDriver, got %T", d)
 	assert.Equal(t, "aws.s3driver", typedDriver.Name())
 	assert.Equal(t, "aws.s3driver", typedDriver.Type())
-	assert.Equal(t, 50*1024*1024, typedDriver.maxPayloadSize)
+	assert.Equal(t, 50*1024*1024, typedDriver.MaxPayloadSize())
 }
 
 func TestNewS3StorageDriver_CustomName(t *testing.T) {
@@ -153,9 +163,12 @@ func TestStaticBucket(t *testing.T) {
 	assert.Equal(t, "my-bucket", fn(converter.StorageDriverStoreContext{Context: context.Background()}, testPayload("x")))
 }
 
-// --- Store tests ---
+// --- Store, Retrieve and Delete tests ---
+//
+// The storage logic shared by the object store drivers is tested in
+// go.temporal.io/sdk/internal/extstore. These tests cover the use of Client.
 
-func TestStore_SinglePayload(t *testing.T) {
+func TestStore_RoundTrip(t *testing.T) {
 	mc := newMemClient()
 	d := newDriver(t, mc)
 	p := testPayload("hello")
@@ -164,106 +177,26 @@ func TestStore_SinglePayload(t *testing.T) {
 	require.NoError(t, err)
 	require.Len(t, claims, 1)
 
-	assert.Equal(t, "test-bucket", claims[0].ClaimData["bucket"])
-	assert.Equal(t, "sha256", claims[0].ClaimData["hash_algorithm"])
-	assert.NotEmpty(t, claims[0].ClaimData["key"])
-	assert.NotEmpty(t, claims[0].ClaimData["hash_value"])
-
-	// Verify key format.
 	data, _ := proto.Marshal(p)
 	h := sha256.Sum256(data)
 	expectedDigest := hex.EncodeToString(h[:])
-	assert.Equal(t, "v0/d/sha256/"+expectedDigest, claims[0].ClaimData["key"])
-	assert.Equal(t, expectedDigest, claims[0].ClaimData["hash_value"])
-}
-
-func TestStore_EmptyPayloads(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{})
-	require.NoError(t, err)
-	assert.Empty(t, claims)
-}
-
-func TestStore_Deduplication(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-	p := testPayload("duplicate-me")
-
-	_, err := d.Store(storeCtx(), []*commonpb.Payload{p})
-	require.NoError(t, err)
-	assert.Equal(t, int64(1), mc.putCount.Load())
-
-	// Store same payload again — should skip the upload.
+	assert.Equal(t, map[string]string{
+		"bucket":         "test-bucket",
+		"key":            "v0/d/sha256/" + expectedDigest,
+		"hash_algorithm": "sha256",
+		"hash_value":     expectedDigest,
+	}, claims[0].ClaimData)
+	assert.Equal(t, data, mc.data[memKey("test-bucket", "v0/d/sha256/"+expectedDigest)])
+
+	// Storing the same payload again skips the upload.
 	_, err = d.Store(storeCtx(), []*commonpb.Payload{p})
 	require.NoError(t, err)
 	assert.Equal(t, int64(1), mc.putCount.Load())
-}
-
-func TestStore_MultiplePayloads(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	payloads := []*commonpb.Payload{
-		testPayload("a"),
-		testPayload("b"),
-		testPayload("c"),
-	}
-
-	claims, err := d.Store(storeCtx(), payloads)
-	require.NoError(t, err)
-	require.Len(t, claims, 3)
-
-	// Each should have unique keys.
-	keys := map[string]bool{}
-	for _, c := range claims {
-		keys[c.ClaimData["key"]] = true
-	}
-	assert.Len(t, keys, 3)
-}
-
-func TestStore_MaxPayloadSizeExceeded(t *testing.T) {
-	mc := newMemClient()
-	d, err := NewDriver(Options{
-		Client:         mc,
-		Bucket:         StaticBucket("b"),
-		MaxPayloadSize: 10, // very small
-	})
-	require.NoError(t, err)
-
-	p := testPayload("this payload is definitely larger than 10 bytes when serialized")
-	_, err = d.Store(storeCtx(), []*commonpb.Payload{p})
-	assert.ErrorContains(t, err, "payload size ")
-	assert.ErrorContains(t, err, " exceeds maximum 10")
-}
 
-func TestStore_DynamicBucket(t *testing.T) {
-	mc := newMemClient()
-	d, err := NewDriver(Options{
-		Client: mc,
-		Bucket: func(_ converter.StorageDriverStoreContext, p *commonpb.Payload) string {
-			if string(p.Data) == "a" {
-				return "bucket-a"
-			}
-			return "bucket-b"
-		},
-	})
-	require.NoError(t, err)
-
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{
-		testPayload("a"),
-		testPayload("b"),
-	})
+	restored, err := d.Retrieve(retrieveCtx(), claims)
 	require.NoError(t, err)
-	require.Len(t, claims, 2)
-
-	buckets := map[string]bool{}
-	for _, c := range claims {
-		buckets[c.ClaimData["bucket"]] = true
-	}
-	assert.True(t, buckets["bucket-a"])
-	assert.True(t, buckets["bucket-b"])
+	require.Len(t, restored, 1)
+	assert.True(t, proto.Equal(p, restored[0]))
 }
 
 // errClient wraps a memClient and injects errors.
@@ -272,6 +205,7 @@ type errClient struct {
 	putErr    error
 	getErr    error
 	existsErr error
+	deleteErr error
 }
 
 func (e *errClient) PutObject(ctx context.Context, bucket, key string, data []byte) error {
@@ -295,356 +229,319 @@ func (e *errClient) GetObject(ctx context.Context, bucket, key string) ([]byte,
 	return e.memClient.GetObject(ctx, bucket, key)
 }
 
-func TestStore_PutObjectError(t *testing.T) {
-	ec := &errClient{
-		memClient: newMemClient(),
-		putErr:    errors.New("access denied"),
+func (e *errClient) DeleteObject(ctx context.Context, bucket, key string) error {
+	if e.deleteErr != nil {
+		return e.deleteErr
 	}
-	d := newDriver(t, ec)
-
-	_, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
-	assert.ErrorContains(t, err, "upload failed [bucket=test-bucket, key=")
-	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: access denied")
+	return e.memClient.DeleteObject(ctx, bucket, key)
 }
 
-func TestStore_ObjectExistsError(t *testing.T) {
-	ec := &errClient{
-		memClient: newMemClient(),
-		existsErr: errors.New("network timeout"),
-	}
-	d := newDriver(t, ec)
+func TestClientErrors(t *testing.T) {
+	mc := newMemClient()
+	claims, err := newDriver(t, mc).Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
 
-	_, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	d := newDriver(t, &errClient{memClient: newMemClient(), existsErr: errors.New("network timeout")})
+	_, err = d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
 	assert.ErrorContains(t, err, "existence check failed [bucket=test-bucket, key=")
 	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: network timeout")
-}
-
-// --- Retrieve tests ---
-
-func TestRetrieve_RoundTrip(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
 
-	original := testPayload("round-trip data")
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{original})
-	require.NoError(t, err)
+	d = newDriver(t, &errClient{memClient: newMemClient(), putErr: errors.New("access denied")})
+	_, err = d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	assert.ErrorContains(t, err, "upload failed [bucket=test-bucket, key=")
+	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: access denied")
 
-	restored, err := d.Retrieve(retrieveCtx(), claims)
-	require.NoError(t, err)
-	require.Len(t, restored, 1)
+	d = newDriver(t, &errClient{memClient: mc, getErr: errors.New("throttled")})
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "download failed [bucket=test-bucket, key=")
+	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: throttled")
 
-	assert.True(t, proto.Equal(original, restored[0]),
-		"restored payload should equal original")
+	d = newDriver(t, &errClient{memClient: mc, deleteErr: errors.New("access denied")})
+	err = d.(converter.StorageDriverDeleter).Delete(deleteCtx(), claims)
+	assert.ErrorContains(t, err, "delete failed [bucket=test-bucket, key=")
+	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: access denied")
 }
 
-func TestRetrieve_MultiplePayloads(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	originals := []*commonpb.Payload{
-		testPayload("x"),
-		testPayload("y"),
-		testPayload("z"),
-	}
-	claims, err := d.Store(storeCtx(), originals)
-	require.NoError(t, err)
-
-	restored, err := d.Retrieve(retrieveCtx(), claims)
-	require.NoError(t, err)
-	require.Len(t, restored, 3)
-	for i, orig := range originals {
-		assert.True(t, proto.Equal(orig, restored[i]))
-	}
+func TestRetrieve_ClaimMissingFields(t *testing.T) {
+	d := newDriver(t, newMemClient())
+	_, err := d.Retrieve(retrieveCtx(), []converter.StorageDriverClaim{{ClaimData: map[string]string{"key": "k"}}})
+	assert.EqualError(t, err, `claim missing field "bucket"`)
+	_, err = d.Retrieve(retrieveCtx(), []converter.StorageDriverClaim{{ClaimData: map[string]string{"bucket": "b"}}})
+	assert.EqualError(t, err, `claim missing field "key"`)
 }
 
-func TestRetrieve_HashVerificationFailure(t *testing.T) {
+func TestDelete_RemovesObjects(t *testing.T) {
 	mc := newMemClient()
 	d := newDriver(t, mc)
-
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("legit")})
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a"), testPayload("b")})
 	require.NoError(t, err)
+	require.Len(t, mc.data, 2)
 
-	// Tamper with the stored data.
-	for k := range mc.data {
-		mc.data[k] = []byte("corrupted")
-	}
+	deleter, ok := d.(converter.StorageDriverDeleter)
+	require.True(t, ok)
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+	assert.Empty(t, mc.data)
+}
 
-	_, err = d.Retrieve(retrieveCtx(), claims)
-	assert.ErrorContains(t, err, "integrity check failed [bucket=test-bucket, key=")
+// noDeleteClient is a Client that does not implement DeleterClient.
+type noDeleteClient struct {
+	Client
 }
 
-func TestRetrieve_UnsupportedHashAlgorithm(t *testing.T) {
+func TestDelete_ClientWithoutDeleter(t *testing.T) {
 	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("data")})
+	d := newDriver(t, noDeleteClient{mc})
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a")})
 	require.NoError(t, err)
 
-	// Override hash algorithm.
-	claims[0].ClaimData["hash_algorithm"] = "md5"
-
-	_, err = d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, `unsupported hash algorithm "md5"`)
+	err = d.(converter.StorageDriverDeleter).Delete(deleteCtx(), claims)
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+	assert.Len(t, mc.data, 1)
 }
 
-func TestRetrieve_MissingKey(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	claims := []converter.StorageDriverClaim{{
-		ClaimData: map[string]string{
-			"bucket":         "test-bucket",
-			"key":            "v0/d/sha256/nonexistent",
-			"hash_algorithm": "sha256",
-			"hash_value":     "abc",
-		},
-	}}
+// --- Multipart and ranged retrieval tests ---
 
-	_, err := d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, "download failed [bucket=test-bucket, key=v0/d/sha256/nonexistent, client_region=ap-southeast-2]: not found: test-bucket/v0/d/sha256/nonexistent")
+// multipartMemClient extends memClient with MultipartClient and RangeClient.
+type multipartMemClient struct {
+	*memClient
+	uploadMu     sync.Mutex
+	uploads      map[string]map[int32][]byte // uploadID -> part number -> data
+	nextUploadID int
+	partCount    atomic.Int64
+	rangeCount   atomic.Int64
+	aborted      atomic.Int64
+	partErr      error
 }
 
-func TestRetrieve_ClaimMissingBucket(t *testing.T) {
-	d := newDriver(t, newMemClient())
-	claims := []converter.StorageDriverClaim{{
-		ClaimData: map[string]string{claimKeyKey: "v0/d/sha256/abc"},
-	}}
-	_, err := d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, `claim missing field "bucket"`)
+func newMultipartMemClient() *multipartMemClient {
+	return &multipartMemClient{memClient: newMemClient(), uploads: map[string]map[int32][]byte{}}
 }
 
-func TestRetrieve_ClaimMissingKey(t *testing.T) {
-	d := newDriver(t, newMemClient())
-	claims := []converter.StorageDriverClaim{{
-		ClaimData: map[string]string{claimKeyBucket: "test-bucket"},
-	}}
-	_, err := d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, `claim missing field "key"`)
+func (m *multipartMemClient) CreateMultipartUpload(_ context.Context, _, _ string) (string, error) {
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+	m.nextUploadID++
+	id := fmt.Sprintf("upload-%d", m.nextUploadID)
+	m.uploads[id] = map[int32][]byte{}
+	return id, nil
 }
 
-func TestRetrieve_NilClaimData(t *testing.T) {
-	d := newDriver(t, newMemClient())
-	_, err := d.Retrieve(retrieveCtx(), []converter.StorageDriverClaim{{}})
-	assert.EqualError(t, err, `claim missing field "bucket"`)
+func (m *multipartMemClient) UploadPart(_ context.Context, _, _, uploadID string, partNumber int32, data []byte) (string, error) {
+	if m.partErr != nil {
+		return "", m.partErr
+	}
+	m.partCount.Add(1)
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+	m.uploads[uploadID][partNumber] = append([]byte(nil), data...)
+	return fmt.Sprintf("etag-%d", partNumber), nil
+}
+
+func (m *multipartMemClient) CompleteMultipartUpload(_ context.Context, bucket, key, uploadID string, parts []CompletedPart) error {
+	m.uploadMu.Lock()
+	var data []byte
+	for i, p := range parts {
+		if p.PartNumber != int32(i+1) || p.ETag != fmt.Sprintf("etag-%d", p.PartNumber) {
+			m.uploadMu.Unlock()
+			return fmt.Errorf("unexpected part %+v at index %d", p, i)
+		}
+		data = append(data, m.uploads[uploadID][p.PartNumber]...)
+	}
+	delete(m.uploads, uploadID)
+	m.uploadMu.Unlock()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.data[memKey(bucket, key)] = data
+	return nil
 }
 
-func TestRetrieve_GetObjectError(t *testing.T) {
-	mc := newMemClient()
-	ec := &errClient{memClient: mc, getErr: errors.New("throttled")}
-	d := newDriver(t, ec)
+func (m *multipartMemClient) AbortMultipartUpload(_ context.Context, _, _, uploadID string) error {
+	m.aborted.Add(1)
+	m.uploadMu.Lock()
+	defer m.uploadMu.Unlock()
+	delete(m.uploads, uploadID)
+	return nil
+}
 
-	// Store with real client, then retrieve with error client.
-	realDriver := newDriver(t, mc)
-	claims, err := realDriver.Store(storeCtx(), []*commonpb.Payload{testPayload("data")})
-	require.NoError(t, err)
+func (m *multipartMemClient) ObjectSize(ctx context.Context, bucket, key string) (int64, error) {
+	data, err := m.memClient.GetObject(ctx, bucket, key)
+	if err != nil {
+		return 0, err
+	}
+	return int64(len(data)), nil
+}
 
-	_, err = d.Retrieve(retrieveCtx(), claims)
-	assert.ErrorContains(t, err, "download failed [bucket=test-bucket, key=")
-	assert.ErrorContains(t, err, ", client_region=ap-southeast-2]: throttled")
+func (m *multipartMemClient) GetObjectRange(ctx context.Context, bucket, key string, offset, length int64) (io.ReadCloser, error) {
+	m.rangeCount.Add(1)
+	data, err := m.memClient.GetObject(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	return io.NopCloser(bytes.NewReader(data[offset : offset+length])), nil
 }
 
-func TestRetrieve_ClaimMissingHashAlgorithm(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
+func largePayload(size int) *commonpb.Payload {
+	data := make([]byte, size)
+	for i := range data {
+		data[i] = byte(i * 31)
+	}
+	return &commonpb.Payload{Data: data}
+}
 
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+func newMultipartDriver(t *testing.T, client Client) converter.StorageDriver {
+	t.Helper()
+	d, err := NewDriver(Options{
+		Client:             client,
+		Bucket:             StaticBucket("test-bucket"),
+		MultipartThreshold: minPartSize,
+		PartSize:           minPartSize,
+	})
 	require.NoError(t, err)
-	delete(claims[0].ClaimData, claimKeyHashAlgorithm)
-
-	_, err = d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, `claim missing field "hash_algorithm"`)
+	return d
 }
 
-func TestRetrieve_ClaimMissingHashValue(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-
-	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+func TestNewS3StorageDriver_MultipartDefaults(t *testing.T) {
+	store, err := newObjectStore(Options{Client: newMultipartMemClient(), Bucket: StaticBucket("b")})
 	require.NoError(t, err)
-	delete(claims[0].ClaimData, claimKeyHashValue)
-
-	_, err = d.Retrieve(retrieveCtx(), claims)
-	assert.EqualError(t, err, `claim missing field "hash_value"`)
+	assert.NotNil(t, store.multipartClient)
+	assert.Equal(t, 50*1024*1024, store.maxPayloadSize)
+	assert.Equal(t, 32*1024*1024, store.multipartThreshold)
+	assert.Equal(t, 16*1024*1024, store.partSize)
+	assert.Equal(t, 4, store.partConcurrency)
 }
 
-// --- Key generation tests ---
-
-func TestObjectKey_NoTarget(t *testing.T) {
-	assert.Equal(t, "v0/d/sha256/abc123", objectKey(nil, "abc123"))
+func TestNewS3StorageDriver_PartSizeTooSmall(t *testing.T) {
+	_, err := NewDriver(Options{Client: newMemClient(), Bucket: StaticBucket("b"), PartSize: 1024})
+	assert.EqualError(t, err, "PartSize must be at least 5242880, got 1024")
 }
 
-func TestObjectKey_WorkflowInfo(t *testing.T) {
-	target := converter.StorageDriverWorkflowInfo{
-		Namespace:    "default",
-		WorkflowType: "MyWorkflow",
-		WorkflowID:   "wf-123",
-		RunID:        "run-456",
-	}
-	assert.Equal(t,
-		"v0/ns/default/wt/MyWorkflow/wi/wf-123/ri/run-456/d/sha256/abc123",
-		objectKey(target, "abc123"),
-	)
-}
-
-func TestObjectKey_ActivityInfo(t *testing.T) {
-	target := converter.StorageDriverActivityInfo{
-		Namespace:    "default",
-		ActivityType: "MyActivity",
-		ActivityID:   "act-789",
-		RunID:        "run-abc",
-	}
-	assert.Equal(t,
-		"v0/ns/default/at/MyActivity/ai/act-789/ri/run-abc/d/sha256/abc123",
-		objectKey(target, "abc123"),
-	)
+func TestNewS3StorageDriver_TooManyParts(t *testing.T) {
+	_, err := NewDriver(Options{
+		Client:         newMultipartMemClient(),
+		Bucket:         StaticBucket("b"),
+		PartSize:       minPartSize,
+		MaxPayloadSize: minPartSize*maxPartCount + 1,
+	})
+	assert.ErrorContains(t, err, "exceeds 10000 parts")
 }
 
-func TestObjectKey_WorkflowInfo_EmptyFields(t *testing.T) {
-	// Empty strings should fall back to "null" in each segment.
-	target := converter.StorageDriverWorkflowInfo{
-		Namespace: "my-ns",
-		// WorkflowType, WorkflowID, RunID intentionally empty
-	}
-	assert.Equal(t,
-		"v0/ns/my-ns/wt/null/wi/null/ri/null/d/sha256/abc123",
-		objectKey(target, "abc123"),
-	)
-}
+func TestStore_Multipart_RoundTrip(t *testing.T) {
+	mc := newMultipartMemClient()
+	d := newMultipartDriver(t, mc)
+	small := testPayload("small")
+	large := largePayload(2*minPartSize + 1000)
 
-func TestObjectKey_ActivityInfo_EmptyFields(t *testing.T) {
-	target := converter.StorageDriverActivityInfo{
-		Namespace: "my-ns",
-		// ActivityType, ActivityID, RunID intentionally empty
-	}
-	assert.Equal(t,
-		"v0/ns/my-ns/at/null/ai/null/ri/null/d/sha256/abc123",
-		objectKey(target, "abc123"),
-	)
-}
-
-func TestObjectKey_WorkflowInfo_SpecialChars(t *testing.T) {
-	// Slashes, spaces, and other special characters must be percent-encoded.
-	target := converter.StorageDriverWorkflowInfo{
-		Namespace:    "my namespace",
-		WorkflowType: "my/workflow",
-		WorkflowID:   "wf id+1",
-		RunID:        "run=abc",
-	}
-	key := objectKey(target, "abc123")
-	assert.Equal(t,
-		"v0/ns/my%20namespace/wt/my%2Fworkflow/wi/wf%20id+1/ri/run=abc/d/sha256/abc123",
-		key,
-	)
-}
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{small, large})
+	require.NoError(t, err)
+	_, chunked := converter.StorageChunkManifestKey(claims[0])
+	assert.False(t, chunked, "small payloads are not chunked")
+	assert.Equal(t, int64(3), mc.partCount.Load())
+	assert.Equal(t, int64(2), mc.putCount.Load(), "only the small payload and the chunk manifest use PutObject")
 
-func TestSha256Hex(t *testing.T) {
-	data := []byte("test")
-	h := sha256.Sum256(data)
-	expected := hex.EncodeToString(h[:])
-	assert.Equal(t, expected, sha256Hex(data))
+	manifestKey, chunked := converter.StorageChunkManifestKey(claims[1])
+	require.True(t, chunked)
+	assert.Equal(t, claims[1].ClaimData["key"]+".chunks-5242880", manifestKey)
+	assert.Len(t, claims[1].ClaimData, 6, "chunk hashes are kept out of the claim")
+
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(small, got[0]))
+	assert.True(t, proto.Equal(large, got[1]))
+	assert.Equal(t, int64(3), mc.rangeCount.Load())
 }
 
-// --- Store with target context tests ---
+func TestStore_Multipart_PartErrorAborts(t *testing.T) {
+	mc := newMultipartMemClient()
+	mc.partErr = errors.New("slow down")
+	d := newMultipartDriver(t, mc)
 
-func TestStore_WithWorkflowTarget(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-	p := testPayload("workflow payload")
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1)})
+	assert.ErrorContains(t, err, "upload failed")
+	assert.ErrorContains(t, err, "slow down")
+	assert.Equal(t, int64(1), mc.aborted.Load())
+	assert.Empty(t, mc.uploads)
+}
 
-	target := converter.StorageDriverWorkflowInfo{
-		Namespace:    "default",
-		WorkflowType: "MyWorkflow",
-		WorkflowID:   "wf-123",
-		RunID:        "run-456",
-	}
-	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{p})
+func TestRetrieve_Ranged_ChunkIntegrityFailure(t *testing.T) {
+	mc := newMultipartMemClient()
+	d := newMultipartDriver(t, mc)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1000)})
 	require.NoError(t, err)
-	require.Len(t, claims, 1)
 
-	key := claims[0].ClaimData["key"]
-	assert.Contains(t, key, "v0/ns/default/wt/MyWorkflow/wi/wf-123/ri/run-456/d/sha256/")
-}
+	// Corrupt one byte in the second chunk.
+	key := memKey(claims[0].ClaimData["bucket"], claims[0].ClaimData["key"])
+	mc.data[key][minPartSize+10] ^= 0xff
 
-func TestStore_WithActivityTarget(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-	p := testPayload("activity payload")
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "chunk 1 integrity check failed")
+}
 
-	target := converter.StorageDriverActivityInfo{
-		Namespace:    "default",
-		ActivityType: "MyActivity",
-		ActivityID:   "act-789",
-		RunID:        "run-abc",
-	}
-	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{p})
+func TestRetrieve_Ranged_SizeMismatch(t *testing.T) {
+	mc := newMultipartMemClient()
+	d := newMultipartDriver(t, mc)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1000)})
 	require.NoError(t, err)
-	require.Len(t, claims, 1)
 
-	key := claims[0].ClaimData["key"]
-	assert.Contains(t, key, "v0/ns/default/at/MyActivity/ai/act-789/ri/run-abc/d/sha256/")
-}
+	key := memKey(claims[0].ClaimData["bucket"], claims[0].ClaimData["key"])
+	mc.data[key] = append(mc.data[key], 0)
 
-func TestStore_RoundTrip_WithWorkflowTarget(t *testing.T) {
-	mc := newMemClient()
-	d := newDriver(t, mc)
-	original := testPayload("round-trip with target")
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "does not match chunk manifest size")
+	assert.Equal(t, int64(0), mc.rangeCount.Load())
+}
 
-	target := converter.StorageDriverWorkflowInfo{
-		Namespace:    "default",
-		WorkflowType: "MyWorkflow",
-		WorkflowID:   "wf-123",
-		RunID:        "run-456",
-	}
-	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{original})
+func TestRetrieve_Ranged_ExceedsMaxPayloadSize(t *testing.T) {
+	mc := newMultipartMemClient()
+	claims, err := newMultipartDriver(t, mc).Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1000)})
 	require.NoError(t, err)
 
-	restored, err := d.Retrieve(retrieveCtx(), claims)
+	d, err := NewDriver(Options{
+		Client:             mc,
+		Bucket:             StaticBucket("test-bucket"),
+		MaxPayloadSize:     minPartSize,
+		MultipartThreshold: minPartSize,
+		PartSize:           minPartSize,
+	})
 	require.NoError(t, err)
-	require.Len(t, restored, 1)
-	assert.True(t, proto.Equal(original, restored[0]))
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, fmt.Sprintf("exceeds maximum %d", minPartSize))
+	assert.Equal(t, int64(0), mc.rangeCount.Load())
 }
 
-func TestStore_DifferentTargets_SamePayload_DifferentKeys(t *testing.T) {
-	// The same payload stored under different targets produces different keys.
-	mc := newMemClient()
-	d := newDriver(t, mc)
-	p := testPayload("shared payload")
-
-	wfTarget := converter.StorageDriverWorkflowInfo{Namespace: "ns", WorkflowID: "wf-1", RunID: "run-1"}
-	actTarget := converter.StorageDriverActivityInfo{Namespace: "ns", ActivityID: "act-1", RunID: "run-1"}
-
-	wfClaims, err := d.Store(storeCtxWithTarget(wfTarget), []*commonpb.Payload{p})
+func TestRetrieve_Ranged_ManifestTampered(t *testing.T) {
+	mc := newMultipartMemClient()
+	d := newMultipartDriver(t, mc)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1000)})
 	require.NoError(t, err)
 
-	actClaims, err := d.Store(storeCtxWithTarget(actTarget), []*commonpb.Payload{p})
-	require.NoError(t, err)
+	manifestKey, _ := converter.StorageChunkManifestKey(claims[0])
+	mc.data[memKey(claims[0].ClaimData["bucket"], manifestKey)] = []byte(`{"size":1,"chunk_size":1,"hashes":[]}`)
 
-	assert.NotEqual(t, wfClaims[0].ClaimData["key"], actClaims[0].ClaimData["key"])
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "chunk manifest integrity check failed")
 }
 
-func TestDescribeClient_EmptyDescribe(t *testing.T) {
-	mc := newMemClient()
-	mc.describe = map[string]string{}
-	assert.Equal(t, "", describeClient(mc))
-}
+func TestDelete_Multipart_RemovesChunkManifest(t *testing.T) {
+	mc := newMultipartMemClient()
+	d := newMultipartDriver(t, mc)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{largePayload(minPartSize + 1000)})
+	require.NoError(t, err)
+	require.Len(t, mc.data, 2)
 
-func TestDescribeClient_NilDescribe(t *testing.T) {
-	mc := newMemClient()
-	mc.describe = nil
-	assert.Nil(t, mc.Describe())
-	assert.Equal(t, "", describeClient(mc))
+	require.NoError(t, d.(converter.StorageDriverDeleter).Delete(deleteCtx(), claims))
+	assert.Empty(t, mc.data)
 }
 
-func TestDescribeClient_SingleEntry(t *testing.T) {
-	mc := newMemClient()
-	mc.describe = map[string]string{"client_region": "us-west-2"}
-	assert.Equal(t, ", client_region=us-west-2", describeClient(mc))
-}
+func TestRetrieve_ChunkedClaim_WithoutRangeClient(t *testing.T) {
+	mc := newMultipartMemClient()
+	large := largePayload(minPartSize + 1000)
+	claims, err := newMultipartDriver(t, mc).Store(storeCtx(), []*commonpb.Payload{large})
+	require.NoError(t, err)
 
-func TestDescribeClient_MultipleEntries(t *testing.T) {
-	mc := newMemClient()
-	mc.describe = map[string]string{"client_region": "us-west-2", "foo": "bar"}
-	out := describeClient(mc)
-	assert.Contains(t, out, ", client_region=us-west-2")
-	assert.Contains(t, out, ", foo=bar")
+	// A driver whose client cannot do ranged reads falls back to a single GET.
+	d := newDriver(t, mc.memClient)
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(large, got[0]))
+	assert.Equal(t, int64(0), mc.rangeCount.Load())
 }
@@ -0,0 +1,75 @@
+// Package azuresdk provides an azblobdriver.Client implementation backed by
+// the Azure SDK for Go. Import this package alongside azblobdriver when using
+// the official Azure Blob Storage client; supply a custom azblobdriver.Client
+// implementation otherwise.
+//
+// NOTE: Experimental
+package azuresdk
+
+import (
+	"bytes"
+	"context"
+	"io"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/streaming"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob/bloberror"
+	"go.temporal.io/sdk/contrib/azure/azblobdriver"
+)
+
+type azblobClient struct {
+	client *azblob.Client
+}
+
+// NewClient creates an azblobdriver.Client backed by an Azure SDK for Go blob
+// client.
+//
+// NOTE: Experimental
+func NewClient(client *azblob.Client) azblobdriver.Client {
+	return &azblobClient{client: client}
+}
+
+func (c *azblobClient) PutBlob(ctx context.Context, container, name string, data []byte) error {
+	// The payload is already in memory, so upload it with a single Put Blob
+	// request rather than staging blocks.
+	blob := c.client.ServiceClient().NewContainerClient(container).NewBlockBlobClient(name)
+	_, err := blob.Upload(ctx, streaming.NopCloser(bytes.NewReader(data)), nil)
+	return err
+}
+
+func (c *azblobClient) BlobExists(ctx context.Context, container, name string) (bool, error) {
+	blob := c.client.ServiceClient().NewContainerClient(container).NewBlobClient(name)
+	_, err := blob.GetProperties(ctx, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *azblobClient) GetBlob(ctx context.Context, container, name string) ([]byte, error) {
+	resp, err := c.client.DownloadStream(ctx, container, name, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	return io.ReadAll(resp.Body)
+}
+
+func (c *azblobClient) DeleteBlob(ctx context.Context, container, name string) error {
+	_, err := c.client.DeleteBlob(ctx, container, name, nil)
+	if bloberror.HasCode(err, bloberror.BlobNotFound) {
+		return nil
+	}
+	return err
+}
+
+func (c *azblobClient) Describe() map[string]string {
+	url := c.client.URL()
+	if url == "" {
+		return nil
+	}
+	return map[string]string{"client_endpoint": url}
+}
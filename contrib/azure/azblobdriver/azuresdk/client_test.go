@@ -0,0 +1,219 @@
+package azuresdk_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/contrib/azure/azblobdriver"
+	"go.temporal.io/sdk/contrib/azure/azblobdriver/azuresdk"
+	"go.temporal.io/sdk/converter"
+)
+
+// fakeBlobService implements the subset of the Azure Blob Storage REST API
+// used by the client: Put Blob, Get Blob Properties, Get Blob and Delete
+// Blob, with path-style addressing of /<container>/<blob>.
+type fakeBlobService struct {
+	mu         sync.Mutex
+	containers map[string]map[string][]byte
+}
+
+func (f *fakeBlobService) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	container, name, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/"), "/")
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	blobs, ok := f.containers[container]
+	if !ok {
+		writeError(w, http.StatusNotFound, "ContainerNotFound")
+		return
+	}
+	switch r.Method {
+	case http.MethodPut:
+		data, err := io.ReadAll(r.Body)
+		if err != nil {
+			writeError(w, http.StatusBadRequest, "InvalidInput")
+			return
+		}
+		blobs[name] = data
+		w.WriteHeader(http.StatusCreated)
+	case http.MethodHead, http.MethodGet:
+		data, ok := blobs[name]
+		if !ok {
+			writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+		w.Header().Set("x-ms-blob-type", "BlockBlob")
+		w.WriteHeader(http.StatusOK)
+		if r.Method == http.MethodGet {
+			_, _ = w.Write(data)
+		}
+	case http.MethodDelete:
+		if _, ok := blobs[name]; !ok {
+			writeError(w, http.StatusNotFound, "BlobNotFound")
+			return
+		}
+		delete(blobs, name)
+		w.WriteHeader(http.StatusAccepted)
+	default:
+		writeError(w, http.StatusMethodNotAllowed, "UnsupportedHttpVerb")
+	}
+}
+
+func writeError(w http.ResponseWriter, status int, code string) {
+	w.Header().Set("x-ms-error-code", code)
+	w.WriteHeader(status)
+}
+
+// newFakeBlobStorage starts an in-process fake Blob service and returns an
+// azblobdriver.Client backed by a real Azure SDK client pointing at it.
+func newFakeBlobStorage(t *testing.T, containers ...string) azblobdriver.Client {
+	t.Helper()
+	service := &fakeBlobService{containers: map[string]map[string][]byte{}}
+	for _, c := range containers {
+		service.containers[c] = map[string][]byte{}
+	}
+	ts := httptest.NewServer(service)
+	t.Cleanup(ts.Close)
+
+	client, err := azblob.NewClientWithNoCredential(ts.URL+"/", nil)
+	require.NoError(t, err)
+	return azuresdk.NewClient(client)
+}
+
+func TestAzureSdkClient_PutGetRoundTrip(t *testing.T) {
+	client := newFakeBlobStorage(t, "test-container")
+	ctx := context.Background()
+
+	data := []byte("hello azure")
+	err := client.PutBlob(ctx, "test-container", "my/name", data)
+	require.NoError(t, err)
+
+	got, err := client.GetBlob(ctx, "test-container", "my/name")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestAzureSdkClient_BlobExists(t *testing.T) {
+	client := newFakeBlobStorage(t, "test-container")
+	ctx := context.Background()
+
+	exists, err := client.BlobExists(ctx, "test-container", "missing-name")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = client.PutBlob(ctx, "test-container", "present-name", []byte("data"))
+	require.NoError(t, err)
+
+	exists, err = client.BlobExists(ctx, "test-container", "present-name")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestAzureSdkClient_GetBlob_NotFound(t *testing.T) {
+	client := newFakeBlobStorage(t, "test-container")
+
+	_, err := client.GetBlob(context.Background(), "test-container", "no-such-name")
+	assert.ErrorContains(t, err, "BlobNotFound")
+}
+
+func TestAzureSdkClient_PutBlob_ContainerNotFound(t *testing.T) {
+	client := newFakeBlobStorage(t)
+
+	err := client.PutBlob(context.Background(), "no-such-container", "my/name", []byte("data"))
+	assert.ErrorContains(t, err, "ContainerNotFound")
+}
+
+func TestAzureSdkClient_BlobExists_ContainerNotFound(t *testing.T) {
+	client := newFakeBlobStorage(t)
+
+	// Unlike a missing blob, a missing container is reported as an error.
+	_, err := client.BlobExists(context.Background(), "no-such-container", "my/name")
+	assert.ErrorContains(t, err, "ContainerNotFound")
+}
+
+func TestAzureSdkClient_DeleteBlob(t *testing.T) {
+	client := newFakeBlobStorage(t, "test-container")
+	ctx := context.Background()
+
+	err := client.PutBlob(ctx, "test-container", "my/name", []byte("data"))
+	require.NoError(t, err)
+
+	err = client.DeleteBlob(ctx, "test-container", "my/name")
+	require.NoError(t, err)
+
+	exists, err := client.BlobExists(ctx, "test-container", "my/name")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Deleting a missing blob is not an error.
+	err = client.DeleteBlob(ctx, "test-container", "my/name")
+	assert.NoError(t, err)
+}
+
+func TestAzureSdkClient_LargeBlob(t *testing.T) {
+	client := newFakeBlobStorage(t, "test-container")
+	ctx := context.Background()
+
+	// 1 MiB of data.
+	data := make([]byte, 1024*1024)
+	for i := range data {
+		data[i] = byte(i % 256)
+	}
+
+	err := client.PutBlob(ctx, "test-container", "large-blob", data)
+	require.NoError(t, err)
+
+	got, err := client.GetBlob(ctx, "test-container", "large-blob")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestAzureSdkClient_Describe_ReturnsClientEndpoint(t *testing.T) {
+	client := newFakeBlobStorage(t)
+	assert.Contains(t, client.Describe()["client_endpoint"], "http://127.0.0.1:")
+}
+
+// TestAzureSdkClient_FullDriverRoundTrip exercises the Azure Blob Storage
+// driver end-to-end through the fake Blob service.
+func TestAzureSdkClient_FullDriverRoundTrip(t *testing.T) {
+	client := newFakeBlobStorage(t, "driver-container")
+
+	d, err := azblobdriver.NewDriver(azblobdriver.Options{
+		Client:    client,
+		Container: azblobdriver.StaticContainer("driver-container"),
+	})
+	require.NoError(t, err)
+
+	payloads := []*commonpb.Payload{
+		{Metadata: map[string][]byte{"encoding": []byte("binary/plain")}, Data: []byte("integration-test-1")},
+		{Metadata: map[string][]byte{"encoding": []byte("binary/plain")}, Data: []byte("integration-test-2")},
+	}
+
+	claims, err := d.Store(
+		converter.StorageDriverStoreContext{Context: context.Background()},
+		payloads,
+	)
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+
+	restored, err := d.Retrieve(
+		converter.StorageDriverRetrieveContext{Context: context.Background()},
+		claims,
+	)
+	require.NoError(t, err)
+	require.Len(t, restored, 2)
+
+	for i := range payloads {
+		assert.Equal(t, payloads[i].Data, restored[i].Data)
+	}
+}
@@ -0,0 +1,36 @@
+package azblobdriver
+
+import "context"
+
+// Client is the interface that the driver uses to interact with Azure Blob
+// Storage. It covers the operations the driver needs: put, existence check,
+// get, and delete. Use [go.temporal.io/sdk/contrib/azure/azblobdriver/azuresdk.NewClient]
+// to obtain an implementation backed by the Azure SDK for Go, or supply a
+// custom implementation for testing.
+//
+// NOTE: Experimental
+type Client interface {
+	// PutBlob uploads data as a block blob to the given container and blob
+	// name. If a blob already exists with that name it should be overwritten.
+	// Implementations must be safe to call concurrently for different names.
+	PutBlob(ctx context.Context, container, name string, data []byte) error
+
+	// BlobExists reports whether a blob exists with the given container and
+	// name. It should return (false, nil) when the blob is absent, and a
+	// non-nil error only when the existence of the blob cannot be determined
+	// (e.g. a network or permission failure).
+	BlobExists(ctx context.Context, container, name string) (bool, error)
+
+	// GetBlob downloads and returns the data stored in the given container
+	// under name. It must return a non-nil error if the blob does not exist.
+	GetBlob(ctx context.Context, container, name string) ([]byte, error)
+
+	// DeleteBlob removes the blob stored in the given container under name.
+	// It should return nil if the blob does not exist.
+	DeleteBlob(ctx context.Context, container, name string) error
+
+	// Describe returns diagnostic metadata about the client configuration,
+	// such as {"client_account": "myaccount"}, that the driver appends to
+	// error messages. Return nil or an empty map if no metadata is available.
+	Describe() map[string]string
+}
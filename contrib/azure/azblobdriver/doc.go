@@ -0,0 +1,17 @@
+// Package azblobdriver provides an Azure Blob Storage-backed
+// [go.temporal.io/sdk/converter.StorageDriver] for the Temporal Go SDK's
+// external payload storage system. Large payloads are offloaded to Azure Blob
+// Storage using content-addressable blob names derived from their SHA-256
+// hash.
+//
+// # Usage
+//
+// Construct a driver using [NewDriver] with an [Options] struct. The [Client]
+// field accepts any implementation of the [Client] interface; use
+// [go.temporal.io/sdk/contrib/azure/azblobdriver/azuresdk.NewClient] to wrap
+// an Azure SDK for Go blob client. The [Options.Container] field accepts a
+// [ContainerFunc] that resolves the target container per payload; use
+// [StaticContainer] for a fixed name.
+//
+// NOTE: Experimental
+package azblobdriver
@@ -0,0 +1,121 @@
+package azblobdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+)
+
+const (
+	defaultMaxPayloadSize = 50 * 1024 * 1024 // 50 MiB
+	driverType            = "azure.azblobdriver"
+	defaultDriverName     = "azure.azblobdriver"
+
+	claimKeyContainer = "container"
+	claimKeyName      = "name"
+)
+
+// ContainerFunc resolves the target Azure Blob Storage container for a given
+// payload. Use StaticContainer for a fixed container name.
+//
+// NOTE: Experimental
+type ContainerFunc func(ctx converter.StorageDriverStoreContext, payload *commonpb.Payload) string
+
+// StaticContainer returns a ContainerFunc that always returns the given
+// container name.
+//
+// NOTE: Experimental
+func StaticContainer(name string) ContainerFunc {
+	return func(_ converter.StorageDriverStoreContext, _ *commonpb.Payload) string { return name }
+}
+
+// Options configures the Azure Blob Storage storage driver.
+//
+// NOTE: Experimental
+type Options struct {
+	// Client is the Azure Blob Storage client used for storage operations.
+	// Required.
+	Client Client
+
+	// Container resolves the target container for each payload. Required.
+	// Use StaticContainer("my-container") for a fixed container.
+	Container ContainerFunc
+
+	// DriverName is a stable, unique identifier for this driver instance.
+	// Defaults to "azure.azblobdriver".
+	DriverName string
+
+	// MaxPayloadSize is the maximum serialized payload size in bytes that
+	// the driver will accept. Defaults to 50 MiB.
+	MaxPayloadSize int
+}
+
+// NewDriver creates a new Azure Blob Storage StorageDriver with the given
+// options. The driver stores payloads in Azure Blob Storage using
+// content-addressable blob names based on SHA-256 hashes, and implements
+// converter.StorageDriverDeleter.
+//
+// NOTE: Experimental
+func NewDriver(opts Options) (converter.StorageDriver, error) {
+	if opts.Client == nil {
+		return nil, errors.New("Client is required")
+	}
+	if opts.Container == nil {
+		return nil, errors.New("Container is required")
+	}
+	name := opts.DriverName
+	if name == "" {
+		name = defaultDriverName
+	}
+	maxSize := opts.MaxPayloadSize
+	if maxSize == 0 {
+		maxSize = defaultMaxPayloadSize
+	}
+	if maxSize < 0 {
+		return nil, fmt.Errorf("MaxPayloadSize must be positive, got %d", maxSize)
+	}
+	return extstore.NewObjectStoreDriver(extstore.ObjectStoreDriverOptions{
+		Store:             objectStore{client: opts.Client},
+		Container:         opts.Container,
+		DriverName:        name,
+		DriverType:        driverType,
+		MaxPayloadSize:    maxSize,
+		ContainerClaimKey: claimKeyContainer,
+		NameClaimKey:      claimKeyName,
+	}), nil
+}
+
+// objectStore adapts a Client to the object store the driver is built on.
+type objectStore struct {
+	client Client
+}
+
+// Compile-time checks that objectStore supports storage and deletion.
+var (
+	_ extstore.ObjectStore        = objectStore{}
+	_ extstore.ObjectStoreDeleter = objectStore{}
+)
+
+func (s objectStore) ObjectExists(ctx context.Context, container, name string) (bool, error) {
+	return s.client.BlobExists(ctx, container, name)
+}
+
+func (s objectStore) PutObject(ctx context.Context, container, name string, data []byte) error {
+	return s.client.PutBlob(ctx, container, name, data)
+}
+
+func (s objectStore) GetObject(ctx context.Context, container, name string, _ converter.StorageDriverClaim) ([]byte, error) {
+	return s.client.GetBlob(ctx, container, name)
+}
+
+func (s objectStore) DeleteObject(ctx context.Context, container, name string, _ converter.StorageDriverClaim) error {
+	return s.client.DeleteBlob(ctx, container, name)
+}
+
+func (s objectStore) Describe() map[string]string {
+	return s.client.Describe()
+}
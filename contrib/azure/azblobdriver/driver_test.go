@@ -0,0 +1,166 @@
+package azblobdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// recordingClient is an in-memory Client that records the calls made to it.
+// The storage logic shared by the object store drivers is tested in
+// go.temporal.io/sdk/internal/extstore, so these tests only cover the use of
+// the options and of Client.
+type recordingClient struct {
+	mu        sync.Mutex
+	blobs     map[string][]byte // key: "container/name"
+	calls     []string
+	existsErr error
+	putErr    error
+}
+
+func (c *recordingClient) record(method, container, name string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, method+" "+container)
+	if c.blobs == nil {
+		c.blobs = map[string][]byte{}
+	}
+	return container + "/" + name
+}
+
+func (c *recordingClient) PutBlob(_ context.Context, container, name string, data []byte) error {
+	key := c.record("PutBlob", container, name)
+	if c.putErr != nil {
+		return c.putErr
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.blobs[key] = data
+	return nil
+}
+
+func (c *recordingClient) BlobExists(_ context.Context, container, name string) (bool, error) {
+	key := c.record("BlobExists", container, name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.blobs[key]
+	return ok, c.existsErr
+}
+
+func (c *recordingClient) GetBlob(_ context.Context, container, name string) ([]byte, error) {
+	key := c.record("GetBlob", container, name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	data, ok := c.blobs[key]
+	if !ok {
+		return nil, fmt.Errorf("blob %s not found", key)
+	}
+	return data, nil
+}
+
+func (c *recordingClient) DeleteBlob(_ context.Context, container, name string) error {
+	key := c.record("DeleteBlob", container, name)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.blobs, key)
+	return nil
+}
+
+func (c *recordingClient) Describe() map[string]string {
+	return map[string]string{"client_account": "testaccount"}
+}
+
+func TestNewDriver_Options(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		opts Options
+		err  string
+	}{
+		{name: "missing client", opts: Options{Container: StaticContainer("c")}, err: "Client is required"},
+		{name: "missing container", opts: Options{Client: &recordingClient{}}, err: "Container is required"},
+		{
+			name: "negative max payload size",
+			opts: Options{Client: &recordingClient{}, Container: StaticContainer("c"), MaxPayloadSize: -1},
+			err:  "MaxPayloadSize must be positive, got -1",
+		},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := NewDriver(tc.opts)
+			assert.EqualError(t, err, tc.err)
+		})
+	}
+
+	d, err := NewDriver(Options{Client: &recordingClient{}, Container: StaticContainer("c")})
+	require.NoError(t, err)
+	typedDriver, ok := d.(*extstore.ObjectStoreDriver)
+	require.True(t, ok, "expected *extstore.ObjectStoreDriver, got %T", d)
+	assert.Equal(t, "azure.azblobdriver", typedDriver.Name())
+	assert.Equal(t, "azure.azblobdriver", typedDriver.Type())
+	assert.Equal(t, 50*1024*1024, typedDriver.MaxPayloadSize())
+}
+
+func TestDriver_UsesContainerAndBlobCalls(t *testing.T) {
+	client := &recordingClient{}
+	d, err := NewDriver(Options{
+		Client: client,
+		Container: func(ctx converter.StorageDriverStoreContext, _ *commonpb.Payload) string {
+			if info, ok := ctx.Target.(converter.StorageDriverWorkflowInfo); ok {
+				return "ns-" + info.Namespace
+			}
+			return "default"
+		},
+	})
+	require.NoError(t, err)
+	p := &commonpb.Payload{Data: []byte("blob")}
+
+	claims, err := d.Store(converter.StorageDriverStoreContext{
+		Context: context.Background(),
+		Target:  converter.StorageDriverWorkflowInfo{Namespace: "orders"},
+	}, []*commonpb.Payload{p})
+	require.NoError(t, err)
+	assert.Equal(t, "ns-orders", claims[0].ClaimData["container"])
+	assert.Contains(t, client.blobs, "ns-orders/"+claims[0].ClaimData["name"])
+
+	retrieved, err := d.Retrieve(converter.StorageDriverRetrieveContext{Context: context.Background()}, claims)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(p, retrieved[0]))
+	require.NoError(t, d.(converter.StorageDriverDeleter).Delete(converter.StorageDriverDeleteContext{Context: context.Background()}, claims))
+	assert.Empty(t, client.blobs)
+
+	assert.Equal(t, []string{
+		"BlobExists ns-orders",
+		"PutBlob ns-orders",
+		"GetBlob ns-orders",
+		"DeleteBlob ns-orders",
+	}, client.calls)
+}
+
+func TestDriver_ClientErrors(t *testing.T) {
+	storeCtx := converter.StorageDriverStoreContext{Context: context.Background()}
+	payloads := []*commonpb.Payload{{Data: []byte("x")}}
+
+	existsErr := errors.New("authorization failure")
+	d, err := NewDriver(Options{Client: &recordingClient{existsErr: existsErr}, Container: StaticContainer("c")})
+	require.NoError(t, err)
+	_, err = d.Store(storeCtx, payloads)
+	assert.ErrorIs(t, err, existsErr)
+	assert.ErrorContains(t, err, "existence check failed [container=c, name=")
+	assert.ErrorContains(t, err, ", client_account=testaccount]")
+
+	putErr := errors.New("container not found")
+	d, err = NewDriver(Options{Client: &recordingClient{putErr: putErr}, Container: StaticContainer("c")})
+	require.NoError(t, err)
+	_, err = d.Store(storeCtx, payloads)
+	assert.ErrorIs(t, err, putErr)
+	assert.ErrorContains(t, err, "upload failed [container=c, name=")
+}
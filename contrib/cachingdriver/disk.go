@@ -0,0 +1,154 @@
+package cachingdriver
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+const tempFilePrefix = ".tmp-"
+
+// diskCache is the optional second cache tier. Each entry is a file beneath
+// dir named after its cache key, holding the SHA-256 checksum of the data
+// followed by the data itself; an in-memory LRU index tracks their sizes and
+// recency so that the tier stays within its bound.
+type diskCache struct {
+	dir   string
+	index *lru
+}
+
+// newDiskCache creates the disk tier, creating dir if needed and indexing any
+// entries left behind by a previous process, oldest first.
+func newDiskCache(dir string, maxBytes int64) (*diskCache, error) {
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, err
+	}
+	type existing struct {
+		key     string
+		size    int64
+		modTime time.Time
+	}
+	var files []existing
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if strings.HasPrefix(d.Name(), tempFilePrefix) {
+			// Left behind by an interrupted write.
+			_ = os.Remove(path)
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		files = append(files, existing{key: filepath.ToSlash(rel), size: info.Size(), modTime: info.ModTime()})
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].modTime.Before(files[j].modTime) })
+
+	c := &diskCache{dir: dir, index: newLRU(maxBytes)}
+	for _, f := range files {
+		if f.size > maxBytes {
+			_ = os.Remove(c.path(f.key))
+			continue
+		}
+		c.removeFiles(c.index.add(lruEntry{key: f.key, size: f.size}))
+	}
+	return c, nil
+}
+
+// get returns the cached bytes for key. A file that has disappeared or cannot
+// be read is dropped from the index, and one whose contents no longer match
+// their checksum is deleted; both are reported as a miss.
+func (c *diskCache) get(key string) ([]byte, bool) {
+	if _, ok := c.index.get(key); !ok {
+		return nil, false
+	}
+	file, err := os.ReadFile(c.path(key))
+	if err != nil {
+		c.index.remove(key)
+		return nil, false
+	}
+	if len(file) < sha256.Size {
+		c.remove(key)
+		return nil, false
+	}
+	checksum, data := file[:sha256.Size], file[sha256.Size:]
+	if actual := sha256.Sum256(data); !bytes.Equal(checksum, actual[:]) {
+		c.remove(key)
+		return nil, false
+	}
+	return data, true
+}
+
+// add writes data for key and evicts the least recently used files to stay
+// within bound. Write failures are not reported; the entry is simply not
+// cached.
+func (c *diskCache) add(key string, data []byte) {
+	size := int64(sha256.Size + len(data))
+	if size > c.index.maxBytes {
+		return
+	}
+	checksum := sha256.Sum256(data)
+	if err := c.writeFile(c.path(key), append(checksum[:], data...)); err != nil {
+		return
+	}
+	c.removeFiles(c.index.add(lruEntry{key: key, size: size}))
+}
+
+// remove deletes the entry for key.
+func (c *diskCache) remove(key string) {
+	if c.index.remove(key) {
+		_ = os.Remove(c.path(key))
+	}
+}
+
+func (c *diskCache) removeFiles(entries []lruEntry) {
+	for _, e := range entries {
+		_ = os.Remove(c.path(e.key))
+	}
+}
+
+func (c *diskCache) path(key string) string {
+	return filepath.Join(c.dir, filepath.FromSlash(key))
+}
+
+// writeFile atomically writes data to path by writing a temporary file in the
+// same directory and renaming it into place, so concurrent readers never
+// observe a partially written entry.
+func (c *diskCache) writeFile(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, tempFilePrefix+"*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	_, err = tmp.Write(data)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpName, path)
+	}
+	if err != nil {
+		_ = os.Remove(tmpName)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+// Package cachingdriver provides a [go.temporal.io/sdk/converter.StorageDriver]
+// wrapper that caches retrieved payloads for the Temporal Go SDK's external
+// payload storage system.
+//
+// Workers call StorageDriver.Retrieve for every stored payload each time a
+// workflow is replayed, for example after it has been evicted from the sticky
+// cache. Wrapping a remote driver such as the S3 driver with this package keeps
+// recently retrieved payloads in a byte-bounded in-memory LRU cache and,
+// optionally, in a second LRU tier on local disk, so repeated replays do not
+// go back to the remote store.
+//
+// # Usage
+//
+// Construct a driver using [NewDriver] with an [Options] struct whose
+// [Options.Driver] field is the driver to wrap, and register the result in
+// place of the wrapped driver. The wrapper reports the wrapped driver's name
+// and type, so payloads stored before the cache was introduced remain
+// retrievable.
+//
+// Entries are keyed by the content hash recorded in each claim under the
+// "hash_algorithm" and "hash_value" fields, as written by the built-in
+// drivers, scoped to the name of the wrapped driver and to the "codec_id"
+// field recorded by [go.temporal.io/sdk/converter.NewCodecStorageDriver].
+// Because the key identifies the content itself, a cached payload can never
+// be stale, and a cache wrapping a codec storage driver still only serves a
+// payload to claims made with the same codec configuration. Claims without a
+// content hash bypass the cache. Entries in the disk tier are checked against
+// their own checksum when read, and discarded if it does not match.
+//
+// NOTE: Experimental
+package cachingdriver
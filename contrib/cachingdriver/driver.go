@@ -0,0 +1,304 @@
+package cachingdriver
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"path"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	defaultMaxMemoryBytes = 64 * 1024 * 1024   // 64 MiB
+	defaultMaxDiskBytes   = 1024 * 1024 * 1024 // 1 GiB
+
+	claimKeyHashAlgorithm = "hash_algorithm"
+	claimKeyHashValue     = "hash_value"
+)
+
+// Metric names emitted by the caching driver. Both counters carry a
+// "driver_name" tag with the name of the wrapped driver. The hit counter also
+// carries a "cache_tier" tag of "memory" or "disk".
+const (
+	MetricCacheHit  = "temporal_external_storage_cache_hit"
+	MetricCacheMiss = "temporal_external_storage_cache_miss"
+
+	driverNameTagName = "driver_name"
+	cacheTierTagName  = "cache_tier"
+	cacheTierMemory   = "memory"
+	cacheTierDisk     = "disk"
+)
+
+// Options configures the caching storage driver.
+//
+// NOTE: Experimental
+type Options struct {
+	// Driver is the storage driver whose retrievals are cached. Required.
+	Driver converter.StorageDriver
+
+	// MaxMemoryBytes bounds the total serialized size of the payloads kept in
+	// memory. Payloads larger than the bound are never cached in memory.
+	// Defaults to 64 MiB.
+	MaxMemoryBytes int64
+
+	// DiskDir, if set, enables a second cache tier that keeps payloads as files
+	// beneath this directory. The directory is created if it does not exist.
+	// Entries written by a previous process are reused, so the tier survives
+	// worker restarts. The directory must not be shared with other processes.
+	DiskDir string
+
+	// MaxDiskBytes bounds the total size of the files in DiskDir. Ignored if
+	// DiskDir is empty. Defaults to 1 GiB.
+	MaxDiskBytes int64
+
+	// MetricsHandler receives the cache hit and miss counters. Typically the
+	// same handler configured in client.Options.MetricsHandler. Defaults to
+	// client.MetricsNopHandler.
+	MetricsHandler client.MetricsHandler
+}
+
+// cachingDriver implements converter.StorageDriver by serving retrievals from
+// an in-memory and an optional on-disk LRU cache before falling back to the
+// wrapped driver.
+type cachingDriver struct {
+	driver converter.StorageDriver
+	// driverScope is the hex-encoded SHA-256 of the wrapped driver's name,
+	// which scopes the cache keys of the driver's claims.
+	driverScope string
+	memory      *lru
+	disk        *diskCache
+
+	memoryHits client.MetricsCounter
+	diskHits   client.MetricsCounter
+	misses     client.MetricsCounter
+}
+
+// cachingDeleterDriver is returned in place of cachingDriver when the wrapped
+// driver supports deletion, so that the wrapper supports it too.
+type cachingDeleterDriver struct {
+	*cachingDriver
+	deleter converter.StorageDriverDeleter
+}
+
+// Compile-time checks that the wrappers implement converter.StorageDriver and
+// converter.StorageDriverDeleter.
+var (
+	_ converter.StorageDriver        = (*cachingDriver)(nil)
+	_ converter.StorageDriverDeleter = (*cachingDeleterDriver)(nil)
+)
+
+// NewDriver creates a StorageDriver that caches the payloads retrieved through
+// Options.Driver. The returned driver implements
+// converter.StorageDriverDeleter if and only if the wrapped driver does.
+//
+// NOTE: Experimental
+func NewDriver(opts Options) (converter.StorageDriver, error) {
+	if opts.Driver == nil {
+		return nil, errors.New("Driver is required")
+	}
+	maxMemory := opts.MaxMemoryBytes
+	if maxMemory == 0 {
+		maxMemory = defaultMaxMemoryBytes
+	}
+	if maxMemory < 0 {
+		return nil, fmt.Errorf("MaxMemoryBytes must be positive, got %d", maxMemory)
+	}
+	handler := opts.MetricsHandler
+	if handler == nil {
+		handler = client.MetricsNopHandler
+	}
+	handler = handler.WithTags(map[string]string{driverNameTagName: opts.Driver.Name()})
+	d := &cachingDriver{
+		driver:      opts.Driver,
+		driverScope: sha256Hex(opts.Driver.Name()),
+		memory:      newLRU(maxMemory),
+		memoryHits:  handler.WithTags(map[string]string{cacheTierTagName: cacheTierMemory}).Counter(MetricCacheHit),
+		diskHits:    handler.WithTags(map[string]string{cacheTierTagName: cacheTierDisk}).Counter(MetricCacheHit),
+		misses:      handler.Counter(MetricCacheMiss),
+	}
+	if opts.DiskDir != "" {
+		maxDisk := opts.MaxDiskBytes
+		if maxDisk == 0 {
+			maxDisk = defaultMaxDiskBytes
+		}
+		if maxDisk < 0 {
+			return nil, fmt.Errorf("MaxDiskBytes must be positive, got %d", maxDisk)
+		}
+		disk, err := newDiskCache(opts.DiskDir, maxDisk)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize disk cache: %w", err)
+		}
+		d.disk = disk
+	}
+	if deleter, ok := opts.Driver.(converter.StorageDriverDeleter); ok {
+		return &cachingDeleterDriver{cachingDriver: d, deleter: deleter}, nil
+	}
+	return d, nil
+}
+
+// Name returns the name of the wrapped driver.
+func (d *cachingDriver) Name() string { return d.driver.Name() }
+
+// Type returns the type of the wrapped driver.
+func (d *cachingDriver) Type() string { return d.driver.Type() }
+
+// Store delegates to the wrapped driver. Stored payloads are not cached.
+func (d *cachingDriver) Store(
+	ctx converter.StorageDriverStoreContext,
+	payloads []*commonpb.Payload,
+) ([]converter.StorageDriverClaim, error) {
+	return d.driver.Store(ctx, payloads)
+}
+
+// Retrieve serves each claim from the memory tier, then the disk tier, and
+// retrieves the remaining claims from the wrapped driver in a single call.
+// Payloads retrieved from the wrapped driver are added to both tiers, and disk
+// hits are promoted to memory.
+func (d *cachingDriver) Retrieve(
+	ctx converter.StorageDriverRetrieveContext,
+	claims []converter.StorageDriverClaim,
+) ([]*commonpb.Payload, error) {
+	payloads := make([]*commonpb.Payload, len(claims))
+	var missIndexes []int
+	var missClaims []converter.StorageDriverClaim
+	for i, c := range claims {
+		key, ok := d.cacheKey(c)
+		if ok {
+			if p := d.lookup(key); p != nil {
+				payloads[i] = p
+				continue
+			}
+			d.misses.Inc(1)
+		}
+		missIndexes = append(missIndexes, i)
+		missClaims = append(missClaims, c)
+	}
+	if len(missClaims) == 0 {
+		return payloads, nil
+	}
+
+	retrieved, err := d.driver.Retrieve(ctx, missClaims)
+	if err != nil {
+		return nil, err
+	}
+	if len(retrieved) != len(missClaims) {
+		return nil, fmt.Errorf("driver %q returned %d payloads for %d claims", d.driver.Name(), len(retrieved), len(missClaims))
+	}
+	for j, i := range missIndexes {
+		payloads[i] = retrieved[j]
+		if key, ok := d.cacheKey(missClaims[j]); ok {
+			d.fill(key, retrieved[j])
+		}
+	}
+	return payloads, nil
+}
+
+// Delete removes the given claims from the cache and deletes them through the
+// wrapped driver.
+func (d *cachingDeleterDriver) Delete(
+	ctx converter.StorageDriverDeleteContext,
+	claims []converter.StorageDriverClaim,
+) error {
+	for _, c := range claims {
+		if key, ok := d.cacheKey(c); ok {
+			d.memory.remove(key)
+			if d.disk != nil {
+				d.disk.remove(key)
+			}
+		}
+	}
+	return d.deleter.Delete(ctx, claims)
+}
+
+// lookup returns the cached payload for key, or nil on a miss. Each hit is
+// unmarshaled afresh so that callers never share a payload.
+func (d *cachingDriver) lookup(key string) *commonpb.Payload {
+	if e, ok := d.memory.get(key); ok {
+		if p, err := unmarshal(e.data); err == nil {
+			d.memoryHits.Inc(1)
+			return p
+		}
+		d.memory.remove(key)
+	}
+	if d.disk == nil {
+		return nil
+	}
+	data, ok := d.disk.get(key)
+	if !ok {
+		return nil
+	}
+	p, err := unmarshal(data)
+	if err != nil {
+		d.disk.remove(key)
+		return nil
+	}
+	d.memory.add(lruEntry{key: key, size: int64(len(data)), data: data})
+	d.diskHits.Inc(1)
+	return p
+}
+
+// fill adds a payload retrieved from the wrapped driver to both tiers.
+// Payloads that cannot be marshaled are not cached.
+func (d *cachingDriver) fill(key string, p *commonpb.Payload) {
+	data, err := proto.Marshal(p)
+	if err != nil {
+		return
+	}
+	d.memory.add(lruEntry{key: key, size: int64(len(data)), data: data})
+	if d.disk != nil {
+		d.disk.add(key, data)
+	}
+}
+
+func unmarshal(data []byte) (*commonpb.Payload, error) {
+	var p commonpb.Payload
+	if err := proto.Unmarshal(data, &p); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// cacheKey derives the cache key from the content hash recorded in a claim,
+// scoped to the wrapped driver and to the codec configuration recorded in the
+// claim by converter.NewCodecStorageDriver, if any. The content hash covers
+// the stored bytes only, so without the scope a cache wrapping a codec storage
+// driver would serve a payload decoded by one configuration to a claim made by
+// another instead of failing the codec_id check. The key doubles as a relative
+// path in the disk tier, so claims whose hash fields are missing or contain
+// anything other than letters, digits, '-' and '_' are not cacheable.
+func (d *cachingDriver) cacheKey(c converter.StorageDriverClaim) (string, bool) {
+	algo := c.ClaimData[claimKeyHashAlgorithm]
+	value := c.ClaimData[claimKeyHashValue]
+	if !isSafeSegment(algo) || !isSafeSegment(value) {
+		return "", false
+	}
+	scope := d.driverScope
+	if codecID, ok := c.ClaimData[converter.ClaimKeyCodecID]; ok {
+		scope = sha256Hex(d.driver.Name() + "\x00" + codecID)
+	}
+	return path.Join(scope, algo, value), true
+}
+
+func sha256Hex(s string) string {
+	h := sha256.Sum256([]byte(s))
+	return hex.EncodeToString(h[:])
+}
+
+func isSafeSegment(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
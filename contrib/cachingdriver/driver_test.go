@@ -0,0 +1,452 @@
+package cachingdriver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/common/metrics"
+	"google.golang.org/protobuf/proto"
+)
+
+// memDriver stores payloads in memory under content-addressed claims, in the
+// same claim format as the built-in drivers.
+type memDriver struct {
+	mu      sync.Mutex
+	objects map[string][]byte
+}
+
+func (d *memDriver) Name() string { return "mem" }
+func (d *memDriver) Type() string { return "mem" }
+
+func (d *memDriver) Store(_ converter.StorageDriverStoreContext, payloads []*commonpb.Payload) ([]converter.StorageDriverClaim, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	claims := make([]converter.StorageDriverClaim, len(payloads))
+	for i, p := range payloads {
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return nil, err
+		}
+		h := sha256.Sum256(data)
+		hash := hex.EncodeToString(h[:])
+		d.objects[hash] = data
+		claims[i] = converter.StorageDriverClaim{ClaimData: map[string]string{
+			claimKeyHashAlgorithm: "sha256",
+			claimKeyHashValue:     hash,
+		}}
+	}
+	return claims, nil
+}
+
+func (d *memDriver) Retrieve(ctx converter.StorageDriverRetrieveContext, claims []converter.StorageDriverClaim) ([]*commonpb.Payload, error) {
+	if err := ctx.Context.Err(); err != nil {
+		return nil, err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	payloads := make([]*commonpb.Payload, len(claims))
+	for i, c := range claims {
+		data, ok := d.objects[c.ClaimData[claimKeyHashValue]]
+		if !ok {
+			return nil, fmt.Errorf("object not found: %s", c.ClaimData[claimKeyHashValue])
+		}
+		payloads[i] = &commonpb.Payload{}
+		if err := proto.Unmarshal(data, payloads[i]); err != nil {
+			return nil, err
+		}
+	}
+	return payloads, nil
+}
+
+func (d *memDriver) Delete(_ converter.StorageDriverDeleteContext, claims []converter.StorageDriverClaim) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	for _, c := range claims {
+		delete(d.objects, c.ClaimData[claimKeyHashValue])
+	}
+	return nil
+}
+
+// countingDriver wraps a driver and records the claims passed to Retrieve.
+type countingDriver struct {
+	converter.StorageDriver
+	mu        sync.Mutex
+	retrieved int
+}
+
+func (c *countingDriver) Retrieve(
+	ctx converter.StorageDriverRetrieveContext,
+	claims []converter.StorageDriverClaim,
+) ([]*commonpb.Payload, error) {
+	c.mu.Lock()
+	c.retrieved += len(claims)
+	c.mu.Unlock()
+	return c.StorageDriver.Retrieve(ctx, claims)
+}
+
+func (c *countingDriver) Delete(ctx converter.StorageDriverDeleteContext, claims []converter.StorageDriverClaim) error {
+	return c.StorageDriver.(converter.StorageDriverDeleter).Delete(ctx, claims)
+}
+
+func (c *countingDriver) retrievedCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.retrieved
+}
+
+// plainDriver stores payloads in memory without recording a content hash.
+type plainDriver struct {
+	payloads []*commonpb.Payload
+}
+
+func (d *plainDriver) Name() string { return "plain" }
+func (d *plainDriver) Type() string { return "plain" }
+
+func (d *plainDriver) Store(_ converter.StorageDriverStoreContext, payloads []*commonpb.Payload) ([]converter.StorageDriverClaim, error) {
+	claims := make([]converter.StorageDriverClaim, len(payloads))
+	for i, p := range payloads {
+		claims[i] = converter.StorageDriverClaim{ClaimData: map[string]string{"index": fmt.Sprint(len(d.payloads))}}
+		d.payloads = append(d.payloads, p)
+	}
+	return claims, nil
+}
+
+func (d *plainDriver) Retrieve(_ converter.StorageDriverRetrieveContext, claims []converter.StorageDriverClaim) ([]*commonpb.Payload, error) {
+	payloads := make([]*commonpb.Payload, len(claims))
+	for i, c := range claims {
+		var idx int
+		if _, err := fmt.Sscan(c.ClaimData["index"], &idx); err != nil {
+			return nil, err
+		}
+		payloads[i] = d.payloads[idx]
+	}
+	return payloads, nil
+}
+
+func newMemDriver() *countingDriver {
+	return &countingDriver{StorageDriver: &memDriver{objects: map[string][]byte{}}}
+}
+
+func makePayload(data string) *commonpb.Payload {
+	return &commonpb.Payload{
+		Metadata: map[string][]byte{"encoding": []byte("binary/plain")},
+		Data:     []byte(data),
+	}
+}
+
+func storeCtx() converter.StorageDriverStoreContext {
+	return converter.StorageDriverStoreContext{Context: context.Background()}
+}
+
+func retrieveCtx() converter.StorageDriverRetrieveContext {
+	return converter.StorageDriverRetrieveContext{Context: context.Background()}
+}
+
+func counterValue(h *metrics.CapturingHandler, name, tier string) int64 {
+	for _, c := range h.Counters() {
+		if c.Name == name && c.Tags[cacheTierTagName] == tier {
+			return c.Value()
+		}
+	}
+	return 0
+}
+
+func TestNewDriver_Validation(t *testing.T) {
+	_, err := NewDriver(Options{})
+	require.EqualError(t, err, "Driver is required")
+
+	inner := newMemDriver()
+	_, err = NewDriver(Options{Driver: inner, MaxMemoryBytes: -1})
+	require.EqualError(t, err, "MaxMemoryBytes must be positive, got -1")
+
+	_, err = NewDriver(Options{Driver: inner, DiskDir: t.TempDir(), MaxDiskBytes: -1})
+	require.EqualError(t, err, "MaxDiskBytes must be positive, got -1")
+}
+
+func TestDriver_NameAndType(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner})
+	require.NoError(t, err)
+	require.Equal(t, inner.Name(), d.Name())
+	require.Equal(t, inner.Type(), d.Type())
+}
+
+func TestDriver_MemoryHit(t *testing.T) {
+	inner := newMemDriver()
+	handler := metrics.NewCapturingHandler()
+	d, err := NewDriver(Options{Driver: inner, MetricsHandler: handler})
+	require.NoError(t, err)
+
+	p1, p2 := makePayload("one"), makePayload("two")
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p1, p2})
+	require.NoError(t, err)
+
+	for range 3 {
+		got, err := d.Retrieve(retrieveCtx(), claims)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(p1, got[0]))
+		require.True(t, proto.Equal(p2, got[1]))
+	}
+	require.Equal(t, 2, inner.retrievedCount())
+	require.Equal(t, int64(2), counterValue(handler, MetricCacheMiss, ""))
+	require.Equal(t, int64(4), counterValue(handler, MetricCacheHit, cacheTierMemory))
+
+	for _, c := range handler.Counters() {
+		require.Equal(t, "mem", c.Tags[driverNameTagName])
+	}
+}
+
+func TestDriver_HitsAreIndependentCopies(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{makePayload("value")})
+	require.NoError(t, err)
+	first, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	first[0].Data = []byte("mutated")
+
+	second, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Equal(t, "value", string(second[0].Data))
+}
+
+func TestDriver_MixedHitsAndMisses(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner})
+	require.NoError(t, err)
+
+	payloads := []*commonpb.Payload{makePayload("a"), makePayload("b"), makePayload("c")}
+	claims, err := d.Store(storeCtx(), payloads)
+	require.NoError(t, err)
+
+	_, err = d.Retrieve(retrieveCtx(), claims[1:2])
+	require.NoError(t, err)
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Equal(t, 3, inner.retrievedCount())
+	for i := range payloads {
+		require.True(t, proto.Equal(payloads[i], got[i]))
+	}
+}
+
+func TestDriver_MemoryEviction(t *testing.T) {
+	inner := newMemDriver()
+	p1, p2 := makePayload(strings.Repeat("1", 100)), makePayload(strings.Repeat("2", 100))
+	size := int64(proto.Size(p1))
+	d, err := NewDriver(Options{Driver: inner, MaxMemoryBytes: size + size/2})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p1, p2})
+	require.NoError(t, err)
+
+	_, err = d.Retrieve(retrieveCtx(), claims[:1])
+	require.NoError(t, err)
+	_, err = d.Retrieve(retrieveCtx(), claims[1:])
+	require.NoError(t, err)
+	require.Equal(t, 2, inner.retrievedCount())
+
+	// p2 is cached, p1 was evicted to make room for it.
+	_, err = d.Retrieve(retrieveCtx(), claims[1:])
+	require.NoError(t, err)
+	require.Equal(t, 2, inner.retrievedCount())
+	_, err = d.Retrieve(retrieveCtx(), claims[:1])
+	require.NoError(t, err)
+	require.Equal(t, 3, inner.retrievedCount())
+}
+
+func TestDriver_DiskTier(t *testing.T) {
+	inner := newMemDriver()
+	dir := t.TempDir()
+	handler := metrics.NewCapturingHandler()
+	p := makePayload(strings.Repeat("x", 100))
+	// The memory tier is too small to hold the payload.
+	d, err := NewDriver(Options{Driver: inner, MaxMemoryBytes: 1, DiskDir: dir, MetricsHandler: handler})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	for range 2 {
+		got, err := d.Retrieve(retrieveCtx(), claims)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(p, got[0]))
+	}
+	require.Equal(t, 1, inner.retrievedCount())
+	require.Equal(t, int64(1), counterValue(handler, MetricCacheHit, cacheTierDisk))
+	require.FileExists(t, diskPath(t, d, dir, claims[0]))
+
+	// A new driver over the same directory reuses the entry.
+	d, err = NewDriver(Options{Driver: inner, DiskDir: dir})
+	require.NoError(t, err)
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, got[0]))
+	require.Equal(t, 1, inner.retrievedCount())
+}
+
+func TestDriver_DiskEviction(t *testing.T) {
+	inner := newMemDriver()
+	dir := t.TempDir()
+	p1, p2 := makePayload(strings.Repeat("1", 100)), makePayload(strings.Repeat("2", 100))
+	size := int64(proto.Size(p1))
+	d, err := NewDriver(Options{Driver: inner, MaxMemoryBytes: 1, DiskDir: dir, MaxDiskBytes: size + size/2})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p1, p2})
+	require.NoError(t, err)
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+
+	require.NoFileExists(t, diskPath(t, d, dir, claims[0]))
+	require.FileExists(t, diskPath(t, d, dir, claims[1]))
+}
+
+func TestDriver_CorruptDiskEntryIsMiss(t *testing.T) {
+	inner := newMemDriver()
+	dir := t.TempDir()
+	d, err := NewDriver(Options{Driver: inner, MaxMemoryBytes: 1, DiskDir: dir})
+	require.NoError(t, err)
+
+	p := makePayload(strings.Repeat("x", 100))
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+
+	file := diskPath(t, d, dir, claims[0])
+	require.NoError(t, os.WriteFile(file, []byte{0xff, 0xff, 0xff}, 0o600))
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, got[0]))
+	require.Equal(t, 2, inner.retrievedCount())
+}
+
+func TestDriver_TamperedDiskEntryIsEvicted(t *testing.T) {
+	inner := newMemDriver()
+	dir := t.TempDir()
+	d, err := NewDriver(Options{Driver: inner, MaxMemoryBytes: 1, DiskDir: dir})
+	require.NoError(t, err)
+
+	p := makePayload(strings.Repeat("x", 100))
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+
+	// The entry still unmarshals, but no longer matches its checksum.
+	file := diskPath(t, d, dir, claims[0])
+	data, err := os.ReadFile(file)
+	require.NoError(t, err)
+	data[len(data)-1] = 'y'
+	require.NoError(t, os.WriteFile(file, data, 0o600))
+
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, got[0]))
+	require.Equal(t, 2, inner.retrievedCount())
+}
+
+func TestDriver_CodecIDScopesCache(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{makePayload("value")})
+	require.NoError(t, err)
+	withCodec := func(codecID string) []converter.StorageDriverClaim {
+		data := maps.Clone(claims[0].ClaimData)
+		data[converter.ClaimKeyCodecID] = codecID
+		return []converter.StorageDriverClaim{{ClaimData: data}}
+	}
+	for _, c := range [][]converter.StorageDriverClaim{claims, withCodec("v1"), withCodec("v2"), withCodec("v1")} {
+		_, err = d.Retrieve(retrieveCtx(), c)
+		require.NoError(t, err)
+	}
+	// Claims recording different codec configurations do not share entries.
+	require.Equal(t, 3, inner.retrievedCount())
+}
+
+func TestDriver_ClaimsWithoutHashBypassCache(t *testing.T) {
+	inner := &countingDriver{StorageDriver: &plainDriver{}}
+	handler := metrics.NewCapturingHandler()
+	d, err := NewDriver(Options{Driver: inner, MetricsHandler: handler})
+	require.NoError(t, err)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{makePayload("value")})
+	require.NoError(t, err)
+	for range 2 {
+		_, err = d.Retrieve(retrieveCtx(), claims)
+		require.NoError(t, err)
+	}
+	require.Equal(t, 2, inner.retrievedCount())
+	require.Zero(t, counterValue(handler, MetricCacheMiss, ""))
+
+	// Hash fields that are not safe path segments are not used as keys.
+	_, ok := unwrap(d).cacheKey(converter.StorageDriverClaim{ClaimData: map[string]string{
+		claimKeyHashAlgorithm: "sha256",
+		claimKeyHashValue:     "../escape",
+	}})
+	require.False(t, ok)
+}
+
+func TestDriver_RetrieveError(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner})
+	require.NoError(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{makePayload("value")})
+	require.NoError(t, err)
+	_, err = d.Retrieve(converter.StorageDriverRetrieveContext{Context: ctx}, claims)
+	require.True(t, errors.Is(err, context.Canceled))
+}
+
+func TestDriver_Delete(t *testing.T) {
+	inner := newMemDriver()
+	d, err := NewDriver(Options{Driver: inner, DiskDir: t.TempDir()})
+	require.NoError(t, err)
+	deleter, ok := d.(converter.StorageDriverDeleter)
+	require.True(t, ok)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{makePayload("value")})
+	require.NoError(t, err)
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+
+	require.NoError(t, deleter.Delete(converter.StorageDriverDeleteContext{Context: context.Background()}, claims))
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	require.Error(t, err)
+
+	// The wrapper only supports deletion if the wrapped driver does.
+	d, err = NewDriver(Options{Driver: &plainDriver{}})
+	require.NoError(t, err)
+	_, ok = d.(converter.StorageDriverDeleter)
+	require.False(t, ok)
+}
+
+func diskPath(t *testing.T, d converter.StorageDriver, dir string, c converter.StorageDriverClaim) string {
+	t.Helper()
+	key, ok := unwrap(d).cacheKey(c)
+	require.True(t, ok)
+	return filepath.Join(dir, filepath.FromSlash(key))
+}
+
+func unwrap(d converter.StorageDriver) *cachingDriver {
+	if dd, ok := d.(*cachingDeleterDriver); ok {
+		return dd.cachingDriver
+	}
+	return d.(*cachingDriver)
+}
@@ -0,0 +1,89 @@
+package cachingdriver
+
+import (
+	"container/list"
+	"sync"
+)
+
+// lruEntry is a single cache entry. data is nil for entries whose bytes live
+// outside the cache, such as the files of the disk tier.
+type lruEntry struct {
+	key  string
+	size int64
+	data []byte
+}
+
+// lru is a byte-bounded least-recently-used index. It is safe for concurrent
+// use.
+type lru struct {
+	mu       sync.Mutex
+	maxBytes int64
+	used     int64
+	ll       *list.List
+	items    map[string]*list.Element
+}
+
+func newLRU(maxBytes int64) *lru {
+	return &lru{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    map[string]*list.Element{},
+	}
+}
+
+// get returns the entry for key and marks it as most recently used.
+func (c *lru) get(key string) (lruEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return lruEntry{}, false
+	}
+	c.ll.MoveToFront(el)
+	return *el.Value.(*lruEntry), true
+}
+
+// add inserts or replaces the entry for key and evicts least recently used
+// entries until the cache fits within its bound. It returns the evicted
+// entries. Entries larger than the bound are not added.
+func (c *lru) add(e lruEntry) (evicted []lruEntry) {
+	if e.size > c.maxBytes {
+		return nil
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.items[e.key]; ok {
+		c.used -= el.Value.(*lruEntry).size
+		c.ll.Remove(el)
+	}
+	c.items[e.key] = c.ll.PushFront(&e)
+	c.used += e.size
+	for c.used > c.maxBytes {
+		oldest := c.ll.Back()
+		old := c.ll.Remove(oldest).(*lruEntry)
+		delete(c.items, old.key)
+		c.used -= old.size
+		evicted = append(evicted, *old)
+	}
+	return evicted
+}
+
+// remove deletes the entry for key, reporting whether it was present.
+func (c *lru) remove(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.items[key]
+	if !ok {
+		return false
+	}
+	c.used -= c.ll.Remove(el).(*lruEntry).size
+	delete(c.items, key)
+	return true
+}
+
+// usedBytes returns the total size of the entries in the cache.
+func (c *lru) usedBytes() int64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.used
+}
@@ -0,0 +1,222 @@
+// Package compression provides zstd and snappy payload compressors for use
+// with [go.temporal.io/sdk/converter.NewCompressionCodec].
+//
+// Compressors only take effect when wrapped in a codec, either directly with
+// [NewZstdCodec] and [NewSnappyCodec] or combined with other compressors via
+// [converter.NewCompressionCodec]. Include every compressor that may have
+// written payloads in the Compressors list so that mixed histories decode.
+//
+// NOTE: Experimental
+package compression
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/klauspost/compress/s2"
+	"github.com/klauspost/compress/zstd"
+	"go.temporal.io/sdk/converter"
+)
+
+const (
+	// MetadataEncodingZstd is "binary/zstd"
+	MetadataEncodingZstd = "binary/zstd"
+	// MetadataEncodingSnappy is "binary/snappy"
+	MetadataEncodingSnappy = "binary/snappy"
+
+	defaultMaxDecompressedSize = 128 * 1024 * 1024 // 128 MiB
+)
+
+// ZstdLevel is a zstd compression level.
+//
+// NOTE: Experimental
+type ZstdLevel = zstd.EncoderLevel
+
+// Zstd compression levels. See the zstd package for details.
+const (
+	ZstdLevelFastest           ZstdLevel = zstd.SpeedFastest
+	ZstdLevelDefault           ZstdLevel = zstd.SpeedDefault
+	ZstdLevelBetterCompression ZstdLevel = zstd.SpeedBetterCompression
+	ZstdLevelBestCompression   ZstdLevel = zstd.SpeedBestCompression
+)
+
+// ZstdOptions are options for NewZstdCompressor. All fields are optional.
+//
+// NOTE: Experimental
+type ZstdOptions struct {
+	// Level is the compression level. Defaults to ZstdLevelDefault.
+	Level ZstdLevel
+
+	// MaxDecompressedSize is the maximum size in bytes of a decompressed
+	// payload. Payloads that decompress to more are rejected, which bounds the
+	// memory a small malicious payload can make the worker allocate. Defaults
+	// to 128 MiB.
+	MaxDecompressedSize int
+}
+
+type zstdCompressor struct {
+	encoder             *zstd.Encoder
+	maxDecompressedSize int
+	// decoders pools single-threaded decoders, so that idle ones and their
+	// buffers are released rather than held for the lifetime of the codec.
+	decoders sync.Pool
+}
+
+// NewZstdCompressor returns a converter.PayloadCompressor using zstd. The
+// returned compressor is safe for concurrent use.
+//
+// NOTE: Experimental
+func NewZstdCompressor(options ZstdOptions) (converter.PayloadCompressor, error) {
+	level := options.Level
+	if level == 0 {
+		level = ZstdLevelDefault
+	}
+	maxSize, err := maxDecompressedSize(options.MaxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	// The encoder is only used through its stateless EncodeAll method, which is
+	// safe for concurrent use.
+	encoder, err := zstd.NewWriter(nil, zstd.WithEncoderLevel(level), zstd.WithEncoderConcurrency(1))
+	if err != nil {
+		return nil, err
+	}
+	z := &zstdCompressor{encoder: encoder, maxDecompressedSize: maxSize}
+	// Check the decoder options once so that the pool cannot fail later.
+	decoder, err := z.newDecoder()
+	if err != nil {
+		return nil, err
+	}
+	z.decoders.Put(decoder)
+	return z, nil
+}
+
+// newDecoder creates a decoder that decodes one payload at a time with
+// DecodeAll. Decoders created without an input reader start no goroutines, so
+// they need not be closed.
+func (z *zstdCompressor) newDecoder() (*zstd.Decoder, error) {
+	return zstd.NewReader(nil,
+		zstd.WithDecoderConcurrency(1),
+		zstd.WithDecoderMaxMemory(uint64(z.maxDecompressedSize)),
+	)
+}
+
+func (*zstdCompressor) Encoding() string { return MetadataEncodingZstd }
+
+func (z *zstdCompressor) Compress(data []byte) ([]byte, error) {
+	return z.encoder.EncodeAll(data, nil), nil
+}
+
+func (z *zstdCompressor) Decompress(data []byte) ([]byte, error) {
+	decoder, ok := z.decoders.Get().(*zstd.Decoder)
+	if !ok {
+		var err error
+		if decoder, err = z.newDecoder(); err != nil {
+			return nil, err
+		}
+	}
+	defer z.decoders.Put(decoder)
+	return decoder.DecodeAll(data, nil)
+}
+
+// SnappyOptions are options for NewSnappyCompressor. All fields are optional.
+//
+// NOTE: Experimental
+type SnappyOptions struct {
+	// MaxDecompressedSize is the maximum size in bytes of a decompressed
+	// payload. Payloads that decompress to more are rejected, which bounds the
+	// memory a small malicious payload can make the worker allocate. Defaults
+	// to 128 MiB.
+	MaxDecompressedSize int
+}
+
+type snappyCompressor struct {
+	maxDecompressedSize int
+}
+
+// NewSnappyCompressor returns a converter.PayloadCompressor using the snappy
+// block format.
+//
+// NOTE: Experimental
+func NewSnappyCompressor(options SnappyOptions) (converter.PayloadCompressor, error) {
+	maxSize, err := maxDecompressedSize(options.MaxDecompressedSize)
+	if err != nil {
+		return nil, err
+	}
+	return snappyCompressor{maxDecompressedSize: maxSize}, nil
+}
+
+func (snappyCompressor) Encoding() string { return MetadataEncodingSnappy }
+
+func (snappyCompressor) Compress(data []byte) ([]byte, error) {
+	return s2.EncodeSnappy(nil, data), nil
+}
+
+func (s snappyCompressor) Decompress(data []byte) ([]byte, error) {
+	// The decoded length is read from the header, so that the output buffer
+	// is never allocated for oversized payloads.
+	size, err := s2.DecodedLen(data)
+	if err != nil {
+		return nil, err
+	}
+	if size > s.maxDecompressedSize {
+		return nil, fmt.Errorf("decompressed size %d exceeds maximum %d", size, s.maxDecompressedSize)
+	}
+	return s2.Decode(nil, data)
+}
+
+func maxDecompressedSize(size int) (int, error) {
+	if size == 0 {
+		return defaultMaxDecompressedSize, nil
+	}
+	if size < 0 {
+		return 0, fmt.Errorf("MaxDecompressedSize must be positive, got %d", size)
+	}
+	return size, nil
+}
+
+// CodecOptions are options for NewZstdCodec and NewSnappyCodec. All fields are
+// optional.
+//
+// NOTE: Experimental
+type CodecOptions struct {
+	// MinSize is the serialized payload size in bytes below which payloads are
+	// left uncompressed. See converter.CompressionCodecOptions.MinSize.
+	MinSize int
+
+	// If true, the codec will use the compressed form even if there is no size
+	// benefit. Otherwise, the compressed form is only used if it is smaller.
+	AlwaysEncode bool
+}
+
+// NewZstdCodec creates a converter.PayloadCodec for use in
+// converter.NewCodecDataConverter to support zstd payload compression.
+//
+// NOTE: Experimental
+func NewZstdCodec(zstdOptions ZstdOptions, options CodecOptions) (converter.PayloadCodec, error) {
+	compressor, err := NewZstdCompressor(zstdOptions)
+	if err != nil {
+		return nil, err
+	}
+	return newCodec(compressor, options)
+}
+
+// NewSnappyCodec creates a converter.PayloadCodec for use in
+// converter.NewCodecDataConverter to support snappy payload compression.
+//
+// NOTE: Experimental
+func NewSnappyCodec(snappyOptions SnappyOptions, options CodecOptions) (converter.PayloadCodec, error) {
+	compressor, err := NewSnappyCompressor(snappyOptions)
+	if err != nil {
+		return nil, err
+	}
+	return newCodec(compressor, options)
+}
+
+func newCodec(compressor converter.PayloadCompressor, options CodecOptions) (converter.PayloadCodec, error) {
+	return converter.NewCompressionCodec(converter.CompressionCodecOptions{
+		Compressors:  []converter.PayloadCompressor{compressor},
+		MinSize:      options.MinSize,
+		AlwaysEncode: options.AlwaysEncode,
+	})
+}
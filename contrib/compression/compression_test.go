@@ -0,0 +1,153 @@
+package compression
+
+import (
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+func largeJSONPayload(t *testing.T) *commonpb.Payload {
+	t.Helper()
+	records := make([]map[string]string, 500)
+	for i := range records {
+		records[i] = map[string]string{"name": "record", "status": "active", "region": "us-east-1"}
+	}
+	p, err := converter.GetDefaultDataConverter().ToPayload(records)
+	require.NoError(t, err)
+	return p
+}
+
+func TestZstdCodec_RoundTrip(t *testing.T) {
+	codec, err := NewZstdCodec(ZstdOptions{}, CodecOptions{})
+	require.NoError(t, err)
+	p := largeJSONPayload(t)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingZstd, string(encoded[0].Metadata[converter.MetadataEncoding]))
+	require.Less(t, len(encoded[0].Data), len(p.Data))
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, decoded[0]))
+}
+
+func TestSnappyCodec_RoundTrip(t *testing.T) {
+	codec, err := NewSnappyCodec(SnappyOptions{}, CodecOptions{})
+	require.NoError(t, err)
+	p := largeJSONPayload(t)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingSnappy, string(encoded[0].Metadata[converter.MetadataEncoding]))
+	require.Less(t, len(encoded[0].Data), len(p.Data))
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, decoded[0]))
+}
+
+func TestCodec_MinSize(t *testing.T) {
+	codec, err := NewSnappyCodec(SnappyOptions{}, CodecOptions{MinSize: 1024})
+	require.NoError(t, err)
+	small, err := converter.GetDefaultDataConverter().ToPayload(strings.Repeat("a", 100))
+	require.NoError(t, err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{small})
+	require.NoError(t, err)
+	require.Same(t, small, encoded[0])
+}
+
+func TestZstdLevels(t *testing.T) {
+	p := largeJSONPayload(t)
+	for _, level := range []ZstdLevel{ZstdLevelFastest, ZstdLevelDefault, ZstdLevelBetterCompression, ZstdLevelBestCompression} {
+		codec, err := NewZstdCodec(ZstdOptions{Level: level}, CodecOptions{})
+		require.NoError(t, err)
+		encoded, err := codec.Encode([]*commonpb.Payload{p})
+		require.NoError(t, err)
+		decoded, err := codec.Decode(encoded)
+		require.NoError(t, err)
+		require.True(t, proto.Equal(p, decoded[0]), "level %v", level)
+	}
+}
+
+func TestMixedHistoryDecodes(t *testing.T) {
+	p := largeJSONPayload(t)
+	zstdCompressor, err := NewZstdCompressor(ZstdOptions{})
+	require.NoError(t, err)
+
+	snappyCompressor, err := NewSnappyCompressor(SnappyOptions{})
+	require.NoError(t, err)
+
+	zlibEncoded, err := converter.NewZlibCodec(converter.ZlibCodecOptions{}).Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	snappyCodec, err := NewSnappyCodec(SnappyOptions{}, CodecOptions{})
+	require.NoError(t, err)
+	snappyEncoded, err := snappyCodec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	// A codec that now writes zstd must still read what was written before.
+	codec, err := converter.NewCompressionCodec(converter.CompressionCodecOptions{
+		Compressors: []converter.PayloadCompressor{
+			zstdCompressor,
+			snappyCompressor,
+			converter.NewZlibCompressor(),
+		},
+	})
+	require.NoError(t, err)
+	zstdEncoded, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	history := []*commonpb.Payload{zlibEncoded[0], snappyEncoded[0], zstdEncoded[0]}
+	decoded, err := codec.Decode(history)
+	require.NoError(t, err)
+	for _, d := range decoded {
+		require.True(t, proto.Equal(p, d))
+	}
+}
+
+func TestZstdCompressor_Concurrent(t *testing.T) {
+	compressor, err := NewZstdCompressor(ZstdOptions{})
+	require.NoError(t, err)
+	var wg sync.WaitGroup
+	for i := range 8 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data := []byte(strings.Repeat(string(rune('a'+i)), 4096))
+			compressed, err := compressor.Compress(data)
+			assert.NoError(t, err)
+			decompressed, err := compressor.Decompress(compressed)
+			assert.NoError(t, err)
+			assert.Equal(t, data, decompressed)
+		}()
+	}
+	wg.Wait()
+}
+
+func TestMaxDecompressedSize(t *testing.T) {
+	data := make([]byte, 1024*1024)
+	zstdCompressor, err := NewZstdCompressor(ZstdOptions{MaxDecompressedSize: len(data) - 1})
+	require.NoError(t, err)
+	snappyCompressor, err := NewSnappyCompressor(SnappyOptions{MaxDecompressedSize: len(data) - 1})
+	require.NoError(t, err)
+
+	for _, compressor := range []converter.PayloadCompressor{zstdCompressor, snappyCompressor} {
+		compressed, err := compressor.Compress(data)
+		require.NoError(t, err)
+		require.Less(t, len(compressed), len(data)/10, compressor.Encoding())
+		_, err = compressor.Decompress(compressed)
+		require.Error(t, err, compressor.Encoding())
+	}
+
+	_, err = NewZstdCompressor(ZstdOptions{MaxDecompressedSize: -1})
+	require.EqualError(t, err, "MaxDecompressedSize must be positive, got -1")
+	_, err = NewSnappyCompressor(SnappyOptions{MaxDecompressedSize: -1})
+	require.EqualError(t, err, "MaxDecompressedSize must be positive, got -1")
+}
@@ -33,6 +33,10 @@ type ClientConfigProfile struct {
 	TLS *ClientConfigTLS
 	// Optional client codec config.
 	Codec *ClientConfigCodec
+	// Optional payload size limit config.
+	PayloadLimits *ClientConfigPayloadLimits
+	// Optional external payload storage config.
+	ExternalStorage *ClientConfigExternalStorage
 	// Client gRPC metadata (aka headers). When loading from TOML and env var, or writing to TOML, the keys are
 	// lowercased and hyphens are replaced with underscores. This is used for deduplicating/overriding too, so manually
 	// set values that are not normalized may not get overridden with [ClientConfigProfile.ApplyEnvVars].
@@ -62,20 +66,75 @@ type ClientConfigTLS struct {
 	DisableHostVerification bool
 }
 
-// ClientConfigCodec is codec configuration for a client.
+// ClientConfigCodec is codec configuration for a client. Endpoint and Auth describe a remote codec server, which is only
+// used if [ToClientOptionsRequest.IncludeRemoteCodec] is set. Otherwise, Compression and Encryption are applied locally
+// by the data converter of the client. Compression and Encryption are ignored when the remote codec is used, so that a
+// single profile can serve both tools that use the codec server and workers that apply the codec locally.
 type ClientConfigCodec struct {
 	// Remote endpoint for the codec.
 	Endpoint string
 	// Auth for the codec.
 	Auth string
+	// Optional local payload compression config.
+	Compression *ClientConfigCompression
+	// Optional local payload encryption config.
+	Encryption *ClientConfigEncryption
 }
 
+// ClientConfigCompression is payload compression configuration for a client. See [converter.NewCompressionCodec].
+type ClientConfigCompression struct {
+	// Compression algorithms that can be decoded. The first is also used to encode. "zlib" is built in, others must be
+	// provided in [ToClientOptionsRequest.Compressors].
+	Algorithms []string
+	// Serialized payload size in bytes below which payloads are left uncompressed.
+	MinSize int
+	// If true, the compressed form is used even if it is not smaller.
+	AlwaysEncode bool
+}
+
+// ClientConfigEncryption is AES-GCM payload encryption configuration for a client. See [converter.NewEncryptionCodec].
+type ClientConfigEncryption struct {
+	// ID of the key in KeyFiles used to encrypt new payloads.
+	CurrentKeyID string
+	// Paths to files containing raw AES key bytes (16, 24, or 32 bytes), keyed by key ID. Every key whose payloads may
+	// still be read must be kept here.
+	KeyFiles map[string]string
+}
+
+// ClientConfigPayloadLimits is payload size limit configuration for a client. See [client.PayloadLimitOptions].
+type ClientConfigPayloadLimits struct {
+	// The limit in bytes at which a payload size warning is logged.
+	PayloadSizeWarning int
+	// The limit in bytes at which an aggregate memo size warning is logged.
+	MemoSizeWarning int
+}
+
+// ClientConfigExternalStorage is external payload storage configuration for a client. See [converter.ExternalStorage].
+type ClientConfigExternalStorage struct {
+	// Name of the storage driver factory in [ToClientOptionsRequest.StorageDriverFactories] used to create the driver.
+	Driver string
+	// Driver-specific options passed to the factory. When loading from TOML and env var, or writing to TOML, the keys
+	// are lowercased.
+	Options map[string]string
+	// Minimum serialized payload size in bytes that triggers external storage. Zero uses the SDK default.
+	PayloadSizeThreshold int
+}
+
+// StorageDriverFactory creates a storage driver from the driver-specific options of a
+// [ClientConfigExternalStorage].
+type StorageDriverFactory func(options map[string]string) (converter.StorageDriver, error)
+
 // ToClientOptionsRequest are options for [ClientConfig.ToClientOptions] and [ClientConfigProfile.ToClientOptions].
 type ToClientOptionsRequest struct {
-	// If true and a codec is configured, the data converter of the client will point to the codec remotely. Users
-	// should usually not set this and rather configure the codec locally. Users should especially not enable this for
-	// clients used by workers since they call the codec repeatedly even during workflow replay.
+	// If true and a codec is configured, the data converter of the client will point to the codec remotely. The codec
+	// must then have an endpoint, or conversion fails. Local compression and encryption of the codec are ignored. Users should usually not
+	// set this and rather configure the codec locally. Users should especially not enable this for clients used by
+	// workers since they call the codec repeatedly even during workflow replay.
 	IncludeRemoteCodec bool
+	// Compressors available to a compression codec config in addition to the built-in "zlib", keyed by algorithm name.
+	Compressors map[string]converter.PayloadCompressor
+	// Factories available to an external storage config, keyed by driver name.
+	StorageDriverFactories map[string]StorageDriverFactory
 }
 
 // ToClientOptions converts the given profile to client options that can be used to create an SDK client. Defaults to
@@ -107,12 +166,24 @@ func (c *ClientConfigProfile) ToClientOptions(options ToClientOptionsRequest) (c
 	} else if c.APIKey != "" {
 		opts.ConnectionOptions.TLS = &tls.Config{}
 	}
-	if c.Codec != nil && options.IncludeRemoteCodec {
+	if c.Codec != nil {
 		var err error
-		if opts.DataConverter, err = c.Codec.toDataConverter(c.Namespace); err != nil {
+		if opts.DataConverter, err = c.Codec.toDataConverter(c.Namespace, options); err != nil {
 			return client.Options{}, fmt.Errorf("invalid codec: %w", err)
 		}
 	}
+	if c.PayloadLimits != nil {
+		opts.PayloadLimits = client.PayloadLimitOptions{
+			PayloadSizeWarning: c.PayloadLimits.PayloadSizeWarning,
+			MemoSizeWarning:    c.PayloadLimits.MemoSizeWarning,
+		}
+	}
+	if c.ExternalStorage != nil {
+		var err error
+		if opts.ExternalStorage, err = c.ExternalStorage.toExternalStorage(options.StorageDriverFactories); err != nil {
+			return client.Options{}, fmt.Errorf("invalid external storage: %w", err)
+		}
+	}
 	if len(c.GRPCMeta) > 0 {
 		opts.HeadersProvider = fixedHeaders(c.GRPCMeta)
 	}
@@ -171,7 +242,38 @@ func (c *ClientConfigTLS) applyToConnectionOptions(connOpts *client.ConnectionOp
 	return nil
 }
 
-func (c *ClientConfigCodec) toDataConverter(namespace string) (converter.DataConverter, error) {
+// toDataConverter returns nil if neither a remote codec (when requested) nor any local codec is configured.
+func (c *ClientConfigCodec) toDataConverter(namespace string, options ToClientOptionsRequest) (converter.DataConverter, error) {
+	if options.IncludeRemoteCodec {
+		if c.Endpoint == "" {
+			return nil, fmt.Errorf("remote codec requested but no endpoint configured")
+		}
+		// The codec server is expected to apply the local codecs configured here, which are therefore ignored
+		return c.toRemoteDataConverter(namespace)
+	}
+	// Encryption is listed first so that it is applied last, after compression, on encode
+	var codecs []converter.PayloadCodec
+	if c.Encryption != nil {
+		codec, err := c.Encryption.toCodec()
+		if err != nil {
+			return nil, fmt.Errorf("invalid encryption: %w", err)
+		}
+		codecs = append(codecs, codec)
+	}
+	if c.Compression != nil {
+		codec, err := c.Compression.toCodec(options.Compressors)
+		if err != nil {
+			return nil, fmt.Errorf("invalid compression: %w", err)
+		}
+		codecs = append(codecs, codec)
+	}
+	if len(codecs) == 0 {
+		return nil, nil
+	}
+	return converter.NewCodecDataConverter(converter.GetDefaultDataConverter(), codecs...), nil
+}
+
+func (c *ClientConfigCodec) toRemoteDataConverter(namespace string) (converter.DataConverter, error) {
 	return converter.NewRemoteDataConverter(converter.GetDefaultDataConverter(), converter.RemoteDataConverterOptions{
 		Endpoint: c.Endpoint,
 		ModifyRequest: func(req *http.Request) error {
@@ -184,6 +286,63 @@ func (c *ClientConfigCodec) toDataConverter(namespace string) (converter.DataCon
 	}), nil
 }
 
+func (c *ClientConfigCompression) toCodec(compressors map[string]converter.PayloadCompressor) (converter.PayloadCodec, error) {
+	if len(c.Algorithms) == 0 {
+		return nil, fmt.Errorf("at least one algorithm is required")
+	}
+	codecOptions := converter.CompressionCodecOptions{MinSize: c.MinSize, AlwaysEncode: c.AlwaysEncode}
+	for _, algo := range c.Algorithms {
+		compressor := compressors[algo]
+		if compressor == nil && algo == "zlib" {
+			compressor = converter.NewZlibCompressor()
+		}
+		if compressor == nil {
+			return nil, fmt.Errorf("unknown algorithm %q", algo)
+		}
+		codecOptions.Compressors = append(codecOptions.Compressors, compressor)
+	}
+	return converter.NewCompressionCodec(codecOptions)
+}
+
+func (c *ClientConfigEncryption) toCodec() (converter.PayloadCodec, error) {
+	keys := make(map[string][]byte, len(c.KeyFiles))
+	for id, path := range c.KeyFiles {
+		key, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed reading key file for key %q: %w", id, err)
+		}
+		keys[id] = key
+	}
+	provider, err := converter.NewStaticEncryptionKeyProvider(converter.StaticEncryptionKeyProviderOptions{
+		CurrentKeyID: c.CurrentKeyID,
+		Keys:         keys,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return converter.NewEncryptionCodec(converter.EncryptionCodecOptions{KeyProvider: provider})
+}
+
+func (c *ClientConfigExternalStorage) toExternalStorage(
+	factories map[string]StorageDriverFactory,
+) (converter.ExternalStorage, error) {
+	if c.Driver == "" {
+		return converter.ExternalStorage{}, fmt.Errorf("driver is required")
+	}
+	factory := factories[c.Driver]
+	if factory == nil {
+		return converter.ExternalStorage{}, fmt.Errorf("no storage driver factory for driver %q", c.Driver)
+	}
+	driver, err := factory(c.Options)
+	if err != nil {
+		return converter.ExternalStorage{}, fmt.Errorf("failed creating driver %q: %w", c.Driver, err)
+	}
+	return converter.ExternalStorage{
+		Drivers:              []converter.StorageDriver{driver},
+		PayloadSizeThreshold: c.PayloadSizeThreshold,
+	}, nil
+}
+
 // NormalizeGRPCMetaKey converts the given key to lowercase and replaces underscores with hyphens.
 func NormalizeGRPCMetaKey(k string) string {
 	return strings.ToLower(strings.ReplaceAll(k, "_", "-"))
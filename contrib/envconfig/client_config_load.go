@@ -4,9 +4,11 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 
 	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/converter"
 )
 
 // MustLoadDefaultClientOptions invokes [LoadDefaultClientOptions] and panics on error.
@@ -52,11 +54,18 @@ type LoadClientOptionsRequest struct {
 	// options structure.
 	DisableEnv bool
 
-	// If true and a codec is configured, the data converter of the client will point to the codec remotely. Users
-	// should usually not set this and rather configure the codec locally. Users should especially not enable this for
-	// clients used by workers since they call the codec repeatedly even during workflow replay.
+	// If true and a codec is configured, the data converter of the client will point to the codec remotely. The codec
+	// must then have an endpoint, or conversion fails. Local compression and encryption of the codec are ignored. Users should usually not
+	// set this and rather configure the codec locally. Users should especially not enable this for clients used by
+	// workers since they call the codec repeatedly even during workflow replay.
 	IncludeRemoteCodec bool
 
+	// Compressors available to a compression codec config in addition to the built-in "zlib", keyed by algorithm name.
+	Compressors map[string]converter.PayloadCompressor
+
+	// Factories available to an external storage config, keyed by driver name.
+	StorageDriverFactories map[string]StorageDriverFactory
+
 	// Override the environment variable lookup. If nil, defaults to [EnvLookupOS].
 	EnvLookup EnvLookup
 }
@@ -81,7 +90,11 @@ func LoadClientOptions(options LoadClientOptionsRequest) (client.Options, error)
 	}
 
 	// Convert to client options
-	return prof.ToClientOptions(ToClientOptionsRequest{IncludeRemoteCodec: options.IncludeRemoteCodec})
+	return prof.ToClientOptions(ToClientOptionsRequest{
+		IncludeRemoteCodec:     options.IncludeRemoteCodec,
+		Compressors:            options.Compressors,
+		StorageDriverFactories: options.StorageDriverFactories,
+	})
 }
 
 // [LoadClientConfigOptions] are options for [LoadClientConfig].
@@ -348,6 +361,95 @@ func (c *ClientConfigProfile) ApplyEnvVars(env EnvLookup) error {
 		}
 		c.Codec.Auth = s
 	}
+	// An empty compression env var disables compression
+	if s, ok := env.LookupEnv("TEMPORAL_CODEC_COMPRESSION"); ok {
+		if c.Codec == nil {
+			c.Codec = &ClientConfigCodec{}
+		}
+		if s == "" {
+			c.Codec.Compression = nil
+		} else {
+			if c.Codec.Compression == nil {
+				c.Codec.Compression = &ClientConfigCompression{}
+			}
+			c.Codec.Compression.Algorithms = strings.Split(s, ",")
+		}
+	}
+	// The minimum size only applies to compression that is enabled, so it cannot enable compression on its own
+	if s, ok := env.LookupEnv("TEMPORAL_CODEC_COMPRESSION_MIN_SIZE"); ok {
+		v, err := envVarToInt("TEMPORAL_CODEC_COMPRESSION_MIN_SIZE", s)
+		if err != nil {
+			return err
+		}
+		if c.Codec != nil && c.Codec.Compression != nil {
+			c.Codec.Compression.MinSize = v
+		}
+	}
+	if s, ok := env.LookupEnv("TEMPORAL_CODEC_ENCRYPTION_KEY_ID"); ok {
+		if c.Codec == nil {
+			c.Codec = &ClientConfigCodec{}
+		}
+		if c.Codec.Encryption == nil {
+			c.Codec.Encryption = &ClientConfigEncryption{}
+		}
+		c.Codec.Encryption.CurrentKeyID = s
+	}
+	// Key files are given as comma-separated id=path pairs and replace all key files
+	if s, ok := env.LookupEnv("TEMPORAL_CODEC_ENCRYPTION_KEY_FILES"); ok {
+		keyFiles := map[string]string{}
+		if s != "" {
+			for _, pair := range strings.Split(s, ",") {
+				id, path, ok := strings.Cut(pair, "=")
+				if !ok || id == "" || path == "" {
+					return fmt.Errorf("invalid TEMPORAL_CODEC_ENCRYPTION_KEY_FILES entry %q, expected id=path", pair)
+				}
+				keyFiles[id] = path
+			}
+		}
+		if c.Codec == nil {
+			c.Codec = &ClientConfigCodec{}
+		}
+		if c.Codec.Encryption == nil {
+			c.Codec.Encryption = &ClientConfigEncryption{}
+		}
+		c.Codec.Encryption.KeyFiles = keyFiles
+	}
+	if s, ok := env.LookupEnv("TEMPORAL_PAYLOAD_SIZE_WARNING"); ok {
+		v, err := envVarToInt("TEMPORAL_PAYLOAD_SIZE_WARNING", s)
+		if err != nil {
+			return err
+		}
+		if c.PayloadLimits == nil {
+			c.PayloadLimits = &ClientConfigPayloadLimits{}
+		}
+		c.PayloadLimits.PayloadSizeWarning = v
+	}
+	if s, ok := env.LookupEnv("TEMPORAL_MEMO_SIZE_WARNING"); ok {
+		v, err := envVarToInt("TEMPORAL_MEMO_SIZE_WARNING", s)
+		if err != nil {
+			return err
+		}
+		if c.PayloadLimits == nil {
+			c.PayloadLimits = &ClientConfigPayloadLimits{}
+		}
+		c.PayloadLimits.MemoSizeWarning = v
+	}
+	if s, ok := env.LookupEnv("TEMPORAL_EXTERNAL_STORAGE_DRIVER"); ok {
+		if c.ExternalStorage == nil {
+			c.ExternalStorage = &ClientConfigExternalStorage{}
+		}
+		c.ExternalStorage.Driver = s
+	}
+	if s, ok := env.LookupEnv("TEMPORAL_EXTERNAL_STORAGE_PAYLOAD_SIZE_THRESHOLD"); ok {
+		v, err := envVarToInt("TEMPORAL_EXTERNAL_STORAGE_PAYLOAD_SIZE_THRESHOLD", s)
+		if err != nil {
+			return err
+		}
+		if c.ExternalStorage == nil {
+			c.ExternalStorage = &ClientConfigExternalStorage{}
+		}
+		c.ExternalStorage.PayloadSizeThreshold = v
+	}
 
 	// GRPC meta requires crawling the envs to find
 	for _, v := range env.Environ() {
@@ -366,10 +468,34 @@ func (c *ClientConfigProfile) ApplyEnvVars(env EnvLookup) error {
 				c.GRPCMeta[key] = pieces[1]
 			}
 		}
+		// External storage options work the same way as gRPC meta, but keys are only lowercased
+		if strings.HasPrefix(v, "TEMPORAL_EXTERNAL_STORAGE_OPTION_") {
+			pieces := strings.SplitN(v, "=", 2)
+			if c.ExternalStorage == nil {
+				c.ExternalStorage = &ClientConfigExternalStorage{}
+			}
+			if c.ExternalStorage.Options == nil {
+				c.ExternalStorage.Options = map[string]string{}
+			}
+			key := strings.ToLower(strings.TrimPrefix(pieces[0], "TEMPORAL_EXTERNAL_STORAGE_OPTION_"))
+			if pieces[1] == "" {
+				delete(c.ExternalStorage.Options, key)
+			} else {
+				c.ExternalStorage.Options[key] = pieces[1]
+			}
+		}
 	}
 	return nil
 }
 
+func envVarToInt(name, val string) (int, error) {
+	v, err := strconv.Atoi(val)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %v: %w", name, err)
+	}
+	return v, nil
+}
+
 func envVarToBool(val string) (v bool, ok bool) {
 	val = strings.ToLower(val)
 	return val == "1" || val == "true", val == "1" || val == "0" || val == "true" || val == "false"
@@ -1,12 +1,20 @@
 package envconfig_test
 
 import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
 	"os"
+	"path/filepath"
 	"runtime"
+	"strings"
+	"sync/atomic"
 	"testing"
 
 	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/client"
 	"go.temporal.io/sdk/contrib/envconfig"
+	"go.temporal.io/sdk/converter"
 )
 
 func TestLoadClientOptionsFile(t *testing.T) {
@@ -171,6 +179,180 @@ disable_host_verification = true`
 	}, prof.GRPCMeta)
 }
 
+func TestClientProfileApplyEnvVarsDataHandling(t *testing.T) {
+	data := `
+[profile.default.codec.compression]
+algorithms = ["zlib"]
+
+[profile.default.codec.encryption]
+current_key_id = "key-1"
+key_files = { key-1 = "/keys/1" }
+
+[profile.default.external_storage]
+driver = "fs"
+options = { dir = "/payloads", mode = "0600" }`
+	prof, err := envconfig.LoadClientConfigProfile(envconfig.LoadClientConfigProfileOptions{
+		ConfigFileData: []byte(data),
+		EnvLookup: EnvLookupMap{
+			"TEMPORAL_CODEC_COMPRESSION":                       "zstd,zlib",
+			"TEMPORAL_CODEC_COMPRESSION_MIN_SIZE":              "512",
+			"TEMPORAL_CODEC_ENCRYPTION_KEY_ID":                 "key-2",
+			"TEMPORAL_CODEC_ENCRYPTION_KEY_FILES":              "key-1=/keys/1,key-2=/keys/2",
+			"TEMPORAL_PAYLOAD_SIZE_WARNING":                    "1000",
+			"TEMPORAL_MEMO_SIZE_WARNING":                       "2000",
+			"TEMPORAL_EXTERNAL_STORAGE_DRIVER":                 "s3",
+			"TEMPORAL_EXTERNAL_STORAGE_PAYLOAD_SIZE_THRESHOLD": "4096",
+			// Replace one option, remove another, add a third
+			"TEMPORAL_EXTERNAL_STORAGE_OPTION_DIR":    "/other",
+			"TEMPORAL_EXTERNAL_STORAGE_OPTION_MODE":   "",
+			"TEMPORAL_EXTERNAL_STORAGE_OPTION_BUCKET": "my-bucket",
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &envconfig.ClientConfigCompression{Algorithms: []string{"zstd", "zlib"}, MinSize: 512},
+		prof.Codec.Compression)
+	require.Equal(t, &envconfig.ClientConfigEncryption{
+		CurrentKeyID: "key-2",
+		KeyFiles:     map[string]string{"key-1": "/keys/1", "key-2": "/keys/2"},
+	}, prof.Codec.Encryption)
+	require.Equal(t, &envconfig.ClientConfigPayloadLimits{PayloadSizeWarning: 1000, MemoSizeWarning: 2000},
+		prof.PayloadLimits)
+	require.Equal(t, &envconfig.ClientConfigExternalStorage{
+		Driver:               "s3",
+		Options:              map[string]string{"dir": "/other", "bucket": "my-bucket"},
+		PayloadSizeThreshold: 4096,
+	}, prof.ExternalStorage)
+
+	// Empty compression disables it, even if a minimum size is also set
+	require.NoError(t, prof.ApplyEnvVars(EnvLookupMap{
+		"TEMPORAL_CODEC_COMPRESSION":          "",
+		"TEMPORAL_CODEC_COMPRESSION_MIN_SIZE": "1024",
+	}))
+	require.Nil(t, prof.Codec.Compression)
+
+	// Invalid values
+	require.ErrorContains(t, prof.ApplyEnvVars(EnvLookupMap{"TEMPORAL_MEMO_SIZE_WARNING": "lots"}),
+		"invalid TEMPORAL_MEMO_SIZE_WARNING")
+	require.ErrorContains(t, prof.ApplyEnvVars(EnvLookupMap{"TEMPORAL_CODEC_ENCRYPTION_KEY_FILES": "key-1"}),
+		`invalid TEMPORAL_CODEC_ENCRYPTION_KEY_FILES entry "key-1"`)
+}
+
+func TestLoadClientOptionsDataHandling(t *testing.T) {
+	keyFile := filepath.Join(t.TempDir(), "key")
+	require.NoError(t, os.WriteFile(keyFile, bytes.Repeat([]byte{1}, 32), 0o600))
+	driver := &namedDriver{name: "my-driver"}
+	var driverOptions map[string]string
+	opts, err := envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec.compression]
+		algorithms = ["zlib"]
+		[profile.default.codec.encryption]
+		current_key_id = "key-1"
+		key_files = { key-1 = "` + filepath.ToSlash(keyFile) + `" }
+		[profile.default.payload_limits]
+		payload_size_warning = 1000
+		[profile.default.external_storage]
+		driver = "mine"
+		payload_size_threshold = 4096
+		options = { dir = "/payloads" }`),
+		EnvLookup: EnvLookupMap{},
+		StorageDriverFactories: map[string]envconfig.StorageDriverFactory{
+			"mine": func(options map[string]string) (converter.StorageDriver, error) {
+				driverOptions = options
+				return driver, nil
+			},
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, client.PayloadLimitOptions{PayloadSizeWarning: 1000}, opts.PayloadLimits)
+	require.Equal(t, []converter.StorageDriver{driver}, opts.ExternalStorage.Drivers)
+	require.Equal(t, 4096, opts.ExternalStorage.PayloadSizeThreshold)
+	require.Equal(t, map[string]string{"dir": "/payloads"}, driverOptions)
+
+	// Payloads are compressed, then encrypted
+	payload, err := opts.DataConverter.ToPayload(strings.Repeat("compressible ", 100))
+	require.NoError(t, err)
+	require.Equal(t, converter.MetadataEncodingEncrypted, string(payload.Metadata[converter.MetadataEncoding]))
+	var value string
+	require.NoError(t, opts.DataConverter.FromPayload(payload, &value))
+	require.Equal(t, strings.Repeat("compressible ", 100), value)
+
+	// Custom compressors
+	opts, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec.compression]
+		algorithms = ["custom"]`),
+		EnvLookup:   EnvLookupMap{},
+		Compressors: map[string]converter.PayloadCompressor{"custom": converter.NewZlibCompressor()},
+	})
+	require.NoError(t, err)
+	require.NotNil(t, opts.DataConverter)
+
+	// A remote codec is used in place of the local codecs of the same profile
+	var remoteCalls atomic.Int32
+	codecHandler := converter.NewPayloadCodecHTTPHandler()
+	codecServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		remoteCalls.Add(1)
+		codecHandler.ServeHTTP(w, r)
+	}))
+	defer codecServer.Close()
+	opts, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec]
+		endpoint = "` + codecServer.URL + `"
+		[profile.default.codec.compression]
+		algorithms = ["zlib"]`),
+		EnvLookup:          EnvLookupMap{},
+		IncludeRemoteCodec: true,
+	})
+	require.NoError(t, err)
+	payload, err = opts.DataConverter.ToPayload(strings.Repeat("compressible ", 100))
+	require.NoError(t, err)
+	require.Equal(t, converter.MetadataEncodingJSON, string(payload.Metadata[converter.MetadataEncoding]))
+	require.Equal(t, int32(1), remoteCalls.Load())
+
+	// Errors
+	_, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec.compression]
+		algorithms = ["custom"]`),
+		EnvLookup: EnvLookupMap{},
+	})
+	require.ErrorContains(t, err, `unknown algorithm "custom"`)
+	_, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec.encryption]
+		current_key_id = "key-1"
+		key_files = { key-1 = "/does/not/exist" }`),
+		EnvLookup: EnvLookupMap{},
+	})
+	require.ErrorContains(t, err, `failed reading key file for key "key-1"`)
+
+	// Remote codecs need an endpoint
+	_, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.codec.compression]
+		algorithms = ["zlib"]`),
+		EnvLookup:          EnvLookupMap{},
+		IncludeRemoteCodec: true,
+	})
+	require.ErrorContains(t, err, "remote codec requested but no endpoint configured")
+	_, err = envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{
+		ConfigFileData: []byte(`
+		[profile.default.external_storage]
+		driver = "unknown"`),
+		EnvLookup: EnvLookupMap{},
+	})
+	require.ErrorContains(t, err, `no storage driver factory for driver "unknown"`)
+}
+
+type namedDriver struct {
+	converter.StorageDriver
+	name string
+}
+
+func (d *namedDriver) Name() string { return d.name }
+
 func TestLoadDefaultConfigFileNotExist(t *testing.T) {
 	// Unset default user config dir.
 	switch runtime.GOOS {
@@ -12,12 +12,14 @@ import (
 // This must be kept in sync with tomlClientConfigProfile's TOML tags.
 // See TestKnownProfileKeysInSync for validation.
 var knownProfileKeys = map[string]bool{
-	"address":   true,
-	"namespace": true,
-	"api_key":   true,
-	"tls":       true,
-	"codec":     true,
-	"grpc_meta": true,
+	"address":          true,
+	"namespace":        true,
+	"api_key":          true,
+	"tls":              true,
+	"codec":            true,
+	"grpc_meta":        true,
+	"payload_limits":   true,
+	"external_storage": true,
 }
 
 // ClientConfigToTOMLOptions are options for [ClientConfig.ToTOML].
@@ -157,21 +159,25 @@ func (c *tomlClientConfig) fromClientConfig(conf *ClientConfig) {
 }
 
 type tomlClientConfigProfile struct {
-	Address   string                 `toml:"address,omitempty"`
-	Namespace string                 `toml:"namespace,omitempty"`
-	APIKey    string                 `toml:"api_key,omitempty"`
-	TLS       *tomlClientConfigTLS   `toml:"tls,omitempty"`
-	Codec     *tomlClientConfigCodec `toml:"codec,omitempty"`
-	GRPCMeta  map[string]string      `toml:"grpc_meta,omitempty"`
+	Address         string                           `toml:"address,omitempty"`
+	Namespace       string                           `toml:"namespace,omitempty"`
+	APIKey          string                           `toml:"api_key,omitempty"`
+	TLS             *tomlClientConfigTLS             `toml:"tls,omitempty"`
+	Codec           *tomlClientConfigCodec           `toml:"codec,omitempty"`
+	GRPCMeta        map[string]string                `toml:"grpc_meta,omitempty"`
+	PayloadLimits   *tomlClientConfigPayloadLimits   `toml:"payload_limits,omitempty"`
+	ExternalStorage *tomlClientConfigExternalStorage `toml:"external_storage,omitempty"`
 }
 
 func (c *tomlClientConfigProfile) toClientConfig() *ClientConfigProfile {
 	ret := &ClientConfigProfile{
-		Address:   c.Address,
-		Namespace: c.Namespace,
-		APIKey:    c.APIKey,
-		TLS:       c.TLS.toClientConfig(),
-		Codec:     c.Codec.toClientConfig(),
+		Address:         c.Address,
+		Namespace:       c.Namespace,
+		APIKey:          c.APIKey,
+		TLS:             c.TLS.toClientConfig(),
+		Codec:           c.Codec.toClientConfig(),
+		PayloadLimits:   c.PayloadLimits.toClientConfig(),
+		ExternalStorage: c.ExternalStorage.toClientConfig(),
 	}
 	// gRPC meta keys have to be normalized
 	if len(c.GRPCMeta) > 0 {
@@ -195,6 +201,14 @@ func (c *tomlClientConfigProfile) fromClientConfig(conf *ClientConfigProfile) {
 		c.Codec = &tomlClientConfigCodec{}
 		c.Codec.fromClientConfig(conf.Codec)
 	}
+	if conf.PayloadLimits != nil {
+		c.PayloadLimits = &tomlClientConfigPayloadLimits{}
+		c.PayloadLimits.fromClientConfig(conf.PayloadLimits)
+	}
+	if conf.ExternalStorage != nil {
+		c.ExternalStorage = &tomlClientConfigExternalStorage{}
+		c.ExternalStorage.fromClientConfig(conf.ExternalStorage)
+	}
 	// gRPC meta keys have to be normalized (we can mutate receiver, it's only used ephemerally)
 	if len(conf.GRPCMeta) > 0 {
 		c.GRPCMeta = make(map[string]string, len(conf.GRPCMeta))
@@ -257,18 +271,121 @@ func (c *tomlClientConfigTLS) fromClientConfig(conf *ClientConfigTLS) {
 }
 
 type tomlClientConfigCodec struct {
-	Endpoint string `toml:"endpoint,omitempty"`
-	Auth     string `toml:"auth,omitempty"`
+	Endpoint    string                       `toml:"endpoint,omitempty"`
+	Auth        string                       `toml:"auth,omitempty"`
+	Compression *tomlClientConfigCompression `toml:"compression,omitempty"`
+	Encryption  *tomlClientConfigEncryption  `toml:"encryption,omitempty"`
 }
 
 func (c *tomlClientConfigCodec) toClientConfig() *ClientConfigCodec {
 	if c == nil {
 		return nil
 	}
-	return &ClientConfigCodec{Endpoint: c.Endpoint, Auth: c.Auth}
+	return &ClientConfigCodec{
+		Endpoint:    c.Endpoint,
+		Auth:        c.Auth,
+		Compression: c.Compression.toClientConfig(),
+		Encryption:  c.Encryption.toClientConfig(),
+	}
 }
 
 func (c *tomlClientConfigCodec) fromClientConfig(conf *ClientConfigCodec) {
 	c.Endpoint = conf.Endpoint
 	c.Auth = conf.Auth
+	if conf.Compression != nil {
+		c.Compression = &tomlClientConfigCompression{}
+		c.Compression.fromClientConfig(conf.Compression)
+	}
+	if conf.Encryption != nil {
+		c.Encryption = &tomlClientConfigEncryption{}
+		c.Encryption.fromClientConfig(conf.Encryption)
+	}
+}
+
+type tomlClientConfigCompression struct {
+	Algorithms   []string `toml:"algorithms,omitempty"`
+	MinSize      int      `toml:"min_size,omitempty"`
+	AlwaysEncode bool     `toml:"always_encode,omitempty"`
+}
+
+func (c *tomlClientConfigCompression) toClientConfig() *ClientConfigCompression {
+	if c == nil {
+		return nil
+	}
+	return &ClientConfigCompression{Algorithms: c.Algorithms, MinSize: c.MinSize, AlwaysEncode: c.AlwaysEncode}
+}
+
+func (c *tomlClientConfigCompression) fromClientConfig(conf *ClientConfigCompression) {
+	c.Algorithms = conf.Algorithms
+	c.MinSize = conf.MinSize
+	c.AlwaysEncode = conf.AlwaysEncode
+}
+
+type tomlClientConfigEncryption struct {
+	CurrentKeyID string            `toml:"current_key_id,omitempty"`
+	KeyFiles     map[string]string `toml:"key_files,omitempty"`
+}
+
+func (c *tomlClientConfigEncryption) toClientConfig() *ClientConfigEncryption {
+	if c == nil {
+		return nil
+	}
+	return &ClientConfigEncryption{CurrentKeyID: c.CurrentKeyID, KeyFiles: c.KeyFiles}
+}
+
+func (c *tomlClientConfigEncryption) fromClientConfig(conf *ClientConfigEncryption) {
+	c.CurrentKeyID = conf.CurrentKeyID
+	c.KeyFiles = conf.KeyFiles
+}
+
+type tomlClientConfigPayloadLimits struct {
+	PayloadSizeWarning int `toml:"payload_size_warning,omitempty"`
+	MemoSizeWarning    int `toml:"memo_size_warning,omitempty"`
+}
+
+func (c *tomlClientConfigPayloadLimits) toClientConfig() *ClientConfigPayloadLimits {
+	if c == nil {
+		return nil
+	}
+	return &ClientConfigPayloadLimits{PayloadSizeWarning: c.PayloadSizeWarning, MemoSizeWarning: c.MemoSizeWarning}
+}
+
+func (c *tomlClientConfigPayloadLimits) fromClientConfig(conf *ClientConfigPayloadLimits) {
+	c.PayloadSizeWarning = conf.PayloadSizeWarning
+	c.MemoSizeWarning = conf.MemoSizeWarning
+}
+
+type tomlClientConfigExternalStorage struct {
+	Driver               string            `toml:"driver,omitempty"`
+	Options              map[string]string `toml:"options,omitempty"`
+	PayloadSizeThreshold int               `toml:"payload_size_threshold,omitempty"`
+}
+
+func (c *tomlClientConfigExternalStorage) toClientConfig() *ClientConfigExternalStorage {
+	if c == nil {
+		return nil
+	}
+	return &ClientConfigExternalStorage{
+		Driver:               c.Driver,
+		Options:              normalizeStorageOptions(c.Options),
+		PayloadSizeThreshold: c.PayloadSizeThreshold,
+	}
+}
+
+func (c *tomlClientConfigExternalStorage) fromClientConfig(conf *ClientConfigExternalStorage) {
+	c.Driver = conf.Driver
+	c.Options = normalizeStorageOptions(conf.Options)
+	c.PayloadSizeThreshold = conf.PayloadSizeThreshold
+}
+
+// normalizeStorageOptions lowercases option keys, returning nil for no options.
+func normalizeStorageOptions(options map[string]string) map[string]string {
+	if len(options) == 0 {
+		return nil
+	}
+	ret := make(map[string]string, len(options))
+	for k, v := range options {
+		ret[strings.ToLower(k)] = v
+	}
+	return ret
 }
@@ -59,6 +59,51 @@ disable_host_verification = true`
 	require.NotEqual(t, conf, newConf)
 }
 
+func TestClientConfigTOMLDataHandling(t *testing.T) {
+	data := `
+[profile.foo.codec.compression]
+algorithms = ["zstd", "zlib"]
+min_size = 1024
+always_encode = true
+
+[profile.foo.codec.encryption]
+current_key_id = "key-2"
+key_files = { key-1 = "/keys/1", key-2 = "/keys/2" }
+
+[profile.foo.payload_limits]
+payload_size_warning = 1000
+memo_size_warning = 2000
+
+[profile.foo.external_storage]
+driver = "fs"
+payload_size_threshold = 4096
+options = { Dir = "/payloads" }`
+
+	var conf ClientConfig
+	require.NoError(t, conf.FromTOML([]byte(data), ClientConfigFromTOMLOptions{Strict: true}))
+	prof := conf.Profiles["foo"]
+	require.Equal(t, &ClientConfigCodec{
+		Compression: &ClientConfigCompression{Algorithms: []string{"zstd", "zlib"}, MinSize: 1024, AlwaysEncode: true},
+		Encryption: &ClientConfigEncryption{
+			CurrentKeyID: "key-2",
+			KeyFiles:     map[string]string{"key-1": "/keys/1", "key-2": "/keys/2"},
+		},
+	}, prof.Codec)
+	require.Equal(t, &ClientConfigPayloadLimits{PayloadSizeWarning: 1000, MemoSizeWarning: 2000}, prof.PayloadLimits)
+	require.Equal(t, &ClientConfigExternalStorage{
+		Driver:               "fs",
+		Options:              map[string]string{"dir": "/payloads"},
+		PayloadSizeThreshold: 4096,
+	}, prof.ExternalStorage)
+
+	// Back to toml and back to structure again, then deep equality check
+	b, err := conf.ToTOML(ClientConfigToTOMLOptions{})
+	require.NoError(t, err)
+	var newConf ClientConfig
+	require.NoError(t, newConf.FromTOML(b, ClientConfigFromTOMLOptions{Strict: true}))
+	require.Equal(t, conf, newConf)
+}
+
 func TestClientConfigTOMLStrict(t *testing.T) {
 	data := `
 [unimportant]
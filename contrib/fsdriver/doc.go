@@ -0,0 +1,15 @@
+// Package fsdriver provides a filesystem-backed [go.temporal.io/sdk/converter.StorageDriver]
+// for the Temporal Go SDK's external payload storage system. Large payloads are
+// written to a local or network-mounted directory tree using
+// content-addressable paths derived from their SHA-256 hash.
+//
+// # Usage
+//
+// Construct a driver using [NewDriver] with an [Options] struct. The
+// [Options.Root] field names the directory under which payloads are written;
+// it must already exist. Every component that retrieves payloads stored by
+// this driver must see the same directory tree, so use a shared mount (e.g.
+// NFS) when clients and workers run on different hosts.
+//
+// NOTE: Experimental
+package fsdriver
@@ -0,0 +1,278 @@
+package fsdriver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+)
+
+const (
+	defaultMaxPayloadSize = 50 * 1024 * 1024 // 50 MiB
+	driverType            = "fsdriver"
+	defaultDriverName     = "fsdriver"
+
+	defaultDirPerm  os.FileMode = 0o750
+	defaultFilePerm os.FileMode = 0o640
+
+	// maxComponentLength is the length in bytes above which a path component
+	// is shortened. It leaves room for the temporary file prefix and suffix
+	// within the 255 byte name limit of common filesystems.
+	maxComponentLength = 128
+	// shortenedPrefixLength is the number of bytes of a shortened path
+	// component that are kept in front of its hash.
+	shortenedPrefixLength = 32
+
+	claimKeyRoot = "root"
+	claimKeyKey  = "key"
+)
+
+// Options configures the filesystem storage driver.
+//
+// NOTE: Experimental
+type Options struct {
+	// Root is the directory under which payloads are stored. It must already
+	// exist; the driver creates subdirectories beneath it as needed. Required.
+	Root string
+
+	// DriverName is a stable, unique identifier for this driver instance.
+	// Defaults to "fsdriver".
+	DriverName string
+
+	// MaxPayloadSize is the maximum serialized payload size in bytes that
+	// the driver will accept. Defaults to 50 MiB.
+	MaxPayloadSize int
+
+	// DirPerm is the permission used when creating subdirectories of Root.
+	// Defaults to 0750.
+	DirPerm os.FileMode
+
+	// FilePerm is the permission of payload files written by the driver.
+	// Defaults to 0640.
+	FilePerm os.FileMode
+}
+
+// objectStore stores objects as files beneath root. The container of every
+// object is root, which is recorded in claims for diagnostics only: objects
+// are always resolved beneath the root of the retrieving driver, so hosts may
+// mount the directory tree at different paths.
+type objectStore struct {
+	root     string
+	dirPerm  os.FileMode
+	filePerm os.FileMode
+}
+
+// Compile-time checks that objectStore supports storage and deletion.
+var (
+	_ extstore.ObjectStore        = (*objectStore)(nil)
+	_ extstore.ObjectStoreDeleter = (*objectStore)(nil)
+)
+
+// NewDriver creates a new filesystem StorageDriver with the given options. The
+// driver stores payloads as files using content-addressable paths based on
+// SHA-256 hashes, and implements converter.StorageDriverDeleter.
+//
+// NOTE: Experimental
+func NewDriver(opts Options) (converter.StorageDriver, error) {
+	if opts.Root == "" {
+		return nil, errors.New("Root is required")
+	}
+	root, err := filepath.Abs(opts.Root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve Root: %w", err)
+	}
+	info, err := os.Stat(root)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat Root: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("Root %q is not a directory", root)
+	}
+	name := opts.DriverName
+	if name == "" {
+		name = defaultDriverName
+	}
+	maxSize := opts.MaxPayloadSize
+	if maxSize == 0 {
+		maxSize = defaultMaxPayloadSize
+	}
+	if maxSize < 0 {
+		return nil, fmt.Errorf("MaxPayloadSize must be positive, got %d", maxSize)
+	}
+	dirPerm := opts.DirPerm
+	if dirPerm == 0 {
+		dirPerm = defaultDirPerm
+	}
+	filePerm := opts.FilePerm
+	if filePerm == 0 {
+		filePerm = defaultFilePerm
+	}
+	return extstore.NewObjectStoreDriver(extstore.ObjectStoreDriverOptions{
+		Store: &objectStore{root: root, dirPerm: dirPerm, filePerm: filePerm},
+		Container: func(converter.StorageDriverStoreContext, *commonpb.Payload) string {
+			return root
+		},
+		DriverName:        name,
+		DriverType:        driverType,
+		MaxPayloadSize:    maxSize,
+		ContainerClaimKey: claimKeyRoot,
+		NameClaimKey:      claimKeyKey,
+	}), nil
+}
+
+func (s *objectStore) ObjectExists(ctx context.Context, _, key string) (bool, error) {
+	if err := ctx.Err(); err != nil {
+		return false, err
+	}
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return false, err
+	}
+	if _, err := os.Stat(fullPath); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// PutObject atomically writes data to the file of key. The data is first
+// written to a temporary file in the destination directory, flushed to stable
+// storage, and then renamed into place, so readers never observe a partially
+// written file.
+func (s *objectStore) PutObject(ctx context.Context, _, key string, data []byte) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	dir := filepath.Dir(fullPath)
+	if err := os.MkdirAll(dir, s.dirPerm); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(dir, ".tmp-"+filepath.Base(fullPath)+"-*")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	committed := false
+	defer func() {
+		if !committed {
+			_ = os.Remove(tmpName)
+		}
+	}()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpName, s.filePerm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, fullPath); err != nil {
+		return err
+	}
+	committed = true
+	return nil
+}
+
+func (s *objectStore) GetObject(ctx context.Context, _, key string, _ converter.StorageDriverClaim) ([]byte, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return nil, err
+	}
+	return os.ReadFile(fullPath)
+}
+
+// DeleteObject removes the file of key. Directories left empty by the deletion
+// are not removed.
+func (s *objectStore) DeleteObject(ctx context.Context, _, key string, _ converter.StorageDriverClaim) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	fullPath, err := s.resolve(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(fullPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+func (s *objectStore) Describe() map[string]string {
+	return nil
+}
+
+// resolve maps a slash-separated object key onto a path beneath root,
+// rejecting keys that would escape it. Each component of the key is mapped
+// with pathComponent.
+func (s *objectStore) resolve(key string) (string, error) {
+	components := strings.Split(key, "/")
+	for i, c := range components {
+		if c == "" {
+			return "", fmt.Errorf("invalid storage path %q", key)
+		}
+		components[i] = pathComponent(c)
+	}
+	local := filepath.Join(components...)
+	if !filepath.IsLocal(local) {
+		return "", fmt.Errorf("invalid storage path %q", key)
+	}
+	return filepath.Join(s.root, local), nil
+}
+
+// pathComponent maps a component of an object key onto a directory or file
+// name that is safe on any filesystem:
+//   - The relative path elements "." and ".." are escaped so that identifiers
+//     cannot traverse the directory tree.
+//   - Upper-case letters are replaced with "!" followed by the lower-case
+//     letter, so that identifiers differing only in case map to different
+//     names on case-insensitive filesystems. Object keys are percent-encoded,
+//     so they never contain "!" themselves.
+//   - Names longer than maxComponentLength are shortened to a prefix followed
+//     by "!!" and the SHA-256 hash of the component. "!!" does not occur in
+//     names that are not shortened.
+func pathComponent(c string) string {
+	switch c {
+	case ".":
+		return "%2E"
+	case "..":
+		return "%2E%2E"
+	}
+	var b strings.Builder
+	for _, r := range c {
+		if 'A' <= r && r <= 'Z' {
+			b.WriteByte('!')
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if len(name) <= maxComponentLength {
+		return name
+	}
+	h := sha256.Sum256([]byte(c))
+	return name[:shortenedPrefixLength] + "!!" + hex.EncodeToString(h[:])
+}
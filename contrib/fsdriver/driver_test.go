@@ -0,0 +1,256 @@
+package fsdriver
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+func testPayload(data string) *commonpb.Payload {
+	return &commonpb.Payload{
+		Metadata: map[string][]byte{"encoding": []byte("binary/plain")},
+		Data:     []byte(data),
+	}
+}
+
+func newDriver(t *testing.T) (converter.StorageDriver, string) {
+	t.Helper()
+	root := t.TempDir()
+	d, err := NewDriver(Options{Root: root})
+	require.NoError(t, err)
+	return d, root
+}
+
+func storeCtx() converter.StorageDriverStoreContext {
+	return converter.StorageDriverStoreContext{Context: context.Background()}
+}
+
+func storeCtxWithTarget(target converter.StorageDriverTargetInfo) converter.StorageDriverStoreContext {
+	return converter.StorageDriverStoreContext{Context: context.Background(), Target: target}
+}
+
+func retrieveCtx() converter.StorageDriverRetrieveContext {
+	return converter.StorageDriverRetrieveContext{Context: context.Background()}
+}
+
+func deleteCtx() converter.StorageDriverDeleteContext {
+	return converter.StorageDriverDeleteContext{Context: context.Background()}
+}
+
+// --- Constructor tests ---
+
+func TestNewDriver_Defaults(t *testing.T) {
+	d, root := newDriver(t)
+	typedDriver, ok := d.(*extstore.ObjectStoreDriver)
+	require.True(t, ok, "expected *extstore.ObjectStoreDriver, got %T", d)
+	assert.Equal(t, "fsdriver", typedDriver.Name())
+	assert.Equal(t, "fsdriver", typedDriver.Type())
+	assert.Equal(t, 50*1024*1024, typedDriver.MaxPayloadSize())
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("perm")})
+	require.NoError(t, err)
+	info, err := os.Stat(filepath.Join(root, filepath.FromSlash(claims[0].ClaimData["key"])))
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o640), info.Mode().Perm())
+}
+
+func TestNewDriver_CustomName(t *testing.T) {
+	d, err := NewDriver(Options{Root: t.TempDir(), DriverName: "custom-name"})
+	require.NoError(t, err)
+	assert.Equal(t, "custom-name", d.Name())
+}
+
+func TestNewDriver_MissingRoot(t *testing.T) {
+	_, err := NewDriver(Options{})
+	assert.EqualError(t, err, "Root is required")
+}
+
+func TestNewDriver_NonexistentRoot(t *testing.T) {
+	_, err := NewDriver(Options{Root: filepath.Join(t.TempDir(), "missing")})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestNewDriver_RootIsFile(t *testing.T) {
+	f := filepath.Join(t.TempDir(), "file")
+	require.NoError(t, os.WriteFile(f, nil, 0o600))
+	_, err := NewDriver(Options{Root: f})
+	assert.ErrorContains(t, err, "is not a directory")
+}
+
+func TestNewDriver_NegativeMaxPayloadSize(t *testing.T) {
+	_, err := NewDriver(Options{Root: t.TempDir(), MaxPayloadSize: -1})
+	assert.EqualError(t, err, "MaxPayloadSize must be positive, got -1")
+}
+
+// --- Store, Retrieve and Delete tests ---
+
+func TestStore_WritesFile(t *testing.T) {
+	d, root := newDriver(t)
+	p := testPayload("hello")
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+
+	data, err := proto.Marshal(p)
+	require.NoError(t, err)
+	hash := claims[0].ClaimData["hash_value"]
+	assert.Equal(t, root, claims[0].ClaimData["root"])
+	assert.Equal(t, "v0/d/sha256/"+hash, claims[0].ClaimData["key"])
+
+	onDisk, err := os.ReadFile(filepath.Join(root, "v0", "d", "sha256", hash))
+	require.NoError(t, err)
+	assert.Equal(t, data, onDisk)
+}
+
+func TestStore_NoTemporaryFilesLeftBehind(t *testing.T) {
+	d, root := newDriver(t)
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a"), testPayload("b")})
+	require.NoError(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(root, "v0", "d", "sha256"))
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	for _, e := range entries {
+		assert.NotContains(t, e.Name(), ".tmp-")
+	}
+}
+
+func TestStore_ConcurrentSamePayload(t *testing.T) {
+	d, _ := newDriver(t)
+	p := testPayload("contended")
+
+	var wg sync.WaitGroup
+	for range 16 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+			assert.NoError(t, err)
+		}()
+	}
+	wg.Wait()
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(p, got[0]))
+}
+
+func TestStore_CanceledContext(t *testing.T) {
+	d, _ := newDriver(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := d.Store(converter.StorageDriverStoreContext{Context: ctx}, []*commonpb.Payload{testPayload("x")})
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStore_LongIdentifiers(t *testing.T) {
+	d, _ := newDriver(t)
+	target := converter.StorageDriverWorkflowInfo{
+		Namespace:    "ns",
+		WorkflowType: "Workflow",
+		// Percent-encoding triples the length of the ID
+		WorkflowID: strings.Repeat("/", 1000),
+		RunID:      "run",
+	}
+	p := testPayload("long")
+	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	got, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(p, got[0]))
+}
+
+func TestDelete_IdentifiersDifferingInCase(t *testing.T) {
+	d, _ := newDriver(t)
+	p := testPayload("shared")
+	upper, err := d.Store(storeCtxWithTarget(converter.StorageDriverWorkflowInfo{WorkflowID: "Foo"}), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	lower, err := d.Store(storeCtxWithTarget(converter.StorageDriverWorkflowInfo{WorkflowID: "foo"}), []*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	require.NoError(t, d.(converter.StorageDriverDeleter).Delete(deleteCtx(), upper))
+	got, err := d.Retrieve(retrieveCtx(), lower)
+	require.NoError(t, err)
+	assert.True(t, proto.Equal(p, got[0]))
+}
+
+func TestRetrieve_MissingFile(t *testing.T) {
+	d, _ := newDriver(t)
+	_, err := d.Retrieve(retrieveCtx(), []converter.StorageDriverClaim{{
+		ClaimData: map[string]string{
+			"root":           "/",
+			"key":            "v0/d/sha256/deadbeef",
+			"hash_algorithm": "sha256",
+			"hash_value":     "deadbeef",
+		},
+	}})
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
+
+func TestRetrieve_InvalidKey(t *testing.T) {
+	d, _ := newDriver(t)
+	for _, key := range []string{"/etc/passwd", "v0//outside", ""} {
+		_, err := d.Retrieve(retrieveCtx(), []converter.StorageDriverClaim{{
+			ClaimData: map[string]string{
+				"root":           "/",
+				"key":            key,
+				"hash_algorithm": "sha256",
+				"hash_value":     "abc",
+			},
+		}})
+		assert.ErrorContains(t, err, "invalid storage path", key)
+	}
+}
+
+func TestDelete_RemovesFiles(t *testing.T) {
+	d, root := newDriver(t)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a"), testPayload("b")})
+	require.NoError(t, err)
+
+	deleter, ok := d.(converter.StorageDriverDeleter)
+	require.True(t, ok)
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+	for _, c := range claims {
+		_, err := os.Stat(filepath.Join(root, filepath.FromSlash(c.ClaimData["key"])))
+		assert.ErrorIs(t, err, os.ErrNotExist)
+	}
+
+	// Deleting again is not an error.
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+}
+
+// --- Path tests ---
+
+func TestPathComponent(t *testing.T) {
+	assert.Equal(t, "run-1", pathComponent("run-1"))
+	assert.Equal(t, "%2E", pathComponent("."))
+	assert.Equal(t, "%2E%2E", pathComponent(".."))
+	assert.Equal(t, "!my!workflow", pathComponent("MyWorkflow"))
+	assert.NotEqual(t, pathComponent("Foo"), pathComponent("foo"))
+
+	long := pathComponent(strings.Repeat("a", 1000))
+	assert.LessOrEqual(t, len(long), maxComponentLength)
+	assert.Contains(t, long, "!!")
+	assert.NotEqual(t, long, pathComponent(strings.Repeat("a", 999)))
+}
+
+func TestResolve_RelativePathElements(t *testing.T) {
+	s := &objectStore{root: "/root"}
+	fullPath, err := s.resolve("v0/ns/../wt/./wi/..%2F../d/sha256/abc")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.FromSlash("/root/v0/ns/%2E%2E/wt/%2E/wi/..%2!f../d/sha256/abc"), fullPath)
+}
@@ -0,0 +1,36 @@
+package gcsdriver
+
+import "context"
+
+// Client is the interface that the driver uses to interact with Google Cloud
+// Storage. It covers the operations the driver needs: put, existence check,
+// get, and delete. Use [go.temporal.io/sdk/contrib/gcp/gcsdriver/gcpsdk.NewClient]
+// to obtain an implementation backed by the Google Cloud Storage client
+// library, or supply a custom implementation for testing.
+//
+// NOTE: Experimental
+type Client interface {
+	// PutObject uploads data to the given bucket and object name. If an object
+	// already exists with that name it should be overwritten. Implementations
+	// must be safe to call concurrently for different names.
+	PutObject(ctx context.Context, bucket, name string, data []byte) error
+
+	// ObjectExists reports whether an object exists with the given bucket and
+	// name. It should return (false, nil) when the object is absent, and a
+	// non-nil error only when the existence of the object cannot be determined
+	// (e.g. a network or permission failure).
+	ObjectExists(ctx context.Context, bucket, name string) (bool, error)
+
+	// GetObject downloads and returns the data stored in the given bucket
+	// under name. It must return a non-nil error if the object does not exist.
+	GetObject(ctx context.Context, bucket, name string) ([]byte, error)
+
+	// DeleteObject removes the object stored in the given bucket under name.
+	// It should return nil if the object does not exist.
+	DeleteObject(ctx context.Context, bucket, name string) error
+
+	// Describe returns diagnostic metadata about the client configuration,
+	// such as {"client_project": "my-project"}, that the driver appends to
+	// error messages. Return nil or an empty map if no metadata is available.
+	Describe() map[string]string
+}
@@ -0,0 +1,118 @@
+package gcsdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+)
+
+const (
+	defaultMaxPayloadSize = 50 * 1024 * 1024 // 50 MiB
+	driverType            = "gcp.gcsdriver"
+	defaultDriverName     = "gcp.gcsdriver"
+
+	claimKeyBucket = "bucket"
+	claimKeyName   = "name"
+)
+
+// BucketFunc resolves the target GCS bucket for a given payload. Use
+// StaticBucket for a fixed bucket name.
+//
+// NOTE: Experimental
+type BucketFunc func(ctx converter.StorageDriverStoreContext, payload *commonpb.Payload) string
+
+// StaticBucket returns a BucketFunc that always returns the given bucket name.
+//
+// NOTE: Experimental
+func StaticBucket(name string) BucketFunc {
+	return func(_ converter.StorageDriverStoreContext, _ *commonpb.Payload) string { return name }
+}
+
+// Options configures the GCS storage driver.
+//
+// NOTE: Experimental
+type Options struct {
+	// Client is the GCS client used for storage operations. Required.
+	Client Client
+
+	// Bucket resolves the target bucket for each payload. Required.
+	// Use StaticBucket("my-bucket") for a fixed bucket.
+	Bucket BucketFunc
+
+	// DriverName is a stable, unique identifier for this driver instance.
+	// Defaults to "gcp.gcsdriver".
+	DriverName string
+
+	// MaxPayloadSize is the maximum serialized payload size in bytes that
+	// the driver will accept. Defaults to 50 MiB.
+	MaxPayloadSize int
+}
+
+// NewDriver creates a new GCS StorageDriver with the given options. The
+// driver stores payloads in Google Cloud Storage using content-addressable
+// names based on SHA-256 hashes, and implements converter.StorageDriverDeleter.
+//
+// NOTE: Experimental
+func NewDriver(opts Options) (converter.StorageDriver, error) {
+	if opts.Client == nil {
+		return nil, errors.New("Client is required")
+	}
+	if opts.Bucket == nil {
+		return nil, errors.New("Bucket is required")
+	}
+	name := opts.DriverName
+	if name == "" {
+		name = defaultDriverName
+	}
+	maxSize := opts.MaxPayloadSize
+	if maxSize == 0 {
+		maxSize = defaultMaxPayloadSize
+	}
+	if maxSize < 0 {
+		return nil, fmt.Errorf("MaxPayloadSize must be positive, got %d", maxSize)
+	}
+	return extstore.NewObjectStoreDriver(extstore.ObjectStoreDriverOptions{
+		Store:             objectStore{client: opts.Client},
+		Container:         opts.Bucket,
+		DriverName:        name,
+		DriverType:        driverType,
+		MaxPayloadSize:    maxSize,
+		ContainerClaimKey: claimKeyBucket,
+		NameClaimKey:      claimKeyName,
+	}), nil
+}
+
+// objectStore adapts a Client to the object store the driver is built on.
+type objectStore struct {
+	client Client
+}
+
+// Compile-time checks that objectStore supports storage and deletion.
+var (
+	_ extstore.ObjectStore        = objectStore{}
+	_ extstore.ObjectStoreDeleter = objectStore{}
+)
+
+func (s objectStore) ObjectExists(ctx context.Context, bucket, name string) (bool, error) {
+	return s.client.ObjectExists(ctx, bucket, name)
+}
+
+func (s objectStore) PutObject(ctx context.Context, bucket, name string, data []byte) error {
+	return s.client.PutObject(ctx, bucket, name, data)
+}
+
+func (s objectStore) GetObject(ctx context.Context, bucket, name string, _ converter.StorageDriverClaim) ([]byte, error) {
+	return s.client.GetObject(ctx, bucket, name)
+}
+
+func (s objectStore) DeleteObject(ctx context.Context, bucket, name string, _ converter.StorageDriverClaim) error {
+	return s.client.DeleteObject(ctx, bucket, name)
+}
+
+func (s objectStore) Describe() map[string]string {
+	return s.client.Describe()
+}
@@ -0,0 +1,139 @@
+package gcsdriver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+)
+
+// fakeClient is an in-memory Client that fails every call with err, if set.
+// The storage logic shared by the object store drivers is tested in
+// go.temporal.io/sdk/internal/extstore, so these tests only cover the use of
+// the options and of Client.
+type fakeClient struct {
+	mu      sync.Mutex
+	objects map[[2]string][]byte // key: bucket, name
+	err     error
+}
+
+func (c *fakeClient) PutObject(_ context.Context, bucket, name string, data []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return c.err
+	}
+	if c.objects == nil {
+		c.objects = map[[2]string][]byte{}
+	}
+	c.objects[[2]string{bucket, name}] = data
+	return nil
+}
+
+func (c *fakeClient) ObjectExists(_ context.Context, bucket, name string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.objects[[2]string{bucket, name}]
+	return ok, c.err
+}
+
+func (c *fakeClient) GetObject(_ context.Context, bucket, name string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.err != nil {
+		return nil, c.err
+	}
+	data, ok := c.objects[[2]string{bucket, name}]
+	if !ok {
+		return nil, fmt.Errorf("object %s/%s not found", bucket, name)
+	}
+	return data, nil
+}
+
+func (c *fakeClient) DeleteObject(_ context.Context, bucket, name string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.objects, [2]string{bucket, name})
+	return c.err
+}
+
+func (c *fakeClient) Describe() map[string]string {
+	return map[string]string{"client_project": "test-project"}
+}
+
+func TestNewDriver(t *testing.T) {
+	d, err := NewDriver(Options{Client: &fakeClient{}, Bucket: StaticBucket("b")})
+	require.NoError(t, err)
+	typedDriver, ok := d.(*extstore.ObjectStoreDriver)
+	require.True(t, ok, "expected *extstore.ObjectStoreDriver, got %T", d)
+	assert.Equal(t, "gcp.gcsdriver", typedDriver.Name())
+	assert.Equal(t, "gcp.gcsdriver", typedDriver.Type())
+	assert.Equal(t, 50*1024*1024, typedDriver.MaxPayloadSize())
+
+	d, err = NewDriver(Options{Client: &fakeClient{}, Bucket: StaticBucket("b"), DriverName: "custom-name", MaxPayloadSize: 1024})
+	require.NoError(t, err)
+	assert.Equal(t, "custom-name", d.Name())
+	assert.Equal(t, 1024, d.(*extstore.ObjectStoreDriver).MaxPayloadSize())
+
+	_, err = NewDriver(Options{Bucket: StaticBucket("b")})
+	assert.EqualError(t, err, "Client is required")
+	_, err = NewDriver(Options{Client: &fakeClient{}})
+	assert.EqualError(t, err, "Bucket is required")
+	_, err = NewDriver(Options{Client: &fakeClient{}, Bucket: StaticBucket("b"), MaxPayloadSize: -1})
+	assert.EqualError(t, err, "MaxPayloadSize must be positive, got -1")
+}
+
+func TestDriver_Buckets(t *testing.T) {
+	client := &fakeClient{}
+	d, err := NewDriver(Options{
+		Client: client,
+		Bucket: func(_ converter.StorageDriverStoreContext, payload *commonpb.Payload) string {
+			return "bucket-" + string(payload.GetData())
+		},
+	})
+	require.NoError(t, err)
+	payloads := []*commonpb.Payload{{Data: []byte("a")}, {Data: []byte("b")}}
+
+	claims, err := d.Store(converter.StorageDriverStoreContext{Context: context.Background()}, payloads)
+	require.NoError(t, err)
+	for i, bucket := range []string{"bucket-a", "bucket-b"} {
+		assert.Equal(t, bucket, claims[i].ClaimData["bucket"])
+		data, err := proto.Marshal(payloads[i])
+		require.NoError(t, err)
+		assert.Equal(t, data, client.objects[[2]string{bucket, claims[i].ClaimData["name"]}])
+	}
+
+	retrieved, err := d.Retrieve(converter.StorageDriverRetrieveContext{Context: context.Background()}, claims)
+	require.NoError(t, err)
+	for i := range payloads {
+		assert.True(t, proto.Equal(payloads[i], retrieved[i]))
+	}
+
+	require.NoError(t, d.(converter.StorageDriverDeleter).Delete(converter.StorageDriverDeleteContext{Context: context.Background()}, claims))
+	assert.Empty(t, client.objects)
+}
+
+func TestDriver_ClientErrors(t *testing.T) {
+	client := &fakeClient{}
+	d, err := NewDriver(Options{Client: client, Bucket: StaticBucket("b")})
+	require.NoError(t, err)
+	claims, err := d.Store(converter.StorageDriverStoreContext{Context: context.Background()}, []*commonpb.Payload{{Data: []byte("x")}})
+	require.NoError(t, err)
+
+	client.err = errors.New("permission denied")
+	_, err = d.Retrieve(converter.StorageDriverRetrieveContext{Context: context.Background()}, claims)
+	assert.ErrorIs(t, err, client.err)
+	assert.ErrorContains(t, err, "download failed [bucket=b, name="+claims[0].ClaimData["name"]+", client_project=test-project]")
+	err = d.(converter.StorageDriverDeleter).Delete(converter.StorageDriverDeleteContext{Context: context.Background()}, claims)
+	assert.ErrorIs(t, err, client.err)
+	assert.ErrorContains(t, err, "delete failed [bucket=b, name=")
+}
@@ -0,0 +1,73 @@
+// Package gcpsdk provides a gcsdriver.Client implementation backed by the
+// Google Cloud Storage client library for Go. Import this package alongside
+// gcsdriver when using the official client library; supply a custom
+// gcsdriver.Client implementation otherwise.
+//
+// NOTE: Experimental
+package gcpsdk
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"cloud.google.com/go/storage"
+	"go.temporal.io/sdk/contrib/gcp/gcsdriver"
+)
+
+type gcsClient struct {
+	client *storage.Client
+}
+
+// NewClient creates a gcsdriver.Client backed by a Google Cloud Storage
+// client.
+//
+// NOTE: Experimental
+func NewClient(client *storage.Client) gcsdriver.Client {
+	return &gcsClient{client: client}
+}
+
+func (c *gcsClient) PutObject(ctx context.Context, bucket, name string, data []byte) error {
+	w := c.client.Bucket(bucket).Object(name).NewWriter(ctx)
+	// The payload is already in memory, so upload it in a single request
+	// rather than buffering it again in 16 MiB resumable-upload chunks.
+	w.ChunkSize = 0
+	if _, err := w.Write(data); err != nil {
+		_ = w.Close()
+		return err
+	}
+	return w.Close()
+}
+
+func (c *gcsClient) ObjectExists(ctx context.Context, bucket, name string) (bool, error) {
+	_, err := c.client.Bucket(bucket).Object(name).Attrs(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (c *gcsClient) GetObject(ctx context.Context, bucket, name string) ([]byte, error) {
+	r, err := c.client.Bucket(bucket).Object(name).NewReader(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	return io.ReadAll(r)
+}
+
+func (c *gcsClient) DeleteObject(ctx context.Context, bucket, name string) error {
+	err := c.client.Bucket(bucket).Object(name).Delete(ctx)
+	if errors.Is(err, storage.ErrObjectNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (c *gcsClient) Describe() map[string]string {
+	// The storage client does not expose its project or endpoint.
+	return nil
+}
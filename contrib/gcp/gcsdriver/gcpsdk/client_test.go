@@ -0,0 +1,145 @@
+package gcpsdk_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/fsouza/fake-gcs-server/fakestorage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/contrib/gcp/gcsdriver"
+	"go.temporal.io/sdk/contrib/gcp/gcsdriver/gcpsdk"
+	"go.temporal.io/sdk/converter"
+)
+
+// newFakeGCS starts an in-process fake GCS server and returns a
+// gcsdriver.Client backed by a real storage client pointing at it.
+func newFakeGCS(t *testing.T, buckets ...string) gcsdriver.Client {
+	t.Helper()
+	server := fakestorage.NewServer(nil)
+	for _, b := range buckets {
+		server.CreateBucketWithOpts(fakestorage.CreateBucketOpts{Name: b})
+	}
+	t.Cleanup(server.Stop)
+	return gcpsdk.NewClient(server.Client())
+}
+
+func TestGcpSdkClient_PutGetRoundTrip(t *testing.T) {
+	client := newFakeGCS(t, "test-bucket")
+	ctx := context.Background()
+
+	data := []byte("hello gcs")
+	err := client.PutObject(ctx, "test-bucket", "my/name", data)
+	require.NoError(t, err)
+
+	got, err := client.GetObject(ctx, "test-bucket", "my/name")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestGcpSdkClient_ObjectExists(t *testing.T) {
+	client := newFakeGCS(t, "test-bucket")
+	ctx := context.Background()
+
+	exists, err := client.ObjectExists(ctx, "test-bucket", "missing-name")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	err = client.PutObject(ctx, "test-bucket", "present-name", []byte("data"))
+	require.NoError(t, err)
+
+	exists, err = client.ObjectExists(ctx, "test-bucket", "present-name")
+	require.NoError(t, err)
+	assert.True(t, exists)
+}
+
+func TestGcpSdkClient_GetObject_NotFound(t *testing.T) {
+	client := newFakeGCS(t, "test-bucket")
+
+	_, err := client.GetObject(context.Background(), "test-bucket", "no-such-name")
+	assert.Error(t, err)
+}
+
+func TestGcpSdkClient_PutObject_BucketNotFound(t *testing.T) {
+	client := newFakeGCS(t)
+
+	err := client.PutObject(context.Background(), "no-such-bucket", "my/name", []byte("data"))
+	assert.Error(t, err)
+}
+
+func TestGcpSdkClient_DeleteObject(t *testing.T) {
+	client := newFakeGCS(t, "test-bucket")
+	ctx := context.Background()
+
+	err := client.PutObject(ctx, "test-bucket", "my/name", []byte("data"))
+	require.NoError(t, err)
+
+	err = client.DeleteObject(ctx, "test-bucket", "my/name")
+	require.NoError(t, err)
+
+	exists, err := client.ObjectExists(ctx, "test-bucket", "my/name")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	// Deleting a missing object is not an error.
+	err = client.DeleteObject(ctx, "test-bucket", "my/name")
+	assert.NoError(t, err)
+}
+
+func TestGcpSdkClient_LargeObject(t *testing.T) {
+	client := newFakeGCS(t, "test-bucket")
+	ctx := context.Background()
+
+	// 1 MiB of data.
+	data := make([]byte, 1024*1024)
+	for i := range data {
+		data[i] = byte(i % 256)
+	}
+
+	err := client.PutObject(ctx, "test-bucket", "large-obj", data)
+	require.NoError(t, err)
+
+	got, err := client.GetObject(ctx, "test-bucket", "large-obj")
+	require.NoError(t, err)
+	assert.Equal(t, data, got)
+}
+
+func TestGcpSdkClient_Describe(t *testing.T) {
+	assert.Empty(t, newFakeGCS(t).Describe())
+}
+
+// TestGcpSdkClient_FullDriverRoundTrip exercises the GCS storage driver
+// end-to-end through the fake GCS backend.
+func TestGcpSdkClient_FullDriverRoundTrip(t *testing.T) {
+	client := newFakeGCS(t, "driver-bucket")
+
+	d, err := gcsdriver.NewDriver(gcsdriver.Options{
+		Client: client,
+		Bucket: gcsdriver.StaticBucket("driver-bucket"),
+	})
+	require.NoError(t, err)
+
+	payloads := []*commonpb.Payload{
+		{Metadata: map[string][]byte{"encoding": []byte("binary/plain")}, Data: []byte("integration-test-1")},
+		{Metadata: map[string][]byte{"encoding": []byte("binary/plain")}, Data: []byte("integration-test-2")},
+	}
+
+	claims, err := d.Store(
+		converter.StorageDriverStoreContext{Context: context.Background()},
+		payloads,
+	)
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+
+	restored, err := d.Retrieve(
+		converter.StorageDriverRetrieveContext{Context: context.Background()},
+		claims,
+	)
+	require.NoError(t, err)
+	require.Len(t, restored, 2)
+
+	for i := range payloads {
+		assert.Equal(t, payloads[i].Data, restored[i].Data)
+	}
+}
@@ -0,0 +1,25 @@
+package k8sworker
+
+import (
+	"time"
+
+	"go.temporal.io/sdk/worker"
+)
+
+const (
+	defaultHealthAddress          = ":8080"
+	defaultTerminationGracePeriod = 30 * time.Second
+	defaultWorkerStopTimeout      = 10 * time.Second
+	defaultShutdownHookBuffer     = 2 * time.Second
+	defaultPollFailureThreshold   = 2 * time.Minute
+
+	envTaskQueue = "TEMPORAL_TASK_QUEUE"
+)
+
+// applyWorkerDefaults sets defaults on the given worker options. Zero-valued fields are set to
+// defaults; non-zero fields (previously set by envconfig or user) are left alone.
+func applyWorkerDefaults(opts *worker.Options) {
+	if opts.WorkerStopTimeout == 0 {
+		opts.WorkerStopTimeout = defaultWorkerStopTimeout
+	}
+}
@@ -0,0 +1,56 @@
+// Package k8sworker provides an ergonomic wrapper for running Temporal workers in long-running
+// containers, such as Kubernetes pods.
+//
+// # Usage
+//
+// Call [RunWorker] from your main() function:
+//
+//	func main() {
+//	    k8sworker.RunWorker(func(ctx *k8sworker.Options) error {
+//	        ctx.TaskQueue = "my-task-queue"
+//	        ctx.RegisterWorkflow(MyWorkflow)
+//	        ctx.RegisterActivity(MyActivity)
+//	        return nil
+//	    })
+//	}
+//
+// [RunWorker] dials the Temporal server, creates and starts a worker, serves liveness and
+// readiness endpoints for it, and drains and stops it when the pod is terminated.
+//
+// # Configuration
+//
+// Client connection options (address, namespace, TLS, API key) are loaded automatically from
+// a TOML config file and environment variables via
+// [go.temporal.io/sdk/contrib/envconfig.LoadClientOptions]. See more at
+// https://docs.temporal.io/references/client-environment-configuration.
+//
+// The configure callback receives an [Options] struct with public fields pre-populated with
+// defaults. Override any field directly in the callback.
+//
+// # Probes
+//
+// The liveness and readiness endpoints are served on [Options.HealthAddress] at [LivenessPath]
+// and [ReadinessPath]. They respond with status 200 when healthy and 503 otherwise, with the
+// reason in the body:
+//
+//	livenessProbe:
+//	  httpGet:
+//	    path: /livez
+//	    port: 8080
+//	readinessProbe:
+//	  httpGet:
+//	    path: /readyz
+//	    port: 8080
+//
+// Liveness fails after a fatal worker error, or when polls for tasks have been failing for longer
+// than [Options.PollFailureThreshold], so that the container is restarted. Readiness fails as soon
+// as a poll fails and while the worker is shutting down.
+//
+// # Termination
+//
+// Set [Options.TerminationGracePeriod] to the terminationGracePeriodSeconds of the pod. On
+// SIGTERM the worker stops polling and waits for running tasks to complete until
+// [Options.ShutdownDeadlineBuffer] before the end of the grace period, leaving time to stop the
+// worker and run the hooks registered with [Options.OnShutdown]. The grace period must be longer
+// than the longest activity that should complete on shutdown.
+package k8sworker
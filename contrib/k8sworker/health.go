@@ -0,0 +1,136 @@
+package k8sworker
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.temporal.io/sdk/worker"
+)
+
+const (
+	// LivenessPath is the path of the liveness endpoint.
+	LivenessPath = "/livez"
+	// ReadinessPath is the path of the readiness endpoint.
+	ReadinessPath = "/readyz"
+)
+
+// health reports the state of a worker on its liveness and readiness endpoints. The state of the
+// worker and of its pollers is read from [worker.Health] snapshots.
+type health struct {
+	// pollFailureThreshold is how long polls may fail continuously before the worker is no longer
+	// live.
+	pollFailureThreshold time.Duration
+	now                  func() time.Time
+
+	lock sync.Mutex
+	// snapshot returns the health of the worker, nil until the worker is created
+	snapshot func() (worker.HealthSnapshot, error)
+	draining bool
+	// pollFailingSince is when a poller was first seen failing, zero if none was on the last probe
+	pollFailingSince time.Time
+}
+
+func newHealth(pollFailureThreshold time.Duration, now func() time.Time) *health {
+	return &health{pollFailureThreshold: pollFailureThreshold, now: now}
+}
+
+// setWorker sets the worker whose health is reported.
+func (h *health) setWorker(w worker.Worker) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.snapshot = func() (worker.HealthSnapshot, error) { return worker.Health(w) }
+}
+
+func (h *health) setDraining() {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	h.draining = true
+}
+
+// check returns an error if the worker had a fatal error or its polls have been failing for
+// longer than the threshold, and otherwise its health snapshot and the last error of its failing
+// pollers, if any. Pollers are only seen failing when probed, so pollFailingSince is when the first
+// probe saw them failing.
+func (h *health) check() (snapshot worker.HealthSnapshot, pollErr error, err error) {
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	if h.snapshot == nil {
+		return worker.HealthSnapshot{}, nil, nil
+	}
+	if snapshot, err = h.snapshot(); err != nil {
+		return snapshot, nil, err
+	}
+	if snapshot.FatalError != nil {
+		return snapshot, nil, fmt.Errorf("worker fatal error: %w", snapshot.FatalError)
+	}
+	var lastErrTime time.Time
+	for _, p := range snapshot.Pollers {
+		if p.Failing && !p.LastPollErrorTime.Before(lastErrTime) {
+			pollErr = p.LastPollError
+			lastErrTime = p.LastPollErrorTime
+		}
+	}
+	if pollErr == nil {
+		h.pollFailingSince = time.Time{}
+		return snapshot, nil, nil
+	}
+	if h.pollFailingSince.IsZero() {
+		h.pollFailingSince = h.now()
+	}
+	if h.pollFailureThreshold > 0 {
+		if failing := h.now().Sub(h.pollFailingSince); failing > h.pollFailureThreshold {
+			return snapshot, pollErr, fmt.Errorf("polls failing for %v: %w", failing, pollErr)
+		}
+	}
+	return snapshot, pollErr, nil
+}
+
+// live returns an error if the worker had a fatal error or its polls have been failing for longer
+// than the threshold. A draining worker stays live so that it is not killed before it finishes.
+func (h *health) live() error {
+	_, _, err := h.check()
+	return err
+}
+
+// ready returns an error unless the worker is live, running, not draining and none of its pollers
+// is failing.
+func (h *health) ready() error {
+	snapshot, pollErr, err := h.check()
+	if err != nil {
+		return err
+	}
+	h.lock.Lock()
+	defer h.lock.Unlock()
+	switch {
+	case !snapshot.Running:
+		return errors.New("worker not started")
+	case h.draining:
+		return errors.New("worker draining")
+	case pollErr != nil:
+		return fmt.Errorf("poll failing: %w", pollErr)
+	}
+	return nil
+}
+
+// handler returns the HTTP handler serving the liveness and readiness endpoints.
+func (h *health) handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(LivenessPath, probeHandler(h.live))
+	mux.HandleFunc(ReadinessPath, probeHandler(h.ready))
+	return mux
+}
+
+func probeHandler(check func() error) http.HandlerFunc {
+	return func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+		if err := check(); err != nil {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			_, _ = fmt.Fprintln(w, err)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "ok")
+	}
+}
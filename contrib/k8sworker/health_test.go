@@ -0,0 +1,58 @@
+package k8sworker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"go.temporal.io/sdk/worker"
+)
+
+func probe(h *health, path string) int {
+	rec := httptest.NewRecorder()
+	h.handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec.Code
+}
+
+func TestHealth_Probes(t *testing.T) {
+	now := time.Unix(1000, 0)
+	h := newHealth(time.Minute, func() time.Time { return now })
+	assert.Equal(t, http.StatusOK, probe(h, LivenessPath))
+	assert.Equal(t, http.StatusServiceUnavailable, probe(h, ReadinessPath))
+
+	var snapshot worker.HealthSnapshot
+	h.snapshot = func() (worker.HealthSnapshot, error) { return snapshot, nil }
+	assert.Equal(t, http.StatusServiceUnavailable, probe(h, ReadinessPath))
+	snapshot = worker.HealthSnapshot{
+		Running: true,
+		Pollers: []worker.PollerHealth{{TaskType: worker.TaskTypeWorkflow}, {TaskType: worker.TaskTypeActivity}},
+	}
+	assert.Equal(t, http.StatusOK, probe(h, ReadinessPath))
+
+	// A failing poller makes the worker unready, and not live once it has failed for too long.
+	snapshot.Pollers[1].Failing = true
+	snapshot.Pollers[1].LastPollError = errors.New("unavailable")
+	assert.ErrorContains(t, h.ready(), "poll failing: unavailable")
+	assert.Equal(t, http.StatusOK, probe(h, LivenessPath))
+	now = now.Add(2 * time.Minute)
+	assert.ErrorContains(t, h.live(), "polls failing for 2m0s: unavailable")
+	snapshot.Pollers[1].Failing = false
+	assert.Equal(t, http.StatusOK, probe(h, LivenessPath))
+	assert.Equal(t, http.StatusOK, probe(h, ReadinessPath))
+
+	// Failing again restarts the threshold.
+	snapshot.Pollers[0].Failing = true
+	assert.Equal(t, http.StatusOK, probe(h, LivenessPath))
+	snapshot.Pollers[0].Failing = false
+
+	h.setDraining()
+	assert.Equal(t, http.StatusOK, probe(h, LivenessPath))
+	assert.Equal(t, http.StatusServiceUnavailable, probe(h, ReadinessPath))
+
+	snapshot.FatalError = errors.New("boom")
+	assert.EqualError(t, h.live(), "worker fatal error: boom")
+}
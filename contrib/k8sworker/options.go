@@ -0,0 +1,115 @@
+package k8sworker
+
+import (
+	"context"
+	"time"
+
+	"github.com/nexus-rpc/sdk-go/nexus"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+// Compile-time check that Options implements worker.Registry.
+var _ worker.Registry = (*Options)(nil)
+
+// Options is passed to the configure callback of [RunWorker]. It implements [worker.Registry] so
+// that workflows, activities, and Nexus services can be registered directly on it.
+//
+// Public fields are pre-populated with defaults before the configure callback is invoked; the
+// callback may override any of them.
+type Options struct {
+	// TaskQueue is the task queue name for the worker. Pre-populated from the
+	// TEMPORAL_TASK_QUEUE environment variable if set; otherwise must be set by the callback.
+	TaskQueue string
+
+	// ClientOptions are the Temporal client options used to dial the server. Pre-populated
+	// from the config file / environment variables via envconfig.
+	ClientOptions client.Options
+
+	// WorkerOptions are the Temporal worker options. WorkerStopTimeout is pre-populated to 10s.
+	// An OnFatalError callback set here is called before the worker reports the error.
+	WorkerOptions worker.Options
+
+	// HealthAddress is the address the liveness and readiness endpoints are served on, at
+	// [LivenessPath] and [ReadinessPath]. Pre-populated to ":8080".
+	HealthAddress string
+
+	// TerminationGracePeriod is the terminationGracePeriodSeconds of the pod. The worker must
+	// drain, stop and run its shutdown hooks within it after receiving SIGTERM. Pre-populated to
+	// the Kubernetes default of 30s.
+	TerminationGracePeriod time.Duration
+
+	// ShutdownDeadlineBuffer is how long before the end of the termination grace period the
+	// worker stops waiting for running tasks to drain and stops (worker stop + shutdown hooks).
+	// Draining does not wait for the polls in progress, which stopping the worker completes, so
+	// the rest of the grace period is only spent on running tasks. Pre-populated to
+	// WorkerOptions.WorkerStopTimeout + 2s. If you change WorkerStopTimeout, adjust this
+	// accordingly.
+	ShutdownDeadlineBuffer time.Duration
+
+	// PollFailureThreshold is how long pollers of the worker may fail continuously before the
+	// liveness endpoint fails. Readiness fails as soon as a poller is failing. Pre-populated to 2m.
+	PollFailureThreshold time.Duration
+
+	registrations []func(worker.Registry)
+	shutdownFuncs []func(context.Context) error
+}
+
+// RegisterWorkflow registers a workflow on the worker. See
+// [worker.WorkflowRegistry.RegisterWorkflow] for details.
+func (c *Options) RegisterWorkflow(w interface{}) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterWorkflow(w) })
+}
+
+// RegisterWorkflowWithOptions registers a workflow with options on the worker. See
+// [worker.WorkflowRegistry.RegisterWorkflowWithOptions] for details.
+func (c *Options) RegisterWorkflowWithOptions(w interface{}, options workflow.RegisterOptions) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterWorkflowWithOptions(w, options) })
+}
+
+// RegisterDynamicWorkflow registers a dynamic workflow on the worker. See
+// [worker.WorkflowRegistry.RegisterDynamicWorkflow] for details.
+func (c *Options) RegisterDynamicWorkflow(w interface{}, options workflow.DynamicRegisterOptions) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterDynamicWorkflow(w, options) })
+}
+
+// RegisterActivity registers an activity on the worker. See
+// [worker.ActivityRegistry.RegisterActivity] for details.
+func (c *Options) RegisterActivity(a interface{}) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterActivity(a) })
+}
+
+// RegisterActivityWithOptions registers an activity with options on the worker. See
+// [worker.ActivityRegistry.RegisterActivityWithOptions] for details.
+func (c *Options) RegisterActivityWithOptions(a interface{}, options activity.RegisterOptions) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterActivityWithOptions(a, options) })
+}
+
+// RegisterDynamicActivity registers a dynamic activity on the worker. See
+// [worker.ActivityRegistry.RegisterDynamicActivity] for details.
+func (c *Options) RegisterDynamicActivity(a interface{}, options activity.DynamicRegisterOptions) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterDynamicActivity(a, options) })
+}
+
+// RegisterNexusService registers a Nexus service on the worker. See
+// [worker.NexusServiceRegistry.RegisterNexusService] for details.
+func (c *Options) RegisterNexusService(s *nexus.Service) {
+	c.registrations = append(c.registrations, func(r worker.Registry) { r.RegisterNexusService(s) })
+}
+
+// OnShutdown registers a function to be called after the worker has stopped. Shutdown functions
+// run in registration order and receive a context that is done at the end of the termination
+// grace period. Use this to flush telemetry providers or release other resources.
+func (c *Options) OnShutdown(fn func(context.Context) error) {
+	c.shutdownFuncs = append(c.shutdownFuncs, fn)
+}
+
+// replayRegistrations replays all buffered registrations onto the given worker.
+func (c *Options) replayRegistrations(w worker.Worker) {
+	for _, fn := range c.registrations {
+		fn(w)
+	}
+}
@@ -0,0 +1,211 @@
+package k8sworker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/contrib/envconfig"
+	"go.temporal.io/sdk/worker"
+)
+
+// workerDeps captures external dependencies for testability.
+type workerDeps struct {
+	dial       func(client.Options) (client.Client, error)
+	newWorker  func(client.Client, string, worker.Options) worker.Worker
+	drain      func(context.Context, worker.Worker) (worker.DrainResult, error)
+	loadConfig func() (client.Options, error)
+	getenv     func(string) string
+	exit       func(int)
+	listen     func(address string) (net.Listener, error)
+	// notifyShutdown returns a channel that receives the signals to shut down on and a function
+	// to stop receiving them.
+	notifyShutdown func() (<-chan os.Signal, func())
+	now            func() time.Time
+}
+
+func defaultDeps() workerDeps {
+	return workerDeps{
+		dial: func(opts client.Options) (client.Client, error) { return client.Dial(opts) },
+		newWorker: func(c client.Client, tq string, opts worker.Options) worker.Worker {
+			return worker.New(c, tq, opts)
+		},
+		drain: worker.Drain,
+		loadConfig: func() (client.Options, error) {
+			return envconfig.LoadClientOptions(envconfig.LoadClientOptionsRequest{})
+		},
+		getenv: os.Getenv,
+		exit:   os.Exit,
+		listen: func(address string) (net.Listener, error) { return net.Listen("tcp", address) },
+		notifyShutdown: func() (<-chan os.Signal, func()) {
+			ch := make(chan os.Signal, 1)
+			signal.Notify(ch, syscall.SIGTERM, os.Interrupt)
+			return ch, func() { signal.Stop(ch) }
+		},
+		now: time.Now,
+	}
+}
+
+// RunWorker runs a Temporal worker in a long-running container such as a Kubernetes pod. It calls
+// the configure callback to collect registrations and option overrides, dials the Temporal
+// server, starts a worker and serves liveness and readiness endpoints for it until the process
+// receives SIGTERM or SIGINT, or the worker has a fatal error.
+//
+// The endpoints report the [worker.Health] of the worker. The liveness endpoint fails when the
+// worker had a fatal error or some of its pollers have been failing for longer than
+// [Options.PollFailureThreshold]. The readiness endpoint also fails until the worker is started,
+// while some of its pollers are failing, and while it is shutting down.
+//
+// On SIGTERM, the worker stops polling for new tasks and waits for running tasks to complete, see
+// [worker.Drain], until [Options.ShutdownDeadlineBuffer] before the end of
+// [Options.TerminationGracePeriod]. Drain does not wait for the polls in progress, so a worker
+// without running tasks drains immediately. It is then stopped, which completes the polls, and the
+// shutdown hooks registered with [Options.OnShutdown] are run before RunWorker returns.
+//
+// You must configure a task queue for the worker to listen on, either via [Options.TaskQueue] or
+// the TEMPORAL_TASK_QUEUE environment variable.
+//
+// On fatal configuration or worker error, it logs to stderr and calls os.Exit(1), so that the
+// container is restarted.
+//
+// NOTE: Experimental
+func RunWorker(configure func(ctx *Options) error) {
+	deps := defaultDeps()
+	if err := runWorkerInternal(configure, deps); err != nil {
+		fmt.Fprintf(os.Stderr, "k8sworker: fatal: %v\n", err)
+		deps.exit(1)
+	}
+}
+
+// runWorkerInternal contains the core logic with injected dependencies for testability. It returns
+// an error instead of calling os.Exit.
+func runWorkerInternal(configure func(ctx *Options) error, deps workerDeps) error {
+	clientOpts, err := deps.loadConfig()
+	if err != nil {
+		return fmt.Errorf("loading client config: %w", err)
+	}
+
+	var workerOpts worker.Options
+	applyWorkerDefaults(&workerOpts)
+
+	configCtx := &Options{
+		ClientOptions:          clientOpts,
+		WorkerOptions:          workerOpts,
+		HealthAddress:          defaultHealthAddress,
+		TerminationGracePeriod: defaultTerminationGracePeriod,
+		ShutdownDeadlineBuffer: workerOpts.WorkerStopTimeout + defaultShutdownHookBuffer,
+		PollFailureThreshold:   defaultPollFailureThreshold,
+	}
+	if tq := deps.getenv(envTaskQueue); tq != "" {
+		configCtx.TaskQueue = tq
+	}
+
+	if err := configure(configCtx); err != nil {
+		return fmt.Errorf("configure callback failed: %w", err)
+	}
+
+	if configCtx.TaskQueue == "" {
+		return fmt.Errorf(
+			"task queue not configured: set Options.TaskQueue or the %s environment variable",
+			envTaskQueue,
+		)
+	}
+	if configCtx.TerminationGracePeriod <= configCtx.ShutdownDeadlineBuffer {
+		return fmt.Errorf(
+			"termination grace period %v leaves no time to drain after the shutdown deadline buffer %v",
+			configCtx.TerminationGracePeriod, configCtx.ShutdownDeadlineBuffer,
+		)
+	}
+
+	logger := configCtx.ClientOptions.Logger
+	h := newHealth(configCtx.PollFailureThreshold, deps.now)
+
+	fatalErrCh := make(chan error, 1)
+	workerOpts = configCtx.WorkerOptions
+	onFatalError := workerOpts.OnFatalError
+	workerOpts.OnFatalError = func(err error) {
+		if onFatalError != nil {
+			onFatalError(err)
+		}
+		select {
+		case fatalErrCh <- err:
+		default:
+		}
+	}
+
+	listener, err := deps.listen(configCtx.HealthAddress)
+	if err != nil {
+		return fmt.Errorf("listening on health address: %w", err)
+	}
+	server := &http.Server{Handler: h.handler(), ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			fmt.Fprintf(os.Stderr, "k8sworker: health server error: %v\n", err)
+		}
+	}()
+	defer server.Close()
+
+	// Listen for signals before starting so that a pod terminated while starting shuts down.
+	shutdownCh, stopNotify := deps.notifyShutdown()
+	defer stopNotify()
+
+	c, err := deps.dial(configCtx.ClientOptions)
+	if err != nil {
+		return fmt.Errorf("dialing Temporal server: %w", err)
+	}
+	defer c.Close()
+
+	w := deps.newWorker(c, configCtx.TaskQueue, workerOpts)
+	if _, err := worker.Health(w); err != nil {
+		return err
+	}
+	h.setWorker(w)
+	configCtx.replayRegistrations(w)
+
+	if err := w.Start(); err != nil {
+		return fmt.Errorf("starting worker: %w", err)
+	}
+
+	var runErr error
+	var shutdownCtx context.Context
+	var cancelShutdown context.CancelFunc
+	select {
+	case <-shutdownCh:
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.Background(), configCtx.TerminationGracePeriod)
+		h.setDraining()
+		// Drain only waits for running tasks, not for polls, so an idle worker drains at once. The
+		// polls are completed by Stop, within the shutdown deadline buffer.
+		drainCtx, cancelDrain := context.WithTimeout(shutdownCtx,
+			configCtx.TerminationGracePeriod-configCtx.ShutdownDeadlineBuffer)
+		result, err := deps.drain(drainCtx, w)
+		cancelDrain()
+		if err != nil && logger != nil {
+			logger.Warn("Worker did not drain before the shutdown deadline",
+				"Error", err,
+				"RunningTasks", len(result.RunningTasks),
+			)
+		}
+	case err := <-fatalErrCh:
+		shutdownCtx, cancelShutdown = context.WithTimeout(context.Background(), configCtx.ShutdownDeadlineBuffer)
+		runErr = fmt.Errorf("worker fatal error: %w", err)
+	}
+	defer cancelShutdown()
+
+	// Stop the worker before running shutdown hooks so that hooks (e.g. telemetry flushes) see
+	// everything emitted while it stopped.
+	w.Stop()
+
+	for _, fn := range configCtx.shutdownFuncs {
+		if err := fn(shutdownCtx); err != nil {
+			fmt.Fprintf(os.Stderr, "k8sworker: shutdown hook error: %v\n", err)
+		}
+	}
+	return runErr
+}
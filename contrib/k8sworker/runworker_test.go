@@ -0,0 +1,247 @@
+package k8sworker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"sync/atomic"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/nexus-rpc/sdk-go/nexus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/activity"
+	"go.temporal.io/sdk/client"
+	"go.temporal.io/sdk/worker"
+	"go.temporal.io/sdk/workflow"
+)
+
+type mockWorker struct {
+	mock.Mock
+	started atomic.Bool
+}
+
+var _ worker.Worker = (*mockWorker)(nil)
+
+func (m *mockWorker) RegisterWorkflow(w interface{}) {
+	m.Called(w)
+}
+
+func (m *mockWorker) RegisterWorkflowWithOptions(w interface{}, options workflow.RegisterOptions) {
+	m.Called(w, options)
+}
+
+func (m *mockWorker) RegisterDynamicWorkflow(w interface{}, options workflow.DynamicRegisterOptions) {
+	m.Called(w, options)
+}
+
+func (m *mockWorker) RegisterActivity(a interface{}) {
+	m.Called(a)
+}
+
+func (m *mockWorker) RegisterActivityWithOptions(a interface{}, options activity.RegisterOptions) {
+	m.Called(a, options)
+}
+
+func (m *mockWorker) RegisterDynamicActivity(a interface{}, options activity.DynamicRegisterOptions) {
+	m.Called(a, options)
+}
+
+func (m *mockWorker) RegisterNexusService(s *nexus.Service) {
+	m.Called(s)
+}
+
+func (m *mockWorker) Start() error {
+	args := m.Called()
+	m.started.Store(args.Error(0) == nil)
+	return args.Error(0)
+}
+
+func (m *mockWorker) Run(interruptCh <-chan interface{}) error {
+	args := m.Called(interruptCh)
+	if interruptCh != nil {
+		<-interruptCh
+	}
+	return args.Error(0)
+}
+
+func (m *mockWorker) Stop() {
+	m.Called()
+}
+
+func (m *mockWorker) Health() worker.HealthSnapshot {
+	return worker.HealthSnapshot{Running: m.started.Load()}
+}
+
+// mockClient implements client.Client for testing.
+type mockClient struct {
+	mock.Mock
+	client.Client
+}
+
+func (m *mockClient) Close() {
+	m.Called()
+}
+
+func myWorkflow() {}
+func myActivity() {}
+
+type testRun struct {
+	deps       workerDeps
+	worker     *mockWorker
+	client     *mockClient
+	shutdownCh chan os.Signal
+	// workerOptions are the options the worker was created with
+	workerOptions chan worker.Options
+	addr          chan string
+}
+
+func newTestRun() *testRun {
+	r := &testRun{
+		worker:        &mockWorker{},
+		client:        &mockClient{},
+		shutdownCh:    make(chan os.Signal, 1),
+		workerOptions: make(chan worker.Options, 1),
+		addr:          make(chan string, 1),
+	}
+	r.deps = workerDeps{
+		dial: func(client.Options) (client.Client, error) { return r.client, nil },
+		newWorker: func(_ client.Client, _ string, opts worker.Options) worker.Worker {
+			r.workerOptions <- opts
+			return r.worker
+		},
+		drain: func(context.Context, worker.Worker) (worker.DrainResult, error) {
+			return worker.DrainResult{}, nil
+		},
+		loadConfig: func() (client.Options, error) { return client.Options{}, nil },
+		getenv: func(k string) string {
+			if k == envTaskQueue {
+				return "test-queue"
+			}
+			return ""
+		},
+		exit: func(int) {},
+		listen: func(string) (net.Listener, error) {
+			l, err := net.Listen("tcp", "127.0.0.1:0")
+			if err == nil {
+				r.addr <- l.Addr().String()
+			}
+			return l, err
+		},
+		notifyShutdown: func() (<-chan os.Signal, func()) { return r.shutdownCh, func() {} },
+		now:            time.Now,
+	}
+	return r
+}
+
+func getStatus(t *testing.T, addr, path string) int {
+	resp, err := http.Get(fmt.Sprintf("http://%s%s", addr, path))
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	return resp.StatusCode
+}
+
+func TestRunWorkerInternal_DrainsOnShutdownSignal(t *testing.T) {
+	r := newTestRun()
+	var drainDeadline time.Time
+	r.deps.drain = func(ctx context.Context, w worker.Worker) (worker.DrainResult, error) {
+		assert.Same(t, r.worker, w)
+		drainDeadline, _ = ctx.Deadline()
+		return worker.DrainResult{}, nil
+	}
+	var stopped, hookRan bool
+	r.worker.On("RegisterWorkflow", mock.Anything).Once()
+	r.worker.On("RegisterActivity", mock.Anything).Once()
+	r.worker.On("Start").Return(nil).Once()
+	r.worker.On("Stop").Run(func(mock.Arguments) { stopped = true }).Once()
+	r.client.On("Close").Once()
+
+	errCh := make(chan error, 1)
+	start := time.Now()
+	go func() {
+		errCh <- runWorkerInternal(func(ctx *Options) error {
+			ctx.TerminationGracePeriod = time.Minute
+			ctx.RegisterWorkflow(myWorkflow)
+			ctx.RegisterActivity(myActivity)
+			ctx.OnShutdown(func(ctx context.Context) error {
+				assert.True(t, stopped, "hook ran before the worker stopped")
+				_, hasDeadline := ctx.Deadline()
+				assert.True(t, hasDeadline)
+				hookRan = true
+				return nil
+			})
+			return nil
+		}, r.deps)
+	}()
+
+	addr := <-r.addr
+	require.Eventually(t, func() bool {
+		return getStatus(t, addr, ReadinessPath) == http.StatusOK
+	}, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, getStatus(t, addr, LivenessPath))
+
+	r.shutdownCh <- syscall.SIGTERM
+	require.NoError(t, <-errCh)
+	assert.True(t, hookRan)
+	// Drain ends the shutdown deadline buffer before the grace period, by default 12s.
+	assert.WithinDuration(t, start.Add(time.Minute-12*time.Second), drainDeadline, 5*time.Second)
+	r.worker.AssertExpectations(t)
+	r.client.AssertExpectations(t)
+}
+
+func TestRunWorkerInternal_FatalError(t *testing.T) {
+	r := newTestRun()
+	var reported error
+	r.worker.On("Start").Return(nil).Run(func(mock.Arguments) {
+		opts := <-r.workerOptions
+		go opts.OnFatalError(errors.New("boom"))
+	}).Once()
+	r.worker.On("Stop").Once()
+	r.client.On("Close").Once()
+
+	err := runWorkerInternal(func(ctx *Options) error {
+		ctx.WorkerOptions.OnFatalError = func(err error) { reported = err }
+		return nil
+	}, r.deps)
+	require.ErrorContains(t, err, "worker fatal error: boom")
+	require.EqualError(t, reported, "boom")
+	r.worker.AssertExpectations(t)
+	r.client.AssertExpectations(t)
+}
+
+func TestRunWorkerInternal_WorkerWithoutHealth(t *testing.T) {
+	r := newTestRun()
+	r.deps.newWorker = func(client.Client, string, worker.Options) worker.Worker {
+		return struct{ worker.Worker }{r.worker}
+	}
+	r.client.On("Close").Once()
+	err := runWorkerInternal(func(ctx *Options) error { return nil }, r.deps)
+	require.ErrorContains(t, err, "does not report its health")
+	r.worker.AssertExpectations(t)
+	r.client.AssertExpectations(t)
+}
+
+func TestRunWorkerInternal_ConfigErrors(t *testing.T) {
+	r := newTestRun()
+	r.deps.getenv = func(string) string { return "" }
+	err := runWorkerInternal(func(ctx *Options) error { return nil }, r.deps)
+	assert.ErrorContains(t, err, "task queue not configured")
+
+	r = newTestRun()
+	err = runWorkerInternal(func(ctx *Options) error {
+		ctx.TerminationGracePeriod = 5 * time.Second
+		return nil
+	}, r.deps)
+	assert.ErrorContains(t, err, "leaves no time to drain")
+
+	r = newTestRun()
+	err = runWorkerInternal(func(ctx *Options) error { return errors.New("bad config") }, r.deps)
+	assert.ErrorContains(t, err, "configure callback failed")
+}
@@ -0,0 +1,81 @@
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pressureSource locates the pressure stall information (PSI) files of a Linux system: the
+// cgroup v2 files of the current cgroup, or the system-wide files if the cgroup has none.
+type pressureSource struct {
+	dir string
+	// file maps a resource to its file name in dir
+	file func(resource string) string
+}
+
+var pressureSources = []pressureSource{
+	{dir: "/sys/fs/cgroup", file: func(resource string) string { return resource + ".pressure" }},
+	{dir: "/proc/pressure", file: func(resource string) string { return resource }},
+}
+
+type pressureInfo struct {
+	cpu    float64
+	memory float64
+	io     float64
+}
+
+// readPressure reads the "some" pressure of CPU, memory and IO averaged over the last 10 seconds,
+// as fractions between 0 and 1, from the first source that has them. Returns an error wrapping
+// fs.ErrNotExist if no source is available.
+func readPressure(sources []pressureSource) (pressureInfo, error) {
+	var lastErr error
+	for _, source := range sources {
+		var info pressureInfo
+		var err error
+		for resource, value := range map[string]*float64{"cpu": &info.cpu, "memory": &info.memory, "io": &info.io} {
+			var data []byte
+			if data, err = os.ReadFile(filepath.Join(source.dir, source.file(resource))); err != nil {
+				break
+			}
+			if *value, err = parsePressure(data); err != nil {
+				return pressureInfo{}, fmt.Errorf("failed to parse %s pressure: %w", resource, err)
+			}
+		}
+		if err == nil {
+			return info, nil
+		}
+		lastErr = err
+	}
+	return pressureInfo{}, fmt.Errorf("failed to read pressure stall information: %w", lastErr)
+}
+
+// parsePressure returns the "some avg10" value of a PSI file as a fraction between 0 and 1. The
+// file contents look like:
+//
+//	some avg10=1.53 avg60=0.87 avg300=0.22 total=1234567
+//	full avg10=0.00 avg60=0.00 avg300=0.00 total=0
+func parsePressure(data []byte) (float64, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] != "some" {
+			continue
+		}
+		for _, field := range fields[1:] {
+			if value, ok := strings.CutPrefix(field, "avg10="); ok {
+				percent, err := strconv.ParseFloat(value, 64)
+				if err != nil {
+					return 0, err
+				}
+				return percent / 100, nil
+			}
+		}
+	}
+	return 0, errors.New("missing some avg10 value")
+}
@@ -0,0 +1,79 @@
+package sysinfo
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/internal/log"
+	"go.temporal.io/sdk/worker"
+)
+
+var _ worker.PressureInfoProvider = (*psUtilSystemInfoSupplier)(nil)
+
+func TestParsePressure(t *testing.T) {
+	pressure, err := parsePressure([]byte("some avg10=12.50 avg60=3.00 avg300=1.00 total=1234\n" +
+		"full avg10=4.00 avg60=1.00 avg300=0.50 total=567\n"))
+	require.NoError(t, err)
+	assert.InDelta(t, 0.125, pressure, 1e-9)
+
+	_, err = parsePressure([]byte("full avg10=4.00 avg60=1.00 avg300=0.50 total=567\n"))
+	assert.EqualError(t, err, "missing some avg10 value")
+}
+
+func TestReadPressure(t *testing.T) {
+	cgroupDir, procDir := t.TempDir(), t.TempDir()
+	sources := []pressureSource{
+		{dir: cgroupDir, file: func(resource string) string { return resource + ".pressure" }},
+		{dir: procDir, file: func(resource string) string { return resource }},
+	}
+	write := func(dir, name, avg10 string) {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name),
+			[]byte("some avg10="+avg10+" avg60=0.00 avg300=0.00 total=0\n"), 0o644))
+	}
+
+	_, err := readPressure(sources)
+	require.ErrorIs(t, err, fs.ErrNotExist)
+
+	// Falls back to the system-wide files when the cgroup has none.
+	write(procDir, "cpu", "10.00")
+	write(procDir, "memory", "20.00")
+	write(procDir, "io", "30.00")
+	info, err := readPressure(sources)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.1, 0.2, 0.3}, []float64{info.cpu, info.memory, info.io}, 1e-9)
+
+	write(cgroupDir, "cpu.pressure", "1.00")
+	write(cgroupDir, "memory.pressure", "2.00")
+	write(cgroupDir, "io.pressure", "3.00")
+	info, err = readPressure(sources)
+	require.NoError(t, err)
+	assert.InDeltaSlice(t, []float64{0.01, 0.02, 0.03}, []float64{info.cpu, info.memory, info.io}, 1e-9)
+}
+
+func TestPressureUnavailable(t *testing.T) {
+	dir := t.TempDir()
+	defer func(sources []pressureSource) { pressureSources = sources }(pressureSources)
+	pressureSources = []pressureSource{{dir: dir, file: func(resource string) string { return resource }}}
+	ctx := &worker.SysInfoContext{Logger: log.NewNopLogger()}
+
+	// Reported as unsupported rather than as no pressure, so that thresholds can be rejected.
+	_, err := newPsUtilSystemInfoSupplier().CpuPressure(ctx)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+
+	if runtime.GOOS != "linux" {
+		return
+	}
+	for _, resource := range []string{"cpu", "memory", "io"} {
+		require.NoError(t, os.WriteFile(filepath.Join(dir, resource),
+			[]byte("some avg10=40.00 avg60=0.00 avg300=0.00 total=0\n"), 0o644))
+	}
+	pressure, err := newPsUtilSystemInfoSupplier().IoPressure(ctx)
+	require.NoError(t, err)
+	assert.InDelta(t, 0.4, pressure, 1e-9)
+}
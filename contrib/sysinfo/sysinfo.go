@@ -2,6 +2,8 @@ package sysinfo
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"runtime"
 	"sync"
 	"sync/atomic"
@@ -12,14 +14,17 @@ import (
 	"go.temporal.io/sdk/worker"
 )
 
-var sysInfoProvider = sync.OnceValue(func() *psUtilSystemInfoSupplier {
-	return &psUtilSystemInfoSupplier{
-		cGroupInfo: newCGroupInfo(),
-	}
-})
+var sysInfoProvider = sync.OnceValue(newPsUtilSystemInfoSupplier)
+
+var (
+	errPressureNotRead     = errors.New("pressure stall information not read yet")
+	errPressureUnsupported = fmt.Errorf("pressure stall information is not available: %w", errors.ErrUnsupported)
+)
 
 // SysInfoProvider returns a shared SysInfoProvider using gopsutil.
-// Supports cgroup metrics in containerized Linux environments.
+// Supports cgroup metrics in containerized Linux environments. On Linux it also implements
+// worker.PressureInfoProvider using pressure stall information (PSI), so it can be used with the
+// pressure thresholds of worker.ResourceBasedTunerOptions.
 func SysInfoProvider() worker.SysInfoProvider {
 	return sysInfoProvider()
 }
@@ -33,6 +38,11 @@ type psUtilSystemInfoSupplier struct {
 
 	stopTryingToGetCGroupInfo bool
 	cGroupInfo                cGroupInfo
+
+	lastPressure            pressureInfo
+	stopTryingToGetPressure bool
+	// pressureErr is why lastPressure is not usable, nil once it has been read
+	pressureErr error
 }
 
 type cGroupInfo interface {
@@ -48,6 +58,13 @@ type cGroupInfo interface {
 	GetLastCPUUsage() float64
 }
 
+func newPsUtilSystemInfoSupplier() *psUtilSystemInfoSupplier {
+	return &psUtilSystemInfoSupplier{
+		cGroupInfo:  newCGroupInfo(),
+		pressureErr: errPressureNotRead,
+	}
+}
+
 func (p *psUtilSystemInfoSupplier) MemoryUsage(infoContext *worker.SysInfoContext) (float64, error) {
 	if err := p.maybeRefresh(infoContext); err != nil {
 		return 0, err
@@ -74,6 +91,42 @@ func (p *psUtilSystemInfoSupplier) CpuUsage(infoContext *worker.SysInfoContext)
 	return p.lastCpuUsage / 100, nil
 }
 
+// CpuPressure returns the CPU pressure of the cgroup, or of the system if not in a cgroup, from
+// Linux pressure stall information. Returns an error wrapping errors.ErrUnsupported if not
+// available.
+func (p *psUtilSystemInfoSupplier) CpuPressure(infoContext *worker.SysInfoContext) (float64, error) {
+	return p.pressure(infoContext, func(info pressureInfo) float64 { return info.cpu })
+}
+
+// MemoryPressure returns the memory pressure of the cgroup, or of the system if not in a cgroup,
+// from Linux pressure stall information. Returns an error wrapping errors.ErrUnsupported if not
+// available.
+func (p *psUtilSystemInfoSupplier) MemoryPressure(infoContext *worker.SysInfoContext) (float64, error) {
+	return p.pressure(infoContext, func(info pressureInfo) float64 { return info.memory })
+}
+
+// IoPressure returns the IO pressure of the cgroup, or of the system if not in a cgroup, from
+// Linux pressure stall information. Returns an error wrapping errors.ErrUnsupported if not
+// available.
+func (p *psUtilSystemInfoSupplier) IoPressure(infoContext *worker.SysInfoContext) (float64, error) {
+	return p.pressure(infoContext, func(info pressureInfo) float64 { return info.io })
+}
+
+func (p *psUtilSystemInfoSupplier) pressure(
+	infoContext *worker.SysInfoContext,
+	get func(pressureInfo) float64,
+) (float64, error) {
+	if err := p.maybeRefresh(infoContext); err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.pressureErr != nil {
+		return 0, p.pressureErr
+	}
+	return get(p.lastPressure), nil
+}
+
 func (p *psUtilSystemInfoSupplier) maybeRefresh(infoContext *worker.SysInfoContext) error {
 	if time.Since(time.Unix(0, p.lastRefresh.Load())) < 100*time.Millisecond {
 		return nil
@@ -106,6 +159,24 @@ func (p *psUtilSystemInfoSupplier) maybeRefresh(infoContext *worker.SysInfoConte
 		p.stopTryingToGetCGroupInfo = !continueUpdates
 	}
 
+	if runtime.GOOS != "linux" {
+		p.pressureErr = errPressureUnsupported
+	} else if !p.stopTryingToGetPressure {
+		pressure, err := readPressure(pressureSources)
+		// Stop updates if the kernel does not support PSI, like for cgroups.
+		continueUpdates, err := handleCGroupUpdateError(err)
+		if err != nil {
+			infoContext.Logger.Warn("Failed to get pressure stall information", "error", err)
+			p.pressureErr = err
+		} else if continueUpdates {
+			p.lastPressure = pressure
+			p.pressureErr = nil
+		} else {
+			p.pressureErr = errPressureUnsupported
+		}
+		p.stopTryingToGetPressure = !continueUpdates
+	}
+
 	p.lastRefresh.Store(time.Now().UnixNano())
 	return nil
 }
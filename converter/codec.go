@@ -69,7 +69,7 @@ func (z *zlibCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, e
 		// Only set if smaller than original amount or has option to always encode
 		if buf.Len() < len(b) || z.options.AlwaysEncode {
 			result[i] = &commonpb.Payload{
-				Metadata: map[string][]byte{MetadataEncoding: []byte("binary/zlib")},
+				Metadata: map[string][]byte{MetadataEncoding: []byte(metadataEncodingZlib)},
 				Data:     buf.Bytes(),
 			}
 		} else {
@@ -83,7 +83,7 @@ func (*zlibCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, err
 	result := make([]*commonpb.Payload, len(payloads))
 	for i, p := range payloads {
 		// Only if it's our encoding
-		if string(p.Metadata[MetadataEncoding]) != "binary/zlib" {
+		if string(p.Metadata[MetadataEncoding]) != metadataEncodingZlib {
 			result[i] = p
 			continue
 		}
@@ -0,0 +1,150 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+
+	commonpb "go.temporal.io/api/common/v1"
+)
+
+// ClaimKeyCodecID is the StorageDriverClaim.ClaimData key under which the
+// driver returned by NewCodecStorageDriver records its CodecID.
+//
+// NOTE: Experimental
+const ClaimKeyCodecID = "codec_id"
+
+// CodecStorageDriverOptions are options for NewCodecStorageDriver.
+//
+// NOTE: Experimental
+type CodecStorageDriverOptions struct {
+	// Driver is the storage driver that stores the encoded payloads. Required.
+	Driver StorageDriver
+
+	// Codecs are applied to payloads before they are stored and reversed after
+	// they are retrieved, in the same order as NewCodecDataConverter: the last
+	// codec encodes first and decodes last. Required.
+	Codecs []PayloadCodec
+
+	// CodecID identifies the codec configuration, e.g. "zstd+aes-gcm/v1". It is
+	// recorded in every claim so that retrieving a payload that was stored with
+	// a different configuration fails with an error instead of returning data
+	// the application cannot read. Change it whenever a change to Codecs makes
+	// previously stored payloads unreadable. Required.
+	CodecID string
+}
+
+type codecStorageDriver struct {
+	driver  StorageDriver
+	codecs  []PayloadCodec
+	codecID string
+}
+
+// codecStorageDeleterDriver is returned in place of codecStorageDriver when
+// the wrapped driver supports deletion, so that the wrapper supports it too.
+type codecStorageDeleterDriver struct {
+	*codecStorageDriver
+	deleter StorageDriverDeleter
+}
+
+// NewCodecStorageDriver creates a StorageDriver that encodes payloads with a
+// PayloadCodec chain before passing them to Options.Driver and decodes them
+// after they are retrieved. Use it to compress or encrypt payloads at rest in
+// the external store independently of the codecs configured on the data
+// converter, which apply to every payload including those kept inline.
+//
+// The wrapper reports the name and type of the wrapped driver. Claims issued
+// by the wrapped driver before it was wrapped carry no codec ID; payloads for
+// such claims are returned as retrieved. Codecs are always called without a
+// serialization context because none is available when payloads are
+// retrieved. The returned driver implements StorageDriverDeleter if and only
+// if the wrapped driver does.
+//
+// NOTE: Experimental
+func NewCodecStorageDriver(options CodecStorageDriverOptions) (StorageDriver, error) {
+	if options.Driver == nil {
+		return nil, errors.New("Driver is required")
+	}
+	if len(options.Codecs) == 0 {
+		return nil, errors.New("at least one codec is required")
+	}
+	if options.CodecID == "" {
+		return nil, errors.New("CodecID is required")
+	}
+	d := &codecStorageDriver{
+		driver:  options.Driver,
+		codecs:  options.Codecs,
+		codecID: options.CodecID,
+	}
+	if deleter, ok := options.Driver.(StorageDriverDeleter); ok {
+		return &codecStorageDeleterDriver{codecStorageDriver: d, deleter: deleter}, nil
+	}
+	return d, nil
+}
+
+func (d *codecStorageDriver) Name() string { return d.driver.Name() }
+
+func (d *codecStorageDriver) Type() string { return d.driver.Type() }
+
+func (d *codecStorageDriver) Store(ctx StorageDriverStoreContext, payloads []*commonpb.Payload) ([]StorageDriverClaim, error) {
+	encoded, err := encodePayloads(payloads, d.codecs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode payloads: %w", err)
+	}
+	claims, err := d.driver.Store(ctx, encoded)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]StorageDriverClaim, len(claims))
+	for i, c := range claims {
+		if _, exists := c.ClaimData[ClaimKeyCodecID]; exists {
+			return nil, fmt.Errorf("driver %q claim already contains field %q", d.driver.Name(), ClaimKeyCodecID)
+		}
+		data := make(map[string]string, len(c.ClaimData)+1)
+		for k, v := range c.ClaimData {
+			data[k] = v
+		}
+		data[ClaimKeyCodecID] = d.codecID
+		result[i] = StorageDriverClaim{ClaimData: data}
+	}
+	return result, nil
+}
+
+func (d *codecStorageDriver) Retrieve(ctx StorageDriverRetrieveContext, claims []StorageDriverClaim) ([]*commonpb.Payload, error) {
+	var encodedIndexes []int
+	for i, c := range claims {
+		codecID, ok := c.ClaimData[ClaimKeyCodecID]
+		if !ok {
+			continue
+		}
+		if codecID != d.codecID {
+			return nil, fmt.Errorf("codec mismatch: payload was stored with codec %q, but this driver is configured with %q", codecID, d.codecID)
+		}
+		encodedIndexes = append(encodedIndexes, i)
+	}
+	payloads, err := d.driver.Retrieve(ctx, claims)
+	if err != nil {
+		return nil, err
+	}
+	if len(payloads) != len(claims) {
+		return nil, fmt.Errorf("driver %q returned %d payloads for %d claims", d.driver.Name(), len(payloads), len(claims))
+	}
+	if len(encodedIndexes) == 0 {
+		return payloads, nil
+	}
+	encoded := make([]*commonpb.Payload, len(encodedIndexes))
+	for j, i := range encodedIndexes {
+		encoded[j] = payloads[i]
+	}
+	decoded, err := decodePayloads(encoded, d.codecs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode payloads: %w", err)
+	}
+	for j, i := range encodedIndexes {
+		payloads[i] = decoded[j]
+	}
+	return payloads, nil
+}
+
+func (d *codecStorageDeleterDriver) Delete(ctx StorageDriverDeleteContext, claims []StorageDriverClaim) error {
+	return d.deleter.Delete(ctx, claims)
+}
@@ -0,0 +1,171 @@
+package converter_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"google.golang.org/protobuf/proto"
+)
+
+func storeCtx() converter.StorageDriverStoreContext {
+	return converter.StorageDriverStoreContext{Context: context.Background()}
+}
+
+func retrieveCtx() converter.StorageDriverRetrieveContext {
+	return converter.StorageDriverRetrieveContext{Context: context.Background()}
+}
+
+func TestCodecStorageDriver_RoundTrip(t *testing.T) {
+	inner := newMemDriver("mem")
+	driver, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  inner,
+		Codecs:  []converter.PayloadCodec{&appendCodec{"+outer", 'o'}, &appendCodec{"+inner", 'i'}},
+		CodecID: "test/v1",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "mem", driver.Name())
+	require.Equal(t, "mem", driver.Type())
+
+	original := makePayload(t, "value")
+	claims, err := driver.Store(storeCtx(), []*commonpb.Payload{original})
+	require.NoError(t, err)
+	require.Equal(t, "test/v1", claims[0].ClaimData[converter.ClaimKeyCodecID])
+
+	// The stored payload is encoded, last codec first.
+	stored, err := inner.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Equal(t, "json/plain+inner+outer", encoding(stored[0]))
+
+	retrieved, err := driver.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(original, retrieved[0]))
+}
+
+func TestCodecStorageDriver_Mismatch(t *testing.T) {
+	inner := newMemDriver("mem")
+	v1, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  inner,
+		Codecs:  []converter.PayloadCodec{&appendCodec{"+a", 'a'}},
+		CodecID: "v1",
+	})
+	require.NoError(t, err)
+	v2, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  inner,
+		Codecs:  []converter.PayloadCodec{&appendCodec{"+b", 'b'}},
+		CodecID: "v2",
+	})
+	require.NoError(t, err)
+
+	claims, err := v1.Store(storeCtx(), []*commonpb.Payload{makePayload(t, "value")})
+	require.NoError(t, err)
+	_, err = v2.Retrieve(retrieveCtx(), claims)
+	require.EqualError(t, err, `codec mismatch: payload was stored with codec "v1", but this driver is configured with "v2"`)
+}
+
+func TestCodecStorageDriver_ClaimsWithoutCodecID(t *testing.T) {
+	inner := newMemDriver("mem")
+	driver, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  inner,
+		Codecs:  []converter.PayloadCodec{&appendCodec{"+a", 'a'}},
+		CodecID: "v1",
+	})
+	require.NoError(t, err)
+
+	// A payload stored before the driver was wrapped is returned as stored,
+	// alongside one stored through the wrapper.
+	legacy := makePayload(t, "legacy")
+	legacyClaims, err := inner.Store(storeCtx(), []*commonpb.Payload{legacy})
+	require.NoError(t, err)
+	current := makePayload(t, "current")
+	currentClaims, err := driver.Store(storeCtx(), []*commonpb.Payload{current})
+	require.NoError(t, err)
+
+	retrieved, err := driver.Retrieve(retrieveCtx(), append(legacyClaims, currentClaims...))
+	require.NoError(t, err)
+	require.True(t, proto.Equal(legacy, retrieved[0]))
+	require.True(t, proto.Equal(current, retrieved[1]))
+}
+
+func TestCodecStorageDriver_CompressAndEncrypt(t *testing.T) {
+	compression, err := converter.NewCompressionCodec(converter.CompressionCodecOptions{
+		Compressors: []converter.PayloadCompressor{converter.NewZlibCompressor()},
+	})
+	require.NoError(t, err)
+	keys, err := converter.NewStaticEncryptionKeyProvider(converter.StaticEncryptionKeyProviderOptions{
+		CurrentKeyID: "k1",
+		Keys:         map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)},
+	})
+	require.NoError(t, err)
+	encryption, err := converter.NewEncryptionCodec(converter.EncryptionCodecOptions{KeyProvider: keys})
+	require.NoError(t, err)
+
+	inner := newMemDriver("mem")
+	driver, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  inner,
+		Codecs:  []converter.PayloadCodec{encryption, compression},
+		CodecID: "zlib+aes-gcm",
+	})
+	require.NoError(t, err)
+
+	original := makePayload(t, strings.Repeat("compressible ", 1000))
+	claims, err := driver.Store(storeCtx(), []*commonpb.Payload{original})
+	require.NoError(t, err)
+
+	stored, err := inner.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Equal(t, converter.MetadataEncodingEncrypted, encoding(stored[0]))
+	require.Less(t, len(stored[0].Data), len(original.Data))
+
+	retrieved, err := driver.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(original, retrieved[0]))
+}
+
+func TestCodecStorageDriver_ExternalStorage(t *testing.T) {
+	driver, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{
+		Driver:  newMemDriver("mem"),
+		Codecs:  []converter.PayloadCodec{&appendCodec{"+a", 'a'}},
+		CodecID: "v1",
+	})
+	require.NoError(t, err)
+
+	original := makePayload(t, "value")
+	claims, err := driver.Store(storeCtx(), []*commonpb.Payload{original})
+	require.NoError(t, err)
+
+	// The codec ID survives the claim's round trip through a storage
+	// reference, so the driver can decode the payload when it is downloaded.
+	h, err := converter.NewPayloadHTTPHandler(converter.PayloadHTTPHandlerOptions{
+		ExternalStorage: converter.ExternalStorage{Drivers: []converter.StorageDriver{driver}},
+	})
+	require.NoError(t, err)
+	data, err := json.Marshal(storageRefJSON{DriverName: "mem", DriverClaim: claims[0]})
+	require.NoError(t, err)
+	ref := &commonpb.Payload{
+		Metadata: map[string][]byte{converter.MetadataEncoding: []byte("json/external-storage-reference")},
+		Data:     data,
+	}
+	result := getPayloads(t, servePost(t, h, "/download", createRequest(t, ref)))
+	require.True(t, proto.Equal(original, result[0]))
+}
+
+func TestNewCodecStorageDriver_Validation(t *testing.T) {
+	codecs := []converter.PayloadCodec{&appendCodec{"+a", 'a'}}
+	_, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{Codecs: codecs, CodecID: "v1"})
+	require.EqualError(t, err, "Driver is required")
+	_, err = converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{Driver: newMemDriver("mem"), CodecID: "v1"})
+	require.EqualError(t, err, "at least one codec is required")
+	_, err = converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{Driver: newMemDriver("mem"), Codecs: codecs})
+	require.EqualError(t, err, "CodecID is required")
+
+	driver, err := converter.NewCodecStorageDriver(converter.CodecStorageDriverOptions{Driver: newMemDriver("mem"), Codecs: codecs, CodecID: "v1"})
+	require.NoError(t, err)
+	_, ok := driver.(converter.StorageDriverDeleter)
+	require.False(t, ok)
+}
@@ -0,0 +1,236 @@
+package converter
+
+import (
+	"bytes"
+	"cmp"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// metadataEncodingZlib is the encoding written by NewZlibCodec and the
+// compressor returned by NewZlibCompressor.
+const metadataEncodingZlib = "binary/zlib"
+
+// PayloadCompressor is a compression algorithm usable with
+// NewCompressionCodec. Compressors operate on the serialized bytes of a whole
+// payload.
+//
+// NOTE: Experimental
+type PayloadCompressor interface {
+	// Encoding returns the value stored in the MetadataEncoding of payloads
+	// compressed by this compressor, e.g. "binary/zstd". It must be unique
+	// among the compressors passed to a codec.
+	Encoding() string
+
+	// Compress returns the compressed form of data.
+	Compress(data []byte) ([]byte, error)
+
+	// Decompress reverses Compress.
+	Decompress(data []byte) ([]byte, error)
+}
+
+type zlibCompressor struct{}
+
+// NewZlibCompressor returns a PayloadCompressor using zlib. Payloads it
+// compresses use the same format as NewZlibCodec, so the two can read each
+// other's output.
+//
+// NOTE: Experimental
+func NewZlibCompressor() PayloadCompressor { return zlibCompressor{} }
+
+func (zlibCompressor) Encoding() string { return metadataEncodingZlib }
+
+func (zlibCompressor) Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, err := w.Write(data)
+	if closeErr := w.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func (zlibCompressor) Decompress(data []byte) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(r)
+	if closeErr := r.Close(); closeErr != nil && err == nil {
+		err = closeErr
+	}
+	return b, err
+}
+
+// CompressionCodecOptions are options for NewCompressionCodec.
+//
+// NOTE: Experimental
+type CompressionCodecOptions struct {
+	// Compressors are the algorithms the codec can decode. Unless
+	// SelectCompressor is set, the first one is also used to encode. Required.
+	Compressors []PayloadCompressor
+
+	// MinSize is the serialized payload size in bytes below which payloads are
+	// left uncompressed. Compressing small payloads rarely pays off. Zero
+	// compresses every payload.
+	MinSize int
+
+	// SelectCompressor, if set, chooses the compressor for each payload that is
+	// at least MinSize bytes. size is the serialized size of the payload. It
+	// must return one of Compressors, or nil to leave the payload uncompressed.
+	// See SelectCompressorBySize for a selector picking by payload size.
+	SelectCompressor func(payload *commonpb.Payload, size int) PayloadCompressor
+
+	// If true, the codec will use the compressed form even if there is no size
+	// benefit. Otherwise, the compressed form is only used if it is smaller.
+	AlwaysEncode bool
+}
+
+// CompressorSizeThreshold pairs a compressor with the serialized payload size
+// from which SelectCompressorBySize picks it.
+//
+// NOTE: Experimental
+type CompressorSizeThreshold struct {
+	// MinSize is the serialized payload size in bytes from which Compressor is
+	// used.
+	MinSize int
+	// Compressor is the compressor used for payloads of at least MinSize bytes,
+	// or nil to leave them uncompressed.
+	Compressor PayloadCompressor
+}
+
+// SelectCompressorBySize returns a selector for
+// CompressionCodecOptions.SelectCompressor that picks, for each payload, the
+// compressor of the threshold with the largest MinSize that the payload
+// reaches. Payloads below every threshold are left uncompressed. For example,
+// to use a fast compressor for medium payloads and a stronger one for large
+// payloads:
+//
+//	converter.SelectCompressorBySize(
+//		converter.CompressorSizeThreshold{MinSize: 4 * 1024, Compressor: snappy},
+//		converter.CompressorSizeThreshold{MinSize: 256 * 1024, Compressor: zstd},
+//	)
+//
+// NOTE: Experimental
+func SelectCompressorBySize(thresholds ...CompressorSizeThreshold) func(payload *commonpb.Payload, size int) PayloadCompressor {
+	sorted := slices.Clone(thresholds)
+	slices.SortStableFunc(sorted, func(a, b CompressorSizeThreshold) int { return cmp.Compare(b.MinSize, a.MinSize) })
+	return func(_ *commonpb.Payload, size int) PayloadCompressor {
+		for _, t := range sorted {
+			if size >= t.MinSize {
+				return t.Compressor
+			}
+		}
+		return nil
+	}
+}
+
+type compressionCodec struct {
+	options     CompressionCodecOptions
+	byEncoding  map[string]PayloadCompressor
+	defaultComp PayloadCompressor
+}
+
+// NewCompressionCodec creates a PayloadCodec for use in NewCodecDataConverter
+// that compresses payloads with one of several algorithms.
+//
+// On encode, payloads smaller than MinSize are passed through unchanged and
+// the rest are compressed by the compressor picked by SelectCompressor, or the
+// first compressor if no selector is set. Use SelectCompressorBySize to pick
+// the compressor by payload size. On decode, the payload's encoding
+// metadata selects the compressor, so histories containing payloads written
+// with different algorithms, or by NewZlibCodec when NewZlibCompressor is
+// included, decode cleanly.
+//
+// NOTE: Experimental
+func NewCompressionCodec(options CompressionCodecOptions) (PayloadCodec, error) {
+	if len(options.Compressors) == 0 {
+		return nil, errors.New("at least one compressor is required")
+	}
+	if options.MinSize < 0 {
+		return nil, fmt.Errorf("MinSize must not be negative, got %d", options.MinSize)
+	}
+	byEncoding := make(map[string]PayloadCompressor, len(options.Compressors))
+	for _, c := range options.Compressors {
+		if _, exists := byEncoding[c.Encoding()]; exists {
+			return nil, fmt.Errorf("duplicate compressor encoding: %q", c.Encoding())
+		}
+		byEncoding[c.Encoding()] = c
+	}
+	return &compressionCodec{
+		options:     options,
+		byEncoding:  byEncoding,
+		defaultComp: options.Compressors[0],
+	}, nil
+}
+
+func (c *compressionCodec) Encode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		b, err := proto.Marshal(p)
+		if err != nil {
+			return payloads, err
+		}
+		if len(b) < c.options.MinSize {
+			result[i] = p
+			continue
+		}
+		comp := c.defaultComp
+		if c.options.SelectCompressor != nil {
+			selected := c.options.SelectCompressor(p, len(b))
+			if selected == nil {
+				result[i] = p
+				continue
+			}
+			registered, ok := c.byEncoding[selected.Encoding()]
+			if !ok {
+				return payloads, fmt.Errorf("compressor selector returned unregistered compressor %q", selected.Encoding())
+			}
+			comp = registered
+		}
+		compressed, err := comp.Compress(b)
+		if err != nil {
+			return payloads, fmt.Errorf("%s compression failed: %w", comp.Encoding(), err)
+		}
+		// Only set if smaller than original amount or has option to always encode
+		if len(compressed) < len(b) || c.options.AlwaysEncode {
+			result[i] = &commonpb.Payload{
+				Metadata: map[string][]byte{MetadataEncoding: []byte(comp.Encoding())},
+				Data:     compressed,
+			}
+		} else {
+			result[i] = p
+		}
+	}
+	return result, nil
+}
+
+func (c *compressionCodec) Decode(payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+	result := make([]*commonpb.Payload, len(payloads))
+	for i, p := range payloads {
+		// Only if it's one of our encodings
+		comp, ok := c.byEncoding[string(p.Metadata[MetadataEncoding])]
+		if !ok {
+			result[i] = p
+			continue
+		}
+		b, err := comp.Decompress(p.Data)
+		if err != nil {
+			return payloads, fmt.Errorf("%s decompression failed: %w", comp.Encoding(), err)
+		}
+		result[i] = &commonpb.Payload{}
+		if err := proto.Unmarshal(b, result[i]); err != nil {
+			return payloads, err
+		}
+	}
+	return result, nil
+}
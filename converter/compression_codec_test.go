@@ -0,0 +1,193 @@
+package converter
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// reverseCompressor is a fake PayloadCompressor that reverses its input and
+// prefixes a marker so that its output is distinguishable in tests.
+type reverseCompressor struct{ encoding string }
+
+func (r reverseCompressor) Encoding() string { return r.encoding }
+
+func (r reverseCompressor) Compress(data []byte) ([]byte, error) {
+	out := []byte{'!'}
+	for i := len(data) - 1; i >= 0; i-- {
+		out = append(out, data[i])
+	}
+	return out, nil
+}
+
+func (r reverseCompressor) Decompress(data []byte) ([]byte, error) {
+	data = bytes.TrimPrefix(data, []byte{'!'})
+	out := make([]byte, 0, len(data))
+	for i := len(data) - 1; i >= 0; i-- {
+		out = append(out, data[i])
+	}
+	return out, nil
+}
+
+func TestCompressionCodec_MinSize(t *testing.T) {
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{NewZlibCompressor()},
+		MinSize:     100,
+	})
+	require.NoError(t, err)
+
+	small, err := GetDefaultDataConverter().ToPayload(strings.Repeat("a", 10))
+	require.NoError(t, err)
+	large, err := GetDefaultDataConverter().ToPayload(strings.Repeat("a", 1000))
+	require.NoError(t, err)
+
+	encoded, err := codec.Encode([]*commonpb.Payload{small, large})
+	require.NoError(t, err)
+	require.Same(t, small, encoded[0])
+	require.Equal(t, "binary/zlib", string(encoded[1].Metadata[MetadataEncoding]))
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(small, decoded[0]))
+	require.True(t, proto.Equal(large, decoded[1]))
+}
+
+func TestCompressionCodec_OnlyWhenSmaller(t *testing.T) {
+	p := &commonpb.Payload{Data: []byte("x")}
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{reverseCompressor{"binary/reverse"}},
+	})
+	require.NoError(t, err)
+	encoded, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Same(t, p, encoded[0])
+
+	codec, err = NewCompressionCodec(CompressionCodecOptions{
+		Compressors:  []PayloadCompressor{reverseCompressor{"binary/reverse"}},
+		AlwaysEncode: true,
+	})
+	require.NoError(t, err)
+	encoded, err = codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Equal(t, "binary/reverse", string(encoded[0].Metadata[MetadataEncoding]))
+}
+
+func TestCompressionCodec_SelectCompressor(t *testing.T) {
+	fast := reverseCompressor{"binary/fast"}
+	dense := reverseCompressor{"binary/dense"}
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{fast, dense},
+		SelectCompressor: func(payload *commonpb.Payload, size int) PayloadCompressor {
+			if string(payload.Metadata[MetadataEncoding]) == MetadataEncodingBinary {
+				return nil
+			}
+			if size > 500 {
+				return dense
+			}
+			return fast
+		},
+		AlwaysEncode: true,
+	})
+	require.NoError(t, err)
+
+	small := &commonpb.Payload{Data: []byte("small")}
+	large := &commonpb.Payload{Data: bytes.Repeat([]byte("l"), 1000)}
+	binary := &commonpb.Payload{Metadata: map[string][]byte{MetadataEncoding: []byte(MetadataEncodingBinary)}, Data: []byte("bin")}
+	encoded, err := codec.Encode([]*commonpb.Payload{small, large, binary})
+	require.NoError(t, err)
+	require.Equal(t, "binary/fast", string(encoded[0].Metadata[MetadataEncoding]))
+	require.Equal(t, "binary/dense", string(encoded[1].Metadata[MetadataEncoding]))
+	require.Same(t, binary, encoded[2])
+
+	decoded, err := codec.Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(small, decoded[0]))
+	require.True(t, proto.Equal(large, decoded[1]))
+	require.Same(t, binary, decoded[2])
+}
+
+func TestSelectCompressorBySize(t *testing.T) {
+	fast := reverseCompressor{"binary/fast"}
+	dense := reverseCompressor{"binary/dense"}
+	selectCompressor := SelectCompressorBySize(
+		CompressorSizeThreshold{MinSize: 1000, Compressor: dense},
+		CompressorSizeThreshold{MinSize: 100, Compressor: fast},
+		CompressorSizeThreshold{MinSize: 5000},
+	)
+	require.Nil(t, selectCompressor(nil, 99))
+	require.Equal(t, fast, selectCompressor(nil, 100))
+	require.Equal(t, fast, selectCompressor(nil, 999))
+	require.Equal(t, dense, selectCompressor(nil, 1000))
+	require.Nil(t, selectCompressor(nil, 5000))
+
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors:      []PayloadCompressor{fast, dense},
+		SelectCompressor: selectCompressor,
+		AlwaysEncode:     true,
+	})
+	require.NoError(t, err)
+	small := &commonpb.Payload{Data: []byte("small")}
+	medium := &commonpb.Payload{Data: bytes.Repeat([]byte("m"), 200)}
+	large := &commonpb.Payload{Data: bytes.Repeat([]byte("l"), 2000)}
+	encoded, err := codec.Encode([]*commonpb.Payload{small, medium, large})
+	require.NoError(t, err)
+	require.Same(t, small, encoded[0])
+	require.Equal(t, "binary/fast", string(encoded[1].Metadata[MetadataEncoding]))
+	require.Equal(t, "binary/dense", string(encoded[2].Metadata[MetadataEncoding]))
+}
+
+func TestCompressionCodec_SelectUnregisteredCompressor(t *testing.T) {
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{NewZlibCompressor()},
+		SelectCompressor: func(*commonpb.Payload, int) PayloadCompressor {
+			return reverseCompressor{"binary/other"}
+		},
+	})
+	require.NoError(t, err)
+	_, err = codec.Encode([]*commonpb.Payload{{Data: []byte("x")}})
+	require.EqualError(t, err, `compressor selector returned unregistered compressor "binary/other"`)
+}
+
+func TestCompressionCodec_DecodesZlibCodecOutput(t *testing.T) {
+	p, err := GetDefaultDataConverter().ToPayload(strings.Repeat("aabbcc", 200))
+	require.NoError(t, err)
+	fromZlibCodec, err := NewZlibCodec(ZlibCodecOptions{}).Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	codec, err := NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{reverseCompressor{"binary/reverse"}, NewZlibCompressor()},
+	})
+	require.NoError(t, err)
+	decoded, err := codec.Decode(fromZlibCodec)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, decoded[0]))
+
+	// And the other way around.
+	zlibOnly, err := NewCompressionCodec(CompressionCodecOptions{Compressors: []PayloadCompressor{NewZlibCompressor()}})
+	require.NoError(t, err)
+	encoded, err := zlibOnly.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	decoded, err = NewZlibCodec(ZlibCodecOptions{}).Decode(encoded)
+	require.NoError(t, err)
+	require.True(t, proto.Equal(p, decoded[0]))
+}
+
+func TestNewCompressionCodec_Validation(t *testing.T) {
+	_, err := NewCompressionCodec(CompressionCodecOptions{})
+	require.EqualError(t, err, "at least one compressor is required")
+
+	_, err = NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{NewZlibCompressor()},
+		MinSize:     -1,
+	})
+	require.EqualError(t, err, "MinSize must not be negative, got -1")
+
+	_, err = NewCompressionCodec(CompressionCodecOptions{
+		Compressors: []PayloadCompressor{NewZlibCompressor(), NewZlibCompressor()},
+	})
+	require.EqualError(t, err, `duplicate compressor encoding: "binary/zlib"`)
+}
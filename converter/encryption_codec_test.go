@@ -0,0 +1,188 @@
+package converter
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+func newTestEncryptionCodec(t *testing.T, currentKeyID string, keys map[string][]byte) PayloadCodec {
+	t.Helper()
+	provider, err := NewStaticEncryptionKeyProvider(StaticEncryptionKeyProviderOptions{
+		CurrentKeyID: currentKeyID,
+		Keys:         keys,
+	})
+	require.NoError(t, err)
+	codec, err := NewEncryptionCodec(EncryptionCodecOptions{KeyProvider: provider})
+	require.NoError(t, err)
+	return codec
+}
+
+func TestEncryptionCodec_RoundTrip(t *testing.T) {
+	codec := newTestEncryptionCodec(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	dc := NewCodecDataConverter(GetDefaultDataConverter(), codec)
+
+	payload, err := dc.ToPayload("secret value")
+	require.NoError(t, err)
+	require.Equal(t, MetadataEncodingEncrypted, string(payload.Metadata[MetadataEncoding]))
+	require.Equal(t, "k1", string(payload.Metadata[MetadataEncryptionKeyID]))
+	require.NotContains(t, string(payload.Data), "secret value")
+
+	var result string
+	require.NoError(t, dc.FromPayload(payload, &result))
+	require.Equal(t, "secret value", result)
+}
+
+func TestEncryptionCodec_NondeterministicCiphertext(t *testing.T) {
+	codec := newTestEncryptionCodec(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 16)})
+	p := &commonpb.Payload{Data: []byte("same")}
+	a, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	b, err := codec.Encode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.NotEqual(t, a[0].Data, b[0].Data)
+}
+
+func TestEncryptionCodec_DecodeSkipsUnencrypted(t *testing.T) {
+	codec := newTestEncryptionCodec(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	p, err := GetDefaultDataConverter().ToPayload("plain")
+	require.NoError(t, err)
+	result, err := codec.Decode([]*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Same(t, p, result[0])
+}
+
+func TestEncryptionCodec_KeyRotation(t *testing.T) {
+	oldKey := bytes.Repeat([]byte{1}, 32)
+	newKey := bytes.Repeat([]byte{2}, 32)
+	oldCodec := newTestEncryptionCodec(t, "old", map[string][]byte{"old": oldKey})
+	rotatedCodec := newTestEncryptionCodec(t, "new", map[string][]byte{"old": oldKey, "new": newKey})
+
+	original := &commonpb.Payload{Data: []byte("value")}
+	oldEncoded, err := oldCodec.Encode([]*commonpb.Payload{original})
+	require.NoError(t, err)
+	newEncoded, err := rotatedCodec.Encode([]*commonpb.Payload{original})
+	require.NoError(t, err)
+	require.Equal(t, "new", string(newEncoded[0].Metadata[MetadataEncryptionKeyID]))
+
+	// The rotated codec can read both generations.
+	decoded, err := rotatedCodec.Decode([]*commonpb.Payload{oldEncoded[0], newEncoded[0]})
+	require.NoError(t, err)
+	require.True(t, proto.Equal(original, decoded[0]))
+	require.True(t, proto.Equal(original, decoded[1]))
+
+	// The old codec does not know the new key.
+	_, err = oldCodec.Decode(newEncoded)
+	require.ErrorContains(t, err, `unknown key ID "new"`)
+}
+
+func TestEncryptionCodec_TamperedPayload(t *testing.T) {
+	codec := newTestEncryptionCodec(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	encoded, err := codec.Encode([]*commonpb.Payload{{Data: []byte("value")}})
+	require.NoError(t, err)
+	encoded[0].Data[len(encoded[0].Data)-1] ^= 0xff
+	_, err = codec.Decode(encoded)
+	require.ErrorContains(t, err, "failed to decrypt payload")
+}
+
+type namespaceKeyProvider struct {
+	keys map[string][]byte
+}
+
+func (p *namespaceKeyProvider) EncryptionKey(ctx SerializationContext) (string, []byte, error) {
+	wf, ok := ctx.(WorkflowSerializationContext)
+	if !ok {
+		return "", nil, errors.New("workflow context required")
+	}
+	return wf.Namespace, p.keys[wf.Namespace], nil
+}
+
+func (p *namespaceKeyProvider) DecryptionKey(_ SerializationContext, keyID string) ([]byte, error) {
+	key, ok := p.keys[keyID]
+	if !ok {
+		return nil, errors.New("unknown key")
+	}
+	return key, nil
+}
+
+func TestEncryptionCodec_SerializationContext(t *testing.T) {
+	provider := &namespaceKeyProvider{keys: map[string][]byte{
+		"ns1": bytes.Repeat([]byte{1}, 32),
+		"ns2": bytes.Repeat([]byte{2}, 32),
+	}}
+	codec, err := NewEncryptionCodec(EncryptionCodecOptions{KeyProvider: provider})
+	require.NoError(t, err)
+	dc := NewCodecDataConverter(GetDefaultDataConverter(), codec)
+
+	_, err = dc.ToPayload("value")
+	require.ErrorContains(t, err, "workflow context required")
+
+	ns1 := WithDataConverterSerializationContext(dc, WorkflowSerializationContext{Namespace: "ns1", WorkflowID: "wf"})
+	ns2 := WithDataConverterSerializationContext(dc, WorkflowSerializationContext{Namespace: "ns2", WorkflowID: "wf"})
+	p1, err := ns1.ToPayload("value")
+	require.NoError(t, err)
+	p2, err := ns2.ToPayload("value")
+	require.NoError(t, err)
+	require.Equal(t, "ns1", string(p1.Metadata[MetadataEncryptionKeyID]))
+	require.Equal(t, "ns2", string(p2.Metadata[MetadataEncryptionKeyID]))
+
+	// Decoding resolves keys by ID alone, so a context-free converter can read both.
+	var s1, s2 string
+	require.NoError(t, dc.FromPayload(p1, &s1))
+	require.NoError(t, dc.FromPayload(p2, &s2))
+	require.Equal(t, "value", s1)
+	require.Equal(t, "value", s2)
+}
+
+func TestEncryptionCodec_HTTPHandler(t *testing.T) {
+	codec := newTestEncryptionCodec(t, "k1", map[string][]byte{"k1": bytes.Repeat([]byte{1}, 32)})
+	encoded, err := NewCodecDataConverter(GetDefaultDataConverter(), codec).ToPayload("value")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(NewPayloadCodecHTTPHandler(codec))
+	defer server.Close()
+
+	body, err := json.Marshal(commonpb.Payloads{Payloads: []*commonpb.Payload{encoded}})
+	require.NoError(t, err)
+	resp, err := http.Post(server.URL+"/decode", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	defer func() { _ = resp.Body.Close() }()
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var buf bytes.Buffer
+	_, err = buf.ReadFrom(resp.Body)
+	require.NoError(t, err)
+	var decoded commonpb.Payloads
+	require.NoError(t, protojson.Unmarshal(buf.Bytes(), &decoded))
+	var result string
+	require.NoError(t, GetDefaultDataConverter().FromPayload(decoded.Payloads[0], &result))
+	require.Equal(t, "value", result)
+}
+
+func TestNewEncryptionCodec_Validation(t *testing.T) {
+	_, err := NewEncryptionCodec(EncryptionCodecOptions{})
+	require.EqualError(t, err, "KeyProvider is required")
+
+	_, err = NewStaticEncryptionKeyProvider(StaticEncryptionKeyProviderOptions{})
+	require.EqualError(t, err, "CurrentKeyID is required")
+
+	_, err = NewStaticEncryptionKeyProvider(StaticEncryptionKeyProviderOptions{
+		CurrentKeyID: "k1",
+		Keys:         map[string][]byte{"k2": bytes.Repeat([]byte{1}, 32)},
+	})
+	require.EqualError(t, err, `current key "k1" not found in Keys`)
+
+	_, err = NewStaticEncryptionKeyProvider(StaticEncryptionKeyProviderOptions{
+		CurrentKeyID: "k1",
+		Keys:         map[string][]byte{"k1": []byte("short")},
+	})
+	require.ErrorContains(t, err, `invalid key "k1"`)
+}
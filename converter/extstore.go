@@ -1,6 +1,11 @@
 package converter
 
-import "go.temporal.io/sdk/internal/extstore"
+import (
+	"context"
+
+	"go.temporal.io/sdk/internal/extstore"
+	"google.golang.org/protobuf/proto"
+)
 
 // StorageDriverTargetInfo identifies the workflow or activity on whose behalf
 // a payload is being stored. Use a type switch on [StorageDriverWorkflowInfo]
@@ -58,7 +63,8 @@ type StorageDriver = extstore.StorageDriver
 // example, routing large binary blobs to object storage while keeping small
 // JSON payloads inline. If no selector is set, the first driver in
 // ExternalStorage.Drivers is used for every payload that exceeds the size
-// threshold.
+// threshold. Built-in selectors that route by encoding, size or target can be
+// combined with NewChainStorageDriverSelector.
 //
 // NOTE: Experimental
 type StorageDriverSelector = extstore.StorageDriverSelector
@@ -72,3 +78,88 @@ type StorageDriverSelector = extstore.StorageDriverSelector
 //
 // NOTE: Experimental
 type ExternalStorage = extstore.ExternalStorage
+
+// StorageDriverDeleteContext carries context passed to
+// StorageDriverDeleter.Delete operations.
+//
+// NOTE: Experimental
+type StorageDriverDeleteContext = extstore.StorageDriverDeleteContext
+
+// StorageDriverDeleter is an optional interface that a StorageDriver may
+// implement to support removing previously stored payloads. It is never called
+// by the SDK on the workflow or activity path; it exists so that operators can
+// purge payloads belonging to executions whose retention period has expired.
+// See [DeleteExternalStorageClaims].
+//
+// NOTE: Experimental
+type StorageDriverDeleter = extstore.StorageDriverDeleter
+
+// ExternalStorageClaim pairs a StorageDriverClaim with the name of the driver
+// that issued it, as recorded in a storage reference payload.
+//
+// NOTE: Experimental
+type ExternalStorageClaim = extstore.ExternalStorageClaim
+
+// CollectExternalStorageClaims returns the external storage claims referenced
+// by the payloads in msg, de-duplicated and in the order they were first
+// encountered. msg is typically a history event or a full history; see
+// client.CollectHistoryExternalStorageClaims to collect the claims of a
+// workflow execution directly from the server.
+//
+// NOTE: Experimental
+func CollectExternalStorageClaims(ctx context.Context, msg proto.Message) ([]ExternalStorageClaim, error) {
+	return extstore.CollectExternalStorageClaims(ctx, msg)
+}
+
+// DeleteExternalStorageClaims deletes the payloads identified by claims using
+// the matching driver from drivers. Every driver referenced by claims must be
+// present in drivers and must implement [StorageDriverDeleter]; otherwise no
+// payloads are deleted and an error is returned. Failures from individual
+// drivers do not prevent the remaining drivers from being called; all failures
+// are returned joined together.
+//
+// Only delete claims belonging to executions that are closed and will not be
+// read again, e.g. because their retention period has expired. Payloads stored
+// without a workflow or activity target are keyed by content alone by the
+// built-in drivers and may be shared with other executions.
+//
+// NOTE: Experimental
+func DeleteExternalStorageClaims(ctx context.Context, drivers []StorageDriver, claims []ExternalStorageClaim) error {
+	return extstore.DeleteExternalStorageClaims(ctx, drivers, claims)
+}
+
+// StorageChunkManifest describes a payload that a driver stores or retrieves
+// as a sequence of fixed-size chunks, e.g. through a multipart upload and
+// ranged downloads. It records a SHA-256 hash per chunk so that each chunk can
+// be verified as soon as it arrives, rather than only after the whole payload
+// has been downloaded.
+//
+// NOTE: Experimental
+type StorageChunkManifest = extstore.StorageChunkManifest
+
+// NewStorageChunkManifest splits data into chunks of chunkSize bytes and
+// returns a manifest with the hash of each chunk.
+//
+// NOTE: Experimental
+func NewStorageChunkManifest(data []byte, chunkSize int64) (StorageChunkManifest, error) {
+	return extstore.NewStorageChunkManifest(data, chunkSize)
+}
+
+// StorageChunkManifestKey returns the key of the manifest recorded in the claim
+// with StorageChunkManifest.AddToClaim. The boolean result is false if the
+// claim carries no manifest.
+//
+// NOTE: Experimental
+func StorageChunkManifestKey(claim StorageDriverClaim) (string, bool) {
+	return extstore.StorageChunkManifestKey(claim)
+}
+
+// DecodeStorageChunkManifest decodes a manifest encoded by
+// StorageChunkManifest.Marshal and read back from the key returned by
+// StorageChunkManifestKey, after checking it against the hash recorded in the
+// claim.
+//
+// NOTE: Experimental
+func DecodeStorageChunkManifest(claim StorageDriverClaim, encoded []byte) (StorageChunkManifest, error) {
+	return extstore.DecodeStorageChunkManifest(claim, encoded)
+}
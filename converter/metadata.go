@@ -15,4 +15,10 @@ const (
 	MetadataEncodingProtoJSON = "json/protobuf"
 	// MetadataEncodingProto is "binary/protobuf"
 	MetadataEncodingProto = "binary/protobuf"
+	// MetadataEncodingEncrypted is "binary/encrypted"
+	MetadataEncodingEncrypted = "binary/encrypted"
+	// MetadataEncryptionKeyID is "encryption-key-id"
+	MetadataEncryptionKeyID = "encryption-key-id"
+	// MetadataEncryptionCipher is "encryption-cipher"
+	MetadataEncryptionCipher = "encryption-cipher"
 )
@@ -0,0 +1,204 @@
+package converter
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+// StorageDriverSelectorFunc is an adapter to allow the use of an ordinary
+// function as a StorageDriverSelector.
+//
+// NOTE: Experimental
+type StorageDriverSelectorFunc func(ctx StorageDriverStoreContext, payload *commonpb.Payload) (StorageDriver, error)
+
+// SelectDriver calls f(ctx, payload).
+func (f StorageDriverSelectorFunc) SelectDriver(ctx StorageDriverStoreContext, payload *commonpb.Payload) (StorageDriver, error) {
+	return f(ctx, payload)
+}
+
+// NewEncodingStorageDriverSelector creates a StorageDriverSelector that routes
+// payloads by the value of their "encoding" metadata, e.g. "binary/plain" or
+// "json/protobuf", using drivers keyed by encoding. Payloads with any other
+// encoding are not matched and the selector returns nil.
+//
+// NOTE: Experimental
+func NewEncodingStorageDriverSelector(drivers map[string]StorageDriver) (StorageDriverSelector, error) {
+	if len(drivers) == 0 {
+		return nil, errors.New("at least one driver is required")
+	}
+	byEncoding := make(map[string]StorageDriver, len(drivers))
+	for encoding, driver := range drivers {
+		if driver == nil {
+			return nil, fmt.Errorf("driver for encoding %q is nil", encoding)
+		}
+		byEncoding[encoding] = driver
+	}
+	return StorageDriverSelectorFunc(func(_ StorageDriverStoreContext, payload *commonpb.Payload) (StorageDriver, error) {
+		return byEncoding[string(payload.GetMetadata()[MetadataEncoding])], nil
+	}), nil
+}
+
+// StorageDriverSizeTier routes payloads of at least MinSize bytes to Driver.
+// See NewSizeTierStorageDriverSelector.
+//
+// NOTE: Experimental
+type StorageDriverSizeTier struct {
+	// MinSize is the minimum serialized payload size in bytes for this tier.
+	MinSize int
+	// Driver stores payloads in this tier. Required.
+	Driver StorageDriver
+}
+
+// NewSizeTierStorageDriverSelector creates a StorageDriverSelector that routes
+// each payload to the tier with the largest MinSize not exceeding the
+// payload's serialized size. Payloads smaller than every tier are not matched
+// and the selector returns nil. Sizes are measured the same way as
+// ExternalStorage.PayloadSizeThreshold, so a tier with a MinSize at or below
+// the threshold applies to every externally stored payload that no larger
+// tier matches.
+//
+// NOTE: Experimental
+func NewSizeTierStorageDriverSelector(tiers []StorageDriverSizeTier) (StorageDriverSelector, error) {
+	if len(tiers) == 0 {
+		return nil, errors.New("at least one tier is required")
+	}
+	sorted := make([]StorageDriverSizeTier, len(tiers))
+	copy(sorted, tiers)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].MinSize > sorted[j].MinSize })
+	for i, tier := range sorted {
+		if tier.Driver == nil {
+			return nil, fmt.Errorf("driver for tier with MinSize %d is nil", tier.MinSize)
+		}
+		if tier.MinSize < 0 {
+			return nil, fmt.Errorf("MinSize must not be negative, got %d", tier.MinSize)
+		}
+		if i > 0 && sorted[i-1].MinSize == tier.MinSize {
+			return nil, fmt.Errorf("duplicate tier with MinSize %d", tier.MinSize)
+		}
+	}
+	return StorageDriverSelectorFunc(func(_ StorageDriverStoreContext, payload *commonpb.Payload) (StorageDriver, error) {
+		size := proto.Size(payload)
+		for _, tier := range sorted {
+			if size >= tier.MinSize {
+				return tier.Driver, nil
+			}
+		}
+		return nil, nil
+	}), nil
+}
+
+// StorageDriverTargetRule routes payloads whose StorageDriverStoreContext.Target
+// matches every non-empty field of the rule to Driver. See
+// NewTargetStorageDriverSelector.
+//
+// NOTE: Experimental
+type StorageDriverTargetRule struct {
+	// Namespace matches the namespace of a workflow or standalone activity
+	// target.
+	Namespace string
+	// WorkflowType matches the workflow type of a workflow target. Rules that
+	// set it never match standalone activity targets.
+	WorkflowType string
+	// ActivityType matches the activity type of a standalone activity target.
+	// Rules that set it never match workflow targets.
+	ActivityType string
+	// Driver stores payloads matched by this rule. Required.
+	Driver StorageDriver
+}
+
+func (r StorageDriverTargetRule) matches(target StorageDriverTargetInfo) bool {
+	switch t := target.(type) {
+	case StorageDriverWorkflowInfo:
+		return r.ActivityType == "" &&
+			(r.Namespace == "" || r.Namespace == t.Namespace) &&
+			(r.WorkflowType == "" || r.WorkflowType == t.WorkflowType)
+	case StorageDriverActivityInfo:
+		return r.WorkflowType == "" &&
+			(r.Namespace == "" || r.Namespace == t.Namespace) &&
+			(r.ActivityType == "" || r.ActivityType == t.ActivityType)
+	default:
+		return false
+	}
+}
+
+// NewTargetStorageDriverSelector creates a StorageDriverSelector that routes
+// payloads by the workflow or standalone activity they are stored for, as
+// identified by StorageDriverStoreContext.Target. Rules are evaluated in order
+// and the first matching rule wins. Payloads matched by no rule, including
+// those stored without a target, are not matched and the selector returns nil.
+//
+// NOTE: Experimental
+func NewTargetStorageDriverSelector(rules []StorageDriverTargetRule) (StorageDriverSelector, error) {
+	if len(rules) == 0 {
+		return nil, errors.New("at least one rule is required")
+	}
+	for i, rule := range rules {
+		if rule.Driver == nil {
+			return nil, fmt.Errorf("driver for rule %d is nil", i)
+		}
+		if rule.Namespace == "" && rule.WorkflowType == "" && rule.ActivityType == "" {
+			return nil, fmt.Errorf("rule %d must set at least one of Namespace, WorkflowType or ActivityType", i)
+		}
+		if rule.WorkflowType != "" && rule.ActivityType != "" {
+			return nil, fmt.Errorf("rule %d must not set both WorkflowType and ActivityType", i)
+		}
+	}
+	rules = append([]StorageDriverTargetRule(nil), rules...)
+	return StorageDriverSelectorFunc(func(ctx StorageDriverStoreContext, _ *commonpb.Payload) (StorageDriver, error) {
+		for _, rule := range rules {
+			if rule.matches(ctx.Target) {
+				return rule.Driver, nil
+			}
+		}
+		return nil, nil
+	}), nil
+}
+
+// NewChainStorageDriverSelector creates a StorageDriverSelector that asks each
+// of selectors in order and returns the first driver selected. If no selector
+// selects a driver, fallback is returned; pass a nil fallback to leave such
+// payloads inline. An error from any selector is returned immediately.
+//
+// Together with the other built-in selectors this makes routing declarative.
+// For example, to store protobuf payloads in one driver, payloads over 10 MiB
+// in another, and everything else in a default driver:
+//
+//	byEncoding, _ := converter.NewEncodingStorageDriverSelector(map[string]converter.StorageDriver{
+//		"binary/protobuf": protoDriver,
+//	})
+//	bySize, _ := converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{
+//		{MinSize: 10 * 1024 * 1024, Driver: largeDriver},
+//	})
+//	selector, _ := converter.NewChainStorageDriverSelector(defaultDriver, byEncoding, bySize)
+//
+// Every driver a selector can return must also be listed in
+// ExternalStorage.Drivers.
+//
+// NOTE: Experimental
+func NewChainStorageDriverSelector(fallback StorageDriver, selectors ...StorageDriverSelector) (StorageDriverSelector, error) {
+	if len(selectors) == 0 {
+		return nil, errors.New("at least one selector is required")
+	}
+	for i, s := range selectors {
+		if s == nil {
+			return nil, fmt.Errorf("selector %d is nil", i)
+		}
+	}
+	selectors = append([]StorageDriverSelector(nil), selectors...)
+	return StorageDriverSelectorFunc(func(ctx StorageDriverStoreContext, payload *commonpb.Payload) (StorageDriver, error) {
+		for _, s := range selectors {
+			driver, err := s.SelectDriver(ctx, payload)
+			if err != nil {
+				return nil, err
+			}
+			if driver != nil {
+				return driver, nil
+			}
+		}
+		return fallback, nil
+	}), nil
+}
@@ -0,0 +1,174 @@
+package converter_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/sdk/converter"
+	"go.temporal.io/sdk/internal/extstore"
+	"google.golang.org/protobuf/proto"
+)
+
+func selectDriver(t *testing.T, s converter.StorageDriverSelector, target converter.StorageDriverTargetInfo, p *commonpb.Payload) converter.StorageDriver {
+	t.Helper()
+	ctx := storeCtx()
+	ctx.Target = target
+	driver, err := s.SelectDriver(ctx, p)
+	require.NoError(t, err)
+	return driver
+}
+
+func TestEncodingStorageDriverSelector(t *testing.T) {
+	jsonDriver, binaryDriver := newMemDriver("json"), newMemDriver("binary")
+	s, err := converter.NewEncodingStorageDriverSelector(map[string]converter.StorageDriver{
+		converter.MetadataEncodingJSON:   jsonDriver,
+		converter.MetadataEncodingBinary: binaryDriver,
+	})
+	require.NoError(t, err)
+
+	binary, err := converter.GetDefaultDataConverter().ToPayload([]byte("bytes"))
+	require.NoError(t, err)
+	require.Equal(t, jsonDriver, selectDriver(t, s, nil, makePayload(t, "value")))
+	require.Equal(t, binaryDriver, selectDriver(t, s, nil, binary))
+	require.Nil(t, selectDriver(t, s, nil, &commonpb.Payload{}))
+
+	_, err = converter.NewEncodingStorageDriverSelector(nil)
+	require.EqualError(t, err, "at least one driver is required")
+	_, err = converter.NewEncodingStorageDriverSelector(map[string]converter.StorageDriver{"json/plain": nil})
+	require.EqualError(t, err, `driver for encoding "json/plain" is nil`)
+}
+
+func TestSizeTierStorageDriverSelector(t *testing.T) {
+	medium, large := newMemDriver("medium"), newMemDriver("large")
+	s, err := converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{
+		{MinSize: 10_000, Driver: large},
+		{MinSize: 1_000, Driver: medium},
+	})
+	require.NoError(t, err)
+
+	small := makePayload(t, "small")
+	mid := makePayload(t, strings.Repeat("m", 5_000))
+	big := makePayload(t, strings.Repeat("l", 50_000))
+	require.Less(t, proto.Size(small), 1_000)
+	require.Nil(t, selectDriver(t, s, nil, small))
+	require.Equal(t, medium, selectDriver(t, s, nil, mid))
+	require.Equal(t, large, selectDriver(t, s, nil, big))
+
+	_, err = converter.NewSizeTierStorageDriverSelector(nil)
+	require.EqualError(t, err, "at least one tier is required")
+	_, err = converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{{MinSize: 1}})
+	require.EqualError(t, err, "driver for tier with MinSize 1 is nil")
+	_, err = converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{{MinSize: -1, Driver: large}})
+	require.EqualError(t, err, "MinSize must not be negative, got -1")
+	_, err = converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{
+		{MinSize: 1, Driver: medium},
+		{MinSize: 1, Driver: large},
+	})
+	require.EqualError(t, err, "duplicate tier with MinSize 1")
+}
+
+func TestTargetStorageDriverSelector(t *testing.T) {
+	orders, billing, reports := newMemDriver("orders"), newMemDriver("billing"), newMemDriver("reports")
+	s, err := converter.NewTargetStorageDriverSelector([]converter.StorageDriverTargetRule{
+		{Namespace: "billing", WorkflowType: "Invoice", Driver: billing},
+		{WorkflowType: "Order", Driver: orders},
+		{ActivityType: "Report", Driver: reports},
+		{Namespace: "billing", Driver: reports},
+	})
+	require.NoError(t, err)
+
+	p := makePayload(t, "value")
+	wf := func(ns, wt string) converter.StorageDriverTargetInfo {
+		return converter.StorageDriverWorkflowInfo{Namespace: ns, WorkflowType: wt, WorkflowID: "id", RunID: "run"}
+	}
+	act := func(ns, at string) converter.StorageDriverTargetInfo {
+		return converter.StorageDriverActivityInfo{Namespace: ns, ActivityType: at, ActivityID: "id", RunID: "run"}
+	}
+	require.Equal(t, billing, selectDriver(t, s, wf("billing", "Invoice"), p))
+	require.Equal(t, orders, selectDriver(t, s, wf("default", "Order"), p))
+	require.Equal(t, reports, selectDriver(t, s, wf("billing", "Other"), p))
+	require.Nil(t, selectDriver(t, s, wf("default", "Other"), p))
+	require.Equal(t, reports, selectDriver(t, s, act("default", "Report"), p))
+	// A WorkflowType rule never matches an activity with the same type name.
+	require.Nil(t, selectDriver(t, s, act("default", "Order"), p))
+	require.Equal(t, reports, selectDriver(t, s, act("billing", "Invoice"), p))
+	require.Nil(t, selectDriver(t, s, nil, p))
+
+	_, err = converter.NewTargetStorageDriverSelector(nil)
+	require.EqualError(t, err, "at least one rule is required")
+	_, err = converter.NewTargetStorageDriverSelector([]converter.StorageDriverTargetRule{{Namespace: "ns"}})
+	require.EqualError(t, err, "driver for rule 0 is nil")
+	_, err = converter.NewTargetStorageDriverSelector([]converter.StorageDriverTargetRule{{Driver: orders}})
+	require.EqualError(t, err, "rule 0 must set at least one of Namespace, WorkflowType or ActivityType")
+	_, err = converter.NewTargetStorageDriverSelector([]converter.StorageDriverTargetRule{{WorkflowType: "a", ActivityType: "b", Driver: orders}})
+	require.EqualError(t, err, "rule 0 must not set both WorkflowType and ActivityType")
+}
+
+func TestChainStorageDriverSelector(t *testing.T) {
+	binaryDriver, large, fallback := newMemDriver("binary"), newMemDriver("large"), newMemDriver("fallback")
+	byEncoding, err := converter.NewEncodingStorageDriverSelector(map[string]converter.StorageDriver{
+		converter.MetadataEncodingBinary: binaryDriver,
+	})
+	require.NoError(t, err)
+	bySize, err := converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{
+		{MinSize: 10_000, Driver: large},
+	})
+	require.NoError(t, err)
+
+	s, err := converter.NewChainStorageDriverSelector(fallback, byEncoding, bySize)
+	require.NoError(t, err)
+	binary, err := converter.GetDefaultDataConverter().ToPayload([]byte(strings.Repeat("b", 50_000)))
+	require.NoError(t, err)
+	require.Equal(t, binaryDriver, selectDriver(t, s, nil, binary))
+	require.Equal(t, large, selectDriver(t, s, nil, makePayload(t, strings.Repeat("l", 50_000))))
+	require.Equal(t, fallback, selectDriver(t, s, nil, makePayload(t, "small")))
+
+	inline, err := converter.NewChainStorageDriverSelector(nil, byEncoding)
+	require.NoError(t, err)
+	require.Nil(t, selectDriver(t, inline, nil, makePayload(t, "small")))
+
+	failing, err := converter.NewChainStorageDriverSelector(fallback,
+		converter.StorageDriverSelectorFunc(func(converter.StorageDriverStoreContext, *commonpb.Payload) (converter.StorageDriver, error) {
+			return nil, errors.New("boom")
+		}), byEncoding)
+	require.NoError(t, err)
+	_, err = failing.SelectDriver(storeCtx(), binary)
+	require.EqualError(t, err, "boom")
+
+	_, err = converter.NewChainStorageDriverSelector(fallback)
+	require.EqualError(t, err, "at least one selector is required")
+	_, err = converter.NewChainStorageDriverSelector(fallback, byEncoding, nil)
+	require.EqualError(t, err, "selector 1 is nil")
+}
+
+func TestChainStorageDriverSelector_ExternalStorage(t *testing.T) {
+	large, fallback := newMemDriver("large"), newMemDriver("fallback")
+	bySize, err := converter.NewSizeTierStorageDriverSelector([]converter.StorageDriverSizeTier{
+		{MinSize: 10_000, Driver: large},
+	})
+	require.NoError(t, err)
+	selector, err := converter.NewChainStorageDriverSelector(fallback, bySize)
+	require.NoError(t, err)
+
+	h, err := converter.NewPayloadHTTPHandler(converter.PayloadHTTPHandlerOptions{
+		ExternalStorage: converter.ExternalStorage{
+			Drivers:              []converter.StorageDriver{large, fallback},
+			DriverSelector:       selector,
+			PayloadSizeThreshold: 1,
+		},
+	})
+	require.NoError(t, err)
+
+	result := getPayloads(t, servePost(t, h, "/encode", createRequest(t,
+		makePayload(t, strings.Repeat("l", 50_000)),
+		makePayload(t, "small"),
+	)))
+	require.Len(t, large.data, 1)
+	require.Len(t, fallback.data, 1)
+	for _, p := range result {
+		require.True(t, extstore.IsStorageReference(p))
+	}
+}
@@ -78,6 +78,21 @@ type (
 		// When registering a struct with activities, skip functions that are not valid activities. If false,
 		// registration panics.
 		SkipInvalidStructFunctions bool
+
+		// MaxConcurrentExecutions limits the number of tasks of this activity type that the worker runs at
+		// once. It is only applied by workers with worker.Options.EnableActivityTypeConcurrencyLimits set,
+		// others log a warning when they start. When registering a struct, the limit applies to each of
+		// its activities separately. The limit in worker.Options.MaxConcurrentActivityTypeExecutionSize
+		// takes precedence if both are set. The zero value means no limit beyond the worker's.
+		//
+		// Limits cost throughput of the whole task queue. Since the worker only learns the type of a task
+		// once it is polled, it stops polling tasks of every type while the next task could exceed a
+		// limit, and the rest of the backlog is dispatched to other workers. Slots reserved for polls in
+		// flight count against the limit, so the worker never has more polls in flight than it. Register
+		// limited activities on a worker of a dedicated task queue.
+		//
+		// NOTE: Experimental
+		MaxConcurrentExecutions int
 	}
 
 	// ActivityOptions stores all activity-specific parameters that will be stored inside of a context.
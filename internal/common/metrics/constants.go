@@ -37,11 +37,16 @@ const (
 
 	CorruptedSignalsCounter = TemporalMetricsPrefix + "corrupted_signals"
 
-	WorkerStartCounter       = TemporalMetricsPrefix + "worker_start"
-	WorkerTaskSlotsAvailable = TemporalMetricsPrefix + "worker_task_slots_available"
-	WorkerTaskSlotsUsed      = TemporalMetricsPrefix + "worker_task_slots_used"
-	PollerStartCounter       = TemporalMetricsPrefix + "poller_start"
-	NumPoller                = TemporalMetricsPrefix + "num_pollers"
+	WorkerStartCounter               = TemporalMetricsPrefix + "worker_start"
+	WorkerTaskSlotsAvailable         = TemporalMetricsPrefix + "worker_task_slots_available"
+	WorkerTaskSlotsUsed              = TemporalMetricsPrefix + "worker_task_slots_used"
+	WorkerTaskSlotsAtMaxDuration     = TemporalMetricsPrefix + "worker_task_slots_at_max_duration"
+	WorkerTaskSlotReservationLatency = TemporalMetricsPrefix + "worker_task_slot_reservation_latency"
+	WorkerTaskSlotReservationDenied  = TemporalMetricsPrefix + "worker_task_slot_reservation_denied"
+	SlotPoolSlotsUsed                = TemporalMetricsPrefix + "slot_pool_slots_used"
+	SlotPoolSlotsIssued              = TemporalMetricsPrefix + "slot_pool_slots_issued"
+	PollerStartCounter               = TemporalMetricsPrefix + "poller_start"
+	NumPoller                        = TemporalMetricsPrefix + "num_pollers"
 
 	TemporalRequest                      = TemporalMetricsPrefix + "request"
 	TemporalRequestFailure               = TemporalRequest + "_failure"
@@ -80,6 +85,9 @@ const (
 	TaskQueueTagName        = "task_queue"
 	OperationTagName        = "operation"
 	CauseTagName            = "cause"
+	DenialReasonTagName     = "denial_reason"
+	WaitReasonTagName       = "wait_reason"
+	SlotPoolMemberTagName   = "slot_pool_member"
 	RequestFailureCode      = "status_code"
 )
 
@@ -81,6 +81,20 @@ func WorkerTags(workerType string) map[string]string {
 	}
 }
 
+// SlotReservationDeniedTags returns a set of tags for a denied slot reservation.
+func SlotReservationDeniedTags(reason string) map[string]string {
+	return map[string]string{
+		DenialReasonTagName: reason,
+	}
+}
+
+// SlotReservationWaitTags returns a set of tags for the wait of a slot reservation.
+func SlotReservationWaitTags(reason string) map[string]string {
+	return map[string]string{
+		WaitReasonTagName: reason,
+	}
+}
+
 // PollerTags returns a set of tags for pollers.
 func PollerTags(pollerType string) map[string]string {
 	return map[string]string{
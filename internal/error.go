@@ -186,6 +186,8 @@ type (
 	workflowPanicError struct {
 		value      interface{}
 		stackTrace string
+		// potentialDeadlock is set when the panic was raised by the deadlock detector
+		potentialDeadlock bool
 	}
 
 	// ContinueAsNewError contains information about how to continue the workflow as new.
@@ -129,6 +129,42 @@ type StorageDriver interface {
 	Retrieve(ctx StorageDriverRetrieveContext, claims []StorageDriverClaim) ([]*commonpb.Payload, error)
 }
 
+// StorageDriverDeleteContext carries context passed to
+// StorageDriverDeleter.Delete operations.
+//
+// NOTE: Experimental
+type StorageDriverDeleteContext struct {
+	// Context is the context of the operation that triggered the driver call.
+	// Drivers should use it to respect cancellation and to propagate deadlines
+	// and trace information to downstream calls (e.g. cloud storage SDKs).
+	Context context.Context
+}
+
+// StorageDriverDeleter is an optional interface that a StorageDriver may
+// implement to support removing previously stored payloads. It is never called
+// by the SDK on the workflow or activity path; it exists so that operators can
+// purge payloads belonging to executions whose retention period has expired.
+// See [DeleteExternalStorageClaims].
+//
+// NOTE: Experimental
+type StorageDriverDeleter interface {
+	// Delete removes the payloads identified by the given claims. Deleting a
+	// payload that no longer exists must not be treated as an error, so that
+	// purges can be safely retried. Delete must not modify the input claims.
+	Delete(ctx StorageDriverDeleteContext, claims []StorageDriverClaim) error
+}
+
+// ExternalStorageClaim pairs a StorageDriverClaim with the name of the driver
+// that issued it, as recorded in a storage reference payload.
+//
+// NOTE: Experimental
+type ExternalStorageClaim struct {
+	// DriverName is the name of the StorageDriver that stored the payload.
+	DriverName string
+	// Claim is the claim returned by the driver when the payload was stored.
+	Claim StorageDriverClaim
+}
+
 // StorageDriverSelector chooses which StorageDriver should store a given
 // payload, or returns nil to leave the payload inline (not stored externally).
 // Use this when different payloads should be routed to different backends. For
@@ -1243,3 +1243,171 @@ func (c *testCallback) PayloadBatchCompleted(count int, size int64, duration tim
 	c.size = size
 	c.duration = duration
 }
+
+// ---------------------------------------------------------------------------
+// Claim collection and deletion
+// ---------------------------------------------------------------------------
+
+type deletingTestDriver struct {
+	*testStorageDriver
+	deleteErr error
+}
+
+func (d *deletingTestDriver) Delete(_ StorageDriverDeleteContext, claims []StorageDriverClaim) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.deleteErr != nil {
+		return d.deleteErr
+	}
+	for _, c := range claims {
+		delete(d.data, c.ClaimData["key"])
+	}
+	return nil
+}
+
+func storeForTest(t *testing.T, driver StorageDriver, payloads ...*commonpb.Payload) []*commonpb.Payload {
+	t.Helper()
+	params, err := ExternalStorageToParams(ExternalStorage{
+		Drivers:              []StorageDriver{driver},
+		PayloadSizeThreshold: 1,
+	})
+	require.NoError(t, err)
+	refs, err := visitPayloads(context.Background(), NewExternalStorageVisitor(params), payloads)
+	require.NoError(t, err)
+	return refs
+}
+
+func TestCollectExternalStorageClaims(t *testing.T) {
+	driver := newTestDriver("d")
+	refs := storeForTest(t, driver, makePayload(t, "a"), makePayload(t, "b"))
+	inline := makePayload(t, "inline")
+
+	// The first reference appears twice and must only be reported once.
+	msg := &commonpb.Payloads{Payloads: []*commonpb.Payload{refs[0], inline, refs[1], refs[0]}}
+	claims, err := CollectExternalStorageClaims(context.Background(), msg)
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+	for i, c := range claims {
+		ref, err := payloadToStorageReference(refs[i])
+		require.NoError(t, err)
+		require.Equal(t, "d", c.DriverName)
+		require.Equal(t, ref.ClaimData, c.Claim.ClaimData)
+	}
+	// The message itself is left untouched.
+	require.True(t, proto.Equal(refs[0], msg.Payloads[0]))
+}
+
+func TestCollectExternalStorageClaims_NoReferences(t *testing.T) {
+	msg := &commonpb.Payloads{Payloads: []*commonpb.Payload{makePayload(t, "inline")}}
+	claims, err := CollectExternalStorageClaims(context.Background(), msg)
+	require.NoError(t, err)
+	require.Empty(t, claims)
+}
+
+func TestClaimCollector_AcrossMessages(t *testing.T) {
+	driver := newTestDriver("d")
+	refs := storeForTest(t, driver, makePayload(t, "a"), makePayload(t, "b"))
+
+	c := NewClaimCollector()
+	require.NoError(t, c.Collect(context.Background(), &commonpb.Payloads{Payloads: refs[:1]}))
+	require.NoError(t, c.Collect(context.Background(), &commonpb.Payloads{Payloads: refs}))
+	require.Len(t, c.Claims(), 2)
+}
+
+func TestDeleteExternalStorageClaims(t *testing.T) {
+	d1 := &deletingTestDriver{testStorageDriver: newTestDriver("d1")}
+	d2 := &deletingTestDriver{testStorageDriver: newTestDriver("d2")}
+	refs := append(storeForTest(t, d1, makePayload(t, "a")), storeForTest(t, d2, makePayload(t, "b"))...)
+
+	claims, err := CollectExternalStorageClaims(context.Background(), &commonpb.Payloads{Payloads: refs})
+	require.NoError(t, err)
+	require.NoError(t, DeleteExternalStorageClaims(context.Background(), []StorageDriver{d1, d2}, claims))
+	require.Empty(t, d1.data)
+	require.Empty(t, d2.data)
+}
+
+func TestDeleteExternalStorageClaims_UnknownDriver(t *testing.T) {
+	err := DeleteExternalStorageClaims(context.Background(), nil, []ExternalStorageClaim{{DriverName: "missing"}})
+	require.EqualError(t, err, `no storage driver registered with name "missing"`)
+}
+
+func TestDeleteExternalStorageClaims_Unsupported(t *testing.T) {
+	driver := newTestDriver("d")
+	refs := storeForTest(t, driver, makePayload(t, "a"))
+	claims, err := CollectExternalStorageClaims(context.Background(), &commonpb.Payloads{Payloads: refs})
+	require.NoError(t, err)
+
+	err = DeleteExternalStorageClaims(context.Background(), []StorageDriver{driver}, claims)
+	require.ErrorIs(t, err, errors.ErrUnsupported)
+	require.Len(t, driver.data, 1)
+}
+
+func TestDeleteExternalStorageClaims_JoinsDriverErrors(t *testing.T) {
+	err1 := errors.New("first")
+	err2 := errors.New("second")
+	d1 := &deletingTestDriver{testStorageDriver: newTestDriver("d1"), deleteErr: err1}
+	d2 := &deletingTestDriver{testStorageDriver: newTestDriver("d2"), deleteErr: err2}
+	claims := []ExternalStorageClaim{{DriverName: "d1"}, {DriverName: "d2"}}
+
+	err := DeleteExternalStorageClaims(context.Background(), []StorageDriver{d1, d2}, claims)
+	require.ErrorIs(t, err, err1)
+	require.ErrorIs(t, err, err2)
+}
+
+// ---------------------------------------------------------------------------
+// StorageChunkManifest
+// ---------------------------------------------------------------------------
+
+func TestStorageChunkManifest(t *testing.T) {
+	data := []byte("0123456789abcdefghij") // 20 bytes
+	m, err := NewStorageChunkManifest(data, 8)
+	require.NoError(t, err)
+	require.Equal(t, 3, m.ChunkCount())
+	require.Len(t, m.Hashes, 3)
+
+	off, n := m.ChunkRange(2)
+	require.Equal(t, int64(16), off)
+	require.Equal(t, int64(4), n)
+
+	for i := 0; i < m.ChunkCount(); i++ {
+		off, n := m.ChunkRange(i)
+		require.NoError(t, m.VerifyChunk(i, data[off:off+n]))
+	}
+	require.ErrorContains(t, m.VerifyChunk(0, []byte("XXXXXXXX")), "chunk 0 integrity check failed")
+	require.ErrorContains(t, m.VerifyChunk(2, []byte("ghi")), "chunk 2 size mismatch")
+	require.ErrorContains(t, m.VerifyChunk(3, nil), "out of range")
+}
+
+func TestStorageChunkManifest_ClaimRoundTrip(t *testing.T) {
+	m, err := NewStorageChunkManifest([]byte("0123456789"), 4)
+	require.NoError(t, err)
+	encoded, err := m.Marshal()
+	require.NoError(t, err)
+	claim := StorageDriverClaim{ClaimData: map[string]string{"key": "k"}}
+	m.AddToClaim(claim.ClaimData, "k.chunks", encoded)
+	// Chunk hashes are kept out of the claim
+	require.Len(t, claim.ClaimData, 3)
+
+	key, ok := StorageChunkManifestKey(claim)
+	require.True(t, ok)
+	require.Equal(t, "k.chunks", key)
+	got, err := DecodeStorageChunkManifest(claim, encoded)
+	require.NoError(t, err)
+	require.Equal(t, m, got)
+
+	_, ok = StorageChunkManifestKey(StorageDriverClaim{ClaimData: map[string]string{"key": "k"}})
+	require.False(t, ok)
+
+	_, err = DecodeStorageChunkManifest(claim, []byte(`{"size":10,"chunk_size":4,"hashes":["abc"]}`))
+	require.ErrorContains(t, err, "chunk manifest integrity check failed")
+
+	tampered := []byte(`{"size":10,"chunk_size":4,"hashes":["abc"]}`)
+	claim.ClaimData["chunk_manifest_hash"] = sha256Hex(tampered)
+	_, err = DecodeStorageChunkManifest(claim, tampered)
+	require.EqualError(t, err, "chunk manifest has 1 chunk hashes for 3 chunks")
+}
+
+func TestNewStorageChunkManifest_InvalidChunkSize(t *testing.T) {
+	_, err := NewStorageChunkManifest([]byte("x"), 0)
+	require.EqualError(t, err, "chunk size must be positive, got 0")
+}
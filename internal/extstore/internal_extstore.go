@@ -3,6 +3,7 @@ package extstore
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"time"
 
@@ -442,3 +443,128 @@ func callDriverRetrieve(d StorageDriver, ctx StorageDriverRetrieveContext, claim
 	}()
 	return d.Retrieve(ctx, claims)
 }
+
+func callDriverDelete(d StorageDriverDeleter, ctx StorageDriverDeleteContext, claims []StorageDriverClaim) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked: %v", r)
+		}
+	}()
+	return d.Delete(ctx, claims)
+}
+
+// ClaimCollector accumulates the external storage claims referenced by one or
+// more proto messages, such as the events of a workflow history. Claims are
+// de-duplicated and returned in the order they were first encountered.
+type ClaimCollector struct {
+	seen   map[string]struct{}
+	claims []ExternalStorageClaim
+}
+
+// NewClaimCollector returns an empty ClaimCollector.
+func NewClaimCollector() *ClaimCollector {
+	return &ClaimCollector{seen: map[string]struct{}{}}
+}
+
+// Collect walks msg with the payload visitor and records the claim of every
+// storage reference it contains. msg is not modified.
+func (c *ClaimCollector) Collect(ctx context.Context, msg proto.Message) error {
+	return proxy.VisitPayloads(ctx, msg, proxy.VisitPayloadsOptions{
+		Visitor: func(_ *proxy.VisitPayloadsContext, payloads []*commonpb.Payload) ([]*commonpb.Payload, error) {
+			for _, p := range payloads {
+				if !IsStorageReference(p) {
+					continue
+				}
+				ref, err := payloadToStorageReference(p)
+				if err != nil {
+					return nil, err
+				}
+				if err := c.add(ExternalStorageClaim{
+					DriverName: ref.DriverName,
+					Claim:      StorageDriverClaim{ClaimData: ref.ClaimData},
+				}); err != nil {
+					return nil, err
+				}
+			}
+			return payloads, nil
+		},
+		SkipSearchAttributes: true,
+	})
+}
+
+// Claims returns the claims collected so far.
+func (c *ClaimCollector) Claims() []ExternalStorageClaim {
+	return c.claims
+}
+
+func (c *ClaimCollector) add(claim ExternalStorageClaim) error {
+	// json.Marshal sorts map keys, so equal claims produce equal keys.
+	key, err := json.Marshal(claim)
+	if err != nil {
+		return fmt.Errorf("failed to marshal storage claim: %w", err)
+	}
+	if _, ok := c.seen[string(key)]; ok {
+		return nil
+	}
+	c.seen[string(key)] = struct{}{}
+	c.claims = append(c.claims, claim)
+	return nil
+}
+
+// CollectExternalStorageClaims returns the external storage claims referenced
+// by the payloads in msg, de-duplicated and in the order they were first
+// encountered.
+func CollectExternalStorageClaims(ctx context.Context, msg proto.Message) ([]ExternalStorageClaim, error) {
+	c := NewClaimCollector()
+	if err := c.Collect(ctx, msg); err != nil {
+		return nil, err
+	}
+	return c.Claims(), nil
+}
+
+// DeleteExternalStorageClaims deletes the payloads identified by claims using
+// the matching driver from drivers. Claims are grouped by driver name and each
+// group is passed to the driver's Delete method in a single call. Every driver
+// referenced by claims must be present in drivers and must implement
+// [StorageDriverDeleter]; otherwise no payloads are deleted and an error is
+// returned. Failures from individual drivers do not prevent the remaining
+// drivers from being called; all failures are returned joined together.
+func DeleteExternalStorageClaims(ctx context.Context, drivers []StorageDriver, claims []ExternalStorageClaim) error {
+	driverMap := make(map[string]StorageDriver, len(drivers))
+	for _, d := range drivers {
+		driverMap[d.Name()] = d
+	}
+
+	type driverBatch struct {
+		deleter StorageDriverDeleter
+		claims  []StorageDriverClaim
+	}
+	var driverOrder []string
+	driverBatches := map[string]*driverBatch{}
+	for _, c := range claims {
+		batch, exists := driverBatches[c.DriverName]
+		if !exists {
+			driver, ok := driverMap[c.DriverName]
+			if !ok {
+				return fmt.Errorf("no storage driver registered with name %q", c.DriverName)
+			}
+			deleter, ok := driver.(StorageDriverDeleter)
+			if !ok {
+				return fmt.Errorf("storage driver %q does not support deletion: %w", c.DriverName, errors.ErrUnsupported)
+			}
+			batch = &driverBatch{deleter: deleter}
+			driverBatches[c.DriverName] = batch
+			driverOrder = append(driverOrder, c.DriverName)
+		}
+		batch.claims = append(batch.claims, c.Claim)
+	}
+
+	driverCtx := StorageDriverDeleteContext{Context: ctx}
+	var errs []error
+	for _, name := range driverOrder {
+		if err := callDriverDelete(driverBatches[name].deleter, driverCtx, driverBatches[name].claims); err != nil {
+			errs = append(errs, fmt.Errorf("storage driver %q delete failed: %w", name, err))
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,325 @@
+package extstore
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+
+	commonpb "go.temporal.io/api/common/v1"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	objectStoreHashAlgorithm = "sha256"
+	objectStoreNameVersion   = "v0"
+
+	claimKeyHashAlgorithm = "hash_algorithm"
+	claimKeyHashValue     = "hash_value"
+)
+
+// ObjectStore is the set of object store operations that ObjectStoreDriver
+// is built on. Object store drivers, such as the S3, GCS and Azure Blob Storage
+// drivers in contrib, adapt their client to it.
+type ObjectStore interface {
+	// ObjectExists reports whether an object exists in container under name.
+	ObjectExists(ctx context.Context, container, name string) (bool, error)
+
+	// PutObject uploads data to container under name. It is only called for
+	// objects that do not exist yet.
+	PutObject(ctx context.Context, container, name string, data []byte) error
+
+	// GetObject downloads the object stored in container under name for the
+	// given claim. The driver verifies the result against the hash recorded
+	// in the claim.
+	GetObject(ctx context.Context, container, name string, claim StorageDriverClaim) ([]byte, error)
+
+	// Describe returns diagnostic metadata that is appended to error messages.
+	Describe() map[string]string
+}
+
+// ObjectStoreDeleter is an optional interface that an ObjectStore implements
+// to support StorageDriverDeleter.
+type ObjectStoreDeleter interface {
+	// DeleteObject removes the objects stored for the given claim in container
+	// under name. It returns nil if they do not exist.
+	DeleteObject(ctx context.Context, container, name string, claim StorageDriverClaim) error
+}
+
+// ObjectStoreClaimWriter is an optional interface that an ObjectStore
+// implements to record more data in the claim of a stored object.
+type ObjectStoreClaimWriter interface {
+	// AddToClaim is called once data is stored in container under name, and
+	// may add entries to claimData.
+	AddToClaim(ctx context.Context, container, name string, data []byte, claimData map[string]string) error
+}
+
+// ObjectStoreDriverOptions are options for NewObjectStoreDriver.
+type ObjectStoreDriverOptions struct {
+	// Store is the object store that payloads are kept in. Required.
+	Store ObjectStore
+
+	// Container resolves the container, such as the bucket, of each payload.
+	// Required.
+	Container func(ctx StorageDriverStoreContext, payload *commonpb.Payload) string
+
+	// DriverName is returned by ObjectStoreDriver.Name.
+	DriverName string
+
+	// DriverType is returned by ObjectStoreDriver.Type.
+	DriverType string
+
+	// MaxPayloadSize is the maximum serialized payload size in bytes that
+	// the driver accepts. Must be positive.
+	MaxPayloadSize int
+
+	// ContainerClaimKey and NameClaimKey are the claim data keys under which
+	// the container and the name of the object are recorded, e.g. "bucket" and
+	// "key". They also label the container and name in error messages.
+	ContainerClaimKey string
+	NameClaimKey      string
+}
+
+// ObjectStoreDriver is a StorageDriver that stores each payload as an object
+// in an ObjectStore, named after the SHA-256 hash of the serialized payload
+// and the target it belongs to. The hash is recorded in the claim and checked
+// when the payload is retrieved.
+type ObjectStoreDriver struct {
+	opts ObjectStoreDriverOptions
+}
+
+// Compile-time checks that ObjectStoreDriver implements StorageDriver and
+// StorageDriverDeleter.
+var (
+	_ StorageDriver        = (*ObjectStoreDriver)(nil)
+	_ StorageDriverDeleter = (*ObjectStoreDriver)(nil)
+)
+
+// NewObjectStoreDriver creates an ObjectStoreDriver. Callers validate the
+// options they expose to users before calling it.
+func NewObjectStoreDriver(opts ObjectStoreDriverOptions) *ObjectStoreDriver {
+	return &ObjectStoreDriver{opts: opts}
+}
+
+// Name returns the unique identifier for this driver instance.
+func (d *ObjectStoreDriver) Name() string { return d.opts.DriverName }
+
+// Type returns the driver implementation type.
+func (d *ObjectStoreDriver) Type() string { return d.opts.DriverType }
+
+// MaxPayloadSize returns the maximum serialized payload size in bytes that the
+// driver accepts.
+func (d *ObjectStoreDriver) MaxPayloadSize() int { return d.opts.MaxPayloadSize }
+
+type preparedObject struct {
+	data      []byte
+	hexDigest string
+	container string
+}
+
+// Store serializes each payload, validates sizes, then uploads concurrently if
+// not already present, and returns a claim per payload.
+//
+// Two phases are used to avoid partial uploads when validation fails:
+//  1. Marshal and validate all payloads sequentially.
+//  2. Upload concurrently — only reached if all payloads passed validation.
+func (d *ObjectStoreDriver) Store(
+	ctx StorageDriverStoreContext,
+	payloads []*commonpb.Payload,
+) ([]StorageDriverClaim, error) {
+	prepared := make([]preparedObject, len(payloads))
+	for i, p := range payloads {
+		data, err := proto.Marshal(p)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal payload: %w", err)
+		}
+		if len(data) > d.opts.MaxPayloadSize {
+			return nil, fmt.Errorf(
+				"payload size %d exceeds maximum %d",
+				len(data), d.opts.MaxPayloadSize,
+			)
+		}
+		prepared[i] = preparedObject{
+			data:      data,
+			hexDigest: sha256Hex(data),
+			container: d.opts.Container(ctx, p),
+		}
+	}
+
+	claims := make([]StorageDriverClaim, len(payloads))
+	g, gctx := errgroup.WithContext(ctx.Context)
+	for i, po := range prepared {
+		g.Go(func() error {
+			name := ObjectStoreName(ctx.Target, po.hexDigest)
+			exists, err := d.opts.Store.ObjectExists(gctx, po.container, name)
+			if err != nil {
+				return fmt.Errorf("existence check failed [%s%s]: %w", d.describeObject(po.container, name), d.describeStore(), err)
+			}
+			if !exists {
+				if err := d.opts.Store.PutObject(gctx, po.container, name, po.data); err != nil {
+					return fmt.Errorf("upload failed [%s%s]: %w", d.describeObject(po.container, name), d.describeStore(), err)
+				}
+			}
+			claimData := map[string]string{
+				d.opts.ContainerClaimKey: po.container,
+				d.opts.NameClaimKey:      name,
+				claimKeyHashAlgorithm:    objectStoreHashAlgorithm,
+				claimKeyHashValue:        po.hexDigest,
+			}
+			if w, ok := d.opts.Store.(ObjectStoreClaimWriter); ok {
+				if err := w.AddToClaim(gctx, po.container, name, po.data, claimData); err != nil {
+					return err
+				}
+			}
+			claims[i] = StorageDriverClaim{ClaimData: claimData}
+			return nil
+		})
+	}
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return claims, nil
+}
+
+// Retrieve downloads payloads using the given claims, verifies their integrity
+// via SHA-256, and returns the deserialized payloads. Claims are processed
+// concurrently.
+func (d *ObjectStoreDriver) Retrieve(
+	ctx StorageDriverRetrieveContext,
+	claims []StorageDriverClaim,
+) ([]*commonpb.Payload, error) {
+	payloads := make([]*commonpb.Payload, len(claims))
+	g, gctx := errgroup.WithContext(ctx.Context)
+
+	for i, c := range claims {
+		g.Go(func() error {
+			container, name, err := d.objectFromClaim(c)
+			if err != nil {
+				return err
+			}
+
+			algo, ok := c.ClaimData[claimKeyHashAlgorithm]
+			if !ok {
+				return fmt.Errorf("claim missing field %q", claimKeyHashAlgorithm)
+			}
+			if algo != objectStoreHashAlgorithm {
+				return fmt.Errorf("unsupported hash algorithm %q", algo)
+			}
+			expectedHash, ok := c.ClaimData[claimKeyHashValue]
+			if !ok {
+				return fmt.Errorf("claim missing field %q", claimKeyHashValue)
+			}
+
+			data, err := d.opts.Store.GetObject(gctx, container, name, c)
+			if err != nil {
+				return fmt.Errorf("download failed [%s%s]: %w", d.describeObject(container, name), d.describeStore(), err)
+			}
+			if actualHash := sha256Hex(data); actualHash != expectedHash {
+				return fmt.Errorf(
+					"integrity check failed [%s]: expected hash %s, got %s",
+					d.describeObject(container, name), expectedHash, actualHash,
+				)
+			}
+
+			var payload commonpb.Payload
+			if err := proto.Unmarshal(data, &payload); err != nil {
+				return fmt.Errorf("failed to unmarshal payload [%s]: %w", d.describeObject(container, name), err)
+			}
+			payloads[i] = &payload
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		return nil, err
+	}
+	return payloads, nil
+}
+
+// Delete removes the objects identified by the given claims. Claims are
+// processed concurrently. Objects that no longer exist are ignored. Returns an
+// error wrapping errors.ErrUnsupported if the store does not implement
+// ObjectStoreDeleter.
+func (d *ObjectStoreDriver) Delete(
+	ctx StorageDriverDeleteContext,
+	claims []StorageDriverClaim,
+) error {
+	deleter, ok := d.opts.Store.(ObjectStoreDeleter)
+	if !ok {
+		return fmt.Errorf("client does not support deletion: %w", errors.ErrUnsupported)
+	}
+	g, gctx := errgroup.WithContext(ctx.Context)
+	for _, c := range claims {
+		g.Go(func() error {
+			container, name, err := d.objectFromClaim(c)
+			if err != nil {
+				return err
+			}
+			if err := deleter.DeleteObject(gctx, container, name, c); err != nil {
+				return fmt.Errorf("delete failed [%s%s]: %w", d.describeObject(container, name), d.describeStore(), err)
+			}
+			return nil
+		})
+	}
+	return g.Wait()
+}
+
+func (d *ObjectStoreDriver) objectFromClaim(c StorageDriverClaim) (container, name string, err error) {
+	container, ok := c.ClaimData[d.opts.ContainerClaimKey]
+	if !ok {
+		return "", "", fmt.Errorf("claim missing field %q", d.opts.ContainerClaimKey)
+	}
+	name, ok = c.ClaimData[d.opts.NameClaimKey]
+	if !ok {
+		return "", "", fmt.Errorf("claim missing field %q", d.opts.NameClaimKey)
+	}
+	return container, name, nil
+}
+
+func (d *ObjectStoreDriver) describeObject(container, name string) string {
+	return d.opts.ContainerClaimKey + "=" + container + ", " + d.opts.NameClaimKey + "=" + name
+}
+
+// describeStore returns ", k=v, k=v" diagnostic info from the store's
+// Describe method, or "" if Describe returns nil/empty.
+func (d *ObjectStoreDriver) describeStore() string {
+	var s string
+	for k, v := range d.opts.Store.Describe() {
+		s += ", " + k + "=" + v
+	}
+	return s
+}
+
+// ObjectStoreName returns the name of the object holding a payload with the
+// given SHA-256 hex digest for target. The name groups objects by namespace
+// and workflow or activity so that they can be managed with prefix-based
+// lifecycle rules.
+func ObjectStoreName(target StorageDriverTargetInfo, hexDigest string) string {
+	digestSegment := "/d/" + objectStoreHashAlgorithm + "/" + hexDigest
+	switch t := target.(type) {
+	case StorageDriverWorkflowInfo:
+		return objectStoreNameVersion +
+			"/ns/" + pathEscape(t.Namespace) +
+			"/wt/" + pathEscape(t.WorkflowType) +
+			"/wi/" + pathEscape(t.WorkflowID) +
+			"/ri/" + pathEscape(t.RunID) +
+			digestSegment
+	case StorageDriverActivityInfo:
+		return objectStoreNameVersion +
+			"/ns/" + pathEscape(t.Namespace) +
+			"/at/" + pathEscape(t.ActivityType) +
+			"/ai/" + pathEscape(t.ActivityID) +
+			"/ri/" + pathEscape(t.RunID) +
+			digestSegment
+	default:
+		return objectStoreNameVersion + digestSegment
+	}
+}
+
+func pathEscape(s string) string {
+	if s == "" {
+		return "null"
+	}
+	return url.PathEscape(s)
+}
@@ -0,0 +1,685 @@
+package extstore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"google.golang.org/protobuf/proto"
+)
+
+const defaultTestMaxPayloadSize = 1024 * 1024
+
+// memStore is an in-memory ObjectStore for unit testing.
+type memStore struct {
+	mu       sync.RWMutex
+	data     map[string][]byte // key: "bucket/name"
+	putCount atomic.Int64
+	describe map[string]string
+}
+
+func newMemStore() *memStore {
+	return &memStore{
+		data:     make(map[string][]byte),
+		describe: map[string]string{"client_project": "test-project"},
+	}
+}
+
+func memKey(bucket, name string) string { return bucket + "/" + name }
+
+func (m *memStore) PutObject(_ context.Context, bucket, name string, data []byte) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.putCount.Add(1)
+	cp := make([]byte, len(data))
+	copy(cp, data)
+	m.data[memKey(bucket, name)] = cp
+	return nil
+}
+
+func (m *memStore) ObjectExists(_ context.Context, bucket, name string) (bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	_, ok := m.data[memKey(bucket, name)]
+	return ok, nil
+}
+
+func (m *memStore) GetObject(_ context.Context, bucket, name string, _ StorageDriverClaim) ([]byte, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	d, ok := m.data[memKey(bucket, name)]
+	if !ok {
+		return nil, fmt.Errorf("not found: %s/%s", bucket, name)
+	}
+	cp := make([]byte, len(d))
+	copy(cp, d)
+	return cp, nil
+}
+
+func (m *memStore) DeleteObject(_ context.Context, bucket, name string, _ StorageDriverClaim) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.data, memKey(bucket, name))
+	return nil
+}
+
+func (m *memStore) Describe() map[string]string { return m.describe }
+
+func testPayload(data string) *commonpb.Payload {
+	return &commonpb.Payload{
+		Metadata: map[string][]byte{"encoding": []byte("binary/plain")},
+		Data:     []byte(data),
+	}
+}
+
+func staticContainer(name string) func(StorageDriverStoreContext, *commonpb.Payload) string {
+	return func(StorageDriverStoreContext, *commonpb.Payload) string { return name }
+}
+
+func newObjectStoreDriver(
+	store ObjectStore,
+	container func(StorageDriverStoreContext, *commonpb.Payload) string,
+	maxPayloadSize int,
+) *ObjectStoreDriver {
+	return NewObjectStoreDriver(ObjectStoreDriverOptions{
+		Store:             store,
+		Container:         container,
+		DriverName:        "test-driver",
+		DriverType:        "test.driver",
+		MaxPayloadSize:    maxPayloadSize,
+		ContainerClaimKey: "bucket",
+		NameClaimKey:      "name",
+	})
+}
+
+func newDriver(t *testing.T, store ObjectStore) *ObjectStoreDriver {
+	t.Helper()
+	return newObjectStoreDriver(store, staticContainer("test-bucket"), defaultTestMaxPayloadSize)
+}
+
+func storeCtx() StorageDriverStoreContext {
+	return StorageDriverStoreContext{Context: context.Background()}
+}
+
+func storeCtxWithTarget(target StorageDriverTargetInfo) StorageDriverStoreContext {
+	return StorageDriverStoreContext{Context: context.Background(), Target: target}
+}
+
+func retrieveCtx() StorageDriverRetrieveContext {
+	return StorageDriverRetrieveContext{Context: context.Background()}
+}
+
+func deleteCtx() StorageDriverDeleteContext {
+	return StorageDriverDeleteContext{Context: context.Background()}
+}
+
+func TestObjectStoreDriver_NameAndType(t *testing.T) {
+	d := newDriver(t, newMemStore())
+	assert.Equal(t, "test-driver", d.Name())
+	assert.Equal(t, "test.driver", d.Type())
+	assert.Equal(t, defaultTestMaxPayloadSize, d.MaxPayloadSize())
+}
+
+// --- Store tests ---
+
+func TestStore_SinglePayload(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	p := testPayload("hello")
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+
+	assert.Equal(t, "test-bucket", claims[0].ClaimData["bucket"])
+	assert.Equal(t, "sha256", claims[0].ClaimData["hash_algorithm"])
+	assert.NotEmpty(t, claims[0].ClaimData["name"])
+	assert.NotEmpty(t, claims[0].ClaimData["hash_value"])
+
+	// Verify name format.
+	data, _ := proto.Marshal(p)
+	h := sha256.Sum256(data)
+	expectedDigest := hex.EncodeToString(h[:])
+	assert.Equal(t, "v0/d/sha256/"+expectedDigest, claims[0].ClaimData["name"])
+	assert.Equal(t, expectedDigest, claims[0].ClaimData["hash_value"])
+}
+
+func TestStore_EmptyPayloads(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{})
+	require.NoError(t, err)
+	assert.Empty(t, claims)
+}
+
+func TestStore_Deduplication(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	p := testPayload("duplicate-me")
+
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), mc.putCount.Load())
+
+	// Store same payload again — should skip the upload.
+	_, err = d.Store(storeCtx(), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), mc.putCount.Load())
+}
+
+func TestStore_MultiplePayloads(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	payloads := []*commonpb.Payload{
+		testPayload("a"),
+		testPayload("b"),
+		testPayload("c"),
+	}
+
+	claims, err := d.Store(storeCtx(), payloads)
+	require.NoError(t, err)
+	require.Len(t, claims, 3)
+
+	// Each should have unique names.
+	names := map[string]bool{}
+	for _, c := range claims {
+		names[c.ClaimData["name"]] = true
+	}
+	assert.Len(t, names, 3)
+}
+
+func TestStore_MaxPayloadSizeExceeded(t *testing.T) {
+	mc := newMemStore()
+	d := newObjectStoreDriver(mc, staticContainer("b"), 10)
+
+	p := testPayload("this payload is definitely larger than 10 bytes when serialized")
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{p})
+	assert.ErrorContains(t, err, "payload size ")
+	assert.ErrorContains(t, err, " exceeds maximum 10")
+}
+
+func TestStore_DynamicBucket(t *testing.T) {
+	mc := newMemStore()
+	d := newObjectStoreDriver(mc, func(_ StorageDriverStoreContext, p *commonpb.Payload) string {
+		if string(p.Data) == "a" {
+			return "bucket-a"
+		}
+		return "bucket-b"
+	}, defaultTestMaxPayloadSize)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{
+		testPayload("a"),
+		testPayload("b"),
+	})
+	require.NoError(t, err)
+	require.Len(t, claims, 2)
+
+	buckets := map[string]bool{}
+	for _, c := range claims {
+		buckets[c.ClaimData["bucket"]] = true
+	}
+	assert.True(t, buckets["bucket-a"])
+	assert.True(t, buckets["bucket-b"])
+}
+
+// errStore wraps a memStore and injects errors.
+type errStore struct {
+	*memStore
+	putErr    error
+	getErr    error
+	existsErr error
+	deleteErr error
+}
+
+func (e *errStore) PutObject(ctx context.Context, bucket, name string, data []byte) error {
+	if e.putErr != nil {
+		return e.putErr
+	}
+	return e.memStore.PutObject(ctx, bucket, name, data)
+}
+
+func (e *errStore) ObjectExists(ctx context.Context, bucket, name string) (bool, error) {
+	if e.existsErr != nil {
+		return false, e.existsErr
+	}
+	return e.memStore.ObjectExists(ctx, bucket, name)
+}
+
+func (e *errStore) GetObject(ctx context.Context, bucket, name string, claim StorageDriverClaim) ([]byte, error) {
+	if e.getErr != nil {
+		return nil, e.getErr
+	}
+	return e.memStore.GetObject(ctx, bucket, name, claim)
+}
+
+func (e *errStore) DeleteObject(ctx context.Context, bucket, name string, claim StorageDriverClaim) error {
+	if e.deleteErr != nil {
+		return e.deleteErr
+	}
+	return e.memStore.DeleteObject(ctx, bucket, name, claim)
+}
+
+func TestStore_PutObjectError(t *testing.T) {
+	ec := &errStore{
+		memStore: newMemStore(),
+		putErr:   errors.New("access denied"),
+	}
+	d := newDriver(t, ec)
+
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	assert.ErrorContains(t, err, "upload failed [bucket=test-bucket, name=")
+	assert.ErrorContains(t, err, ", client_project=test-project]: access denied")
+}
+
+func TestStore_ObjectExistsError(t *testing.T) {
+	ec := &errStore{
+		memStore:  newMemStore(),
+		existsErr: errors.New("network timeout"),
+	}
+	d := newDriver(t, ec)
+
+	_, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	assert.ErrorContains(t, err, "existence check failed [bucket=test-bucket, name=")
+	assert.ErrorContains(t, err, ", client_project=test-project]: network timeout")
+}
+
+// --- Retrieve tests ---
+
+func TestRetrieve_RoundTrip(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	original := testPayload("round-trip data")
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{original})
+	require.NoError(t, err)
+
+	restored, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+
+	assert.True(t, proto.Equal(original, restored[0]),
+		"restored payload should equal original")
+}
+
+func TestRetrieve_MultiplePayloads(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	originals := []*commonpb.Payload{
+		testPayload("x"),
+		testPayload("y"),
+		testPayload("z"),
+	}
+	claims, err := d.Store(storeCtx(), originals)
+	require.NoError(t, err)
+
+	restored, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Len(t, restored, 3)
+	for i, orig := range originals {
+		assert.True(t, proto.Equal(orig, restored[i]))
+	}
+}
+
+func TestRetrieve_HashVerificationFailure(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("legit")})
+	require.NoError(t, err)
+
+	// Tamper with the stored data.
+	for k := range mc.data {
+		mc.data[k] = []byte("corrupted")
+	}
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "integrity check failed [bucket=test-bucket, name=")
+}
+
+func TestRetrieve_UnsupportedHashAlgorithm(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("data")})
+	require.NoError(t, err)
+
+	// Override hash algorithm.
+	claims[0].ClaimData["hash_algorithm"] = "md5"
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, `unsupported hash algorithm "md5"`)
+}
+
+func TestRetrieve_MissingObject(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims := []StorageDriverClaim{{
+		ClaimData: map[string]string{
+			"bucket":         "test-bucket",
+			"name":           "v0/d/sha256/nonexistent",
+			"hash_algorithm": "sha256",
+			"hash_value":     "abc",
+		},
+	}}
+
+	_, err := d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, "download failed [bucket=test-bucket, name=v0/d/sha256/nonexistent, client_project=test-project]: not found: test-bucket/v0/d/sha256/nonexistent")
+}
+
+func TestRetrieve_ClaimMissingBucket(t *testing.T) {
+	d := newDriver(t, newMemStore())
+	claims := []StorageDriverClaim{{
+		ClaimData: map[string]string{"name": "v0/d/sha256/abc"},
+	}}
+	_, err := d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, `claim missing field "bucket"`)
+}
+
+func TestRetrieve_ClaimMissingName(t *testing.T) {
+	d := newDriver(t, newMemStore())
+	claims := []StorageDriverClaim{{
+		ClaimData: map[string]string{"bucket": "test-bucket"},
+	}}
+	_, err := d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, `claim missing field "name"`)
+}
+
+func TestRetrieve_NilClaimData(t *testing.T) {
+	d := newDriver(t, newMemStore())
+	_, err := d.Retrieve(retrieveCtx(), []StorageDriverClaim{{}})
+	assert.EqualError(t, err, `claim missing field "bucket"`)
+}
+
+func TestRetrieve_GetObjectError(t *testing.T) {
+	mc := newMemStore()
+	ec := &errStore{memStore: mc, getErr: errors.New("throttled")}
+	d := newDriver(t, ec)
+
+	// Store with real client, then retrieve with error client.
+	realDriver := newDriver(t, mc)
+	claims, err := realDriver.Store(storeCtx(), []*commonpb.Payload{testPayload("data")})
+	require.NoError(t, err)
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "download failed [bucket=test-bucket, name=")
+	assert.ErrorContains(t, err, ", client_project=test-project]: throttled")
+}
+
+func TestRetrieve_ClaimMissingHashAlgorithm(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
+	delete(claims[0].ClaimData, claimKeyHashAlgorithm)
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, `claim missing field "hash_algorithm"`)
+}
+
+func TestRetrieve_ClaimMissingHashValue(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
+	delete(claims[0].ClaimData, claimKeyHashValue)
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.EqualError(t, err, `claim missing field "hash_value"`)
+}
+
+// --- Name generation tests ---
+
+func TestObjectStoreName_NoTarget(t *testing.T) {
+	assert.Equal(t, "v0/d/sha256/abc123", ObjectStoreName(nil, "abc123"))
+}
+
+func TestObjectStoreName_WorkflowInfo(t *testing.T) {
+	target := StorageDriverWorkflowInfo{
+		Namespace:    "default",
+		WorkflowType: "MyWorkflow",
+		WorkflowID:   "wf-123",
+		RunID:        "run-456",
+	}
+	assert.Equal(t,
+		"v0/ns/default/wt/MyWorkflow/wi/wf-123/ri/run-456/d/sha256/abc123",
+		ObjectStoreName(target, "abc123"),
+	)
+}
+
+func TestObjectStoreName_ActivityInfo(t *testing.T) {
+	target := StorageDriverActivityInfo{
+		Namespace:    "default",
+		ActivityType: "MyActivity",
+		ActivityID:   "act-789",
+		RunID:        "run-abc",
+	}
+	assert.Equal(t,
+		"v0/ns/default/at/MyActivity/ai/act-789/ri/run-abc/d/sha256/abc123",
+		ObjectStoreName(target, "abc123"),
+	)
+}
+
+func TestObjectStoreName_WorkflowInfo_EmptyFields(t *testing.T) {
+	// Empty strings should fall back to "null" in each segment.
+	target := StorageDriverWorkflowInfo{
+		Namespace: "my-ns",
+		// WorkflowType, WorkflowID, RunID intentionally empty
+	}
+	assert.Equal(t,
+		"v0/ns/my-ns/wt/null/wi/null/ri/null/d/sha256/abc123",
+		ObjectStoreName(target, "abc123"),
+	)
+}
+
+func TestObjectStoreName_ActivityInfo_EmptyFields(t *testing.T) {
+	target := StorageDriverActivityInfo{
+		Namespace: "my-ns",
+		// ActivityType, ActivityID, RunID intentionally empty
+	}
+	assert.Equal(t,
+		"v0/ns/my-ns/at/null/ai/null/ri/null/d/sha256/abc123",
+		ObjectStoreName(target, "abc123"),
+	)
+}
+
+func TestObjectStoreName_WorkflowInfo_SpecialChars(t *testing.T) {
+	// Slashes, spaces, and other special characters must be percent-encoded.
+	target := StorageDriverWorkflowInfo{
+		Namespace:    "my namespace",
+		WorkflowType: "my/workflow",
+		WorkflowID:   "wf id+1",
+		RunID:        "run=abc",
+	}
+	name := ObjectStoreName(target, "abc123")
+	assert.Equal(t,
+		"v0/ns/my%20namespace/wt/my%2Fworkflow/wi/wf%20id+1/ri/run=abc/d/sha256/abc123",
+		name,
+	)
+}
+
+// --- Store with target context tests ---
+
+func TestStore_WithWorkflowTarget(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	p := testPayload("workflow payload")
+
+	target := StorageDriverWorkflowInfo{
+		Namespace:    "default",
+		WorkflowType: "MyWorkflow",
+		WorkflowID:   "wf-123",
+		RunID:        "run-456",
+	}
+	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+
+	name := claims[0].ClaimData["name"]
+	assert.Contains(t, name, "v0/ns/default/wt/MyWorkflow/wi/wf-123/ri/run-456/d/sha256/")
+}
+
+func TestStore_WithActivityTarget(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	p := testPayload("activity payload")
+
+	target := StorageDriverActivityInfo{
+		Namespace:    "default",
+		ActivityType: "MyActivity",
+		ActivityID:   "act-789",
+		RunID:        "run-abc",
+	}
+	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{p})
+	require.NoError(t, err)
+	require.Len(t, claims, 1)
+
+	name := claims[0].ClaimData["name"]
+	assert.Contains(t, name, "v0/ns/default/at/MyActivity/ai/act-789/ri/run-abc/d/sha256/")
+}
+
+func TestStore_RoundTrip_WithWorkflowTarget(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	original := testPayload("round-trip with target")
+
+	target := StorageDriverWorkflowInfo{
+		Namespace:    "default",
+		WorkflowType: "MyWorkflow",
+		WorkflowID:   "wf-123",
+		RunID:        "run-456",
+	}
+	claims, err := d.Store(storeCtxWithTarget(target), []*commonpb.Payload{original})
+	require.NoError(t, err)
+
+	restored, err := d.Retrieve(retrieveCtx(), claims)
+	require.NoError(t, err)
+	require.Len(t, restored, 1)
+	assert.True(t, proto.Equal(original, restored[0]))
+}
+
+func TestStore_DifferentTargets_SamePayload_DifferentNames(t *testing.T) {
+	// The same payload stored under different targets produces different names.
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	p := testPayload("shared payload")
+
+	wfTarget := StorageDriverWorkflowInfo{Namespace: "ns", WorkflowID: "wf-1", RunID: "run-1"}
+	actTarget := StorageDriverActivityInfo{Namespace: "ns", ActivityID: "act-1", RunID: "run-1"}
+
+	wfClaims, err := d.Store(storeCtxWithTarget(wfTarget), []*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	actClaims, err := d.Store(storeCtxWithTarget(actTarget), []*commonpb.Payload{p})
+	require.NoError(t, err)
+
+	assert.NotEqual(t, wfClaims[0].ClaimData["name"], actClaims[0].ClaimData["name"])
+}
+
+// --- Delete tests ---
+
+func TestDelete_RemovesObjects(t *testing.T) {
+	mc := newMemStore()
+	d := newDriver(t, mc)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a"), testPayload("b")})
+	require.NoError(t, err)
+	require.Len(t, mc.data, 2)
+
+	deleter := StorageDriverDeleter(d)
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+	assert.Empty(t, mc.data)
+
+	_, err = d.Retrieve(retrieveCtx(), claims)
+	assert.ErrorContains(t, err, "download failed")
+}
+
+func TestDelete_MissingObjectIsNotAnError(t *testing.T) {
+	d := newDriver(t, newMemStore())
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("a")})
+	require.NoError(t, err)
+
+	deleter := d
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+	require.NoError(t, deleter.Delete(deleteCtx(), claims))
+}
+
+func TestDelete_ClaimMissingFields(t *testing.T) {
+	deleter := newDriver(t, newMemStore())
+
+	err := deleter.Delete(deleteCtx(), []StorageDriverClaim{{ClaimData: map[string]string{"name": "k"}}})
+	assert.EqualError(t, err, `claim missing field "bucket"`)
+
+	err = deleter.Delete(deleteCtx(), []StorageDriverClaim{{ClaimData: map[string]string{"bucket": "b"}}})
+	assert.EqualError(t, err, `claim missing field "name"`)
+}
+
+func TestDelete_DeleteObjectError(t *testing.T) {
+	ec := &errStore{
+		memStore:  newMemStore(),
+		deleteErr: errors.New("access denied"),
+	}
+	d := newDriver(t, ec)
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
+
+	err = d.Delete(deleteCtx(), claims)
+	assert.ErrorContains(t, err, "delete failed [bucket=test-bucket, name=")
+	assert.ErrorContains(t, err, ", client_project=test-project]: access denied")
+}
+
+func TestDelete_StoreWithoutDeleter(t *testing.T) {
+	d := newDriver(t, struct{ ObjectStore }{newMemStore()})
+	err := d.Delete(deleteCtx(), nil)
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+}
+
+// claimWriterStore records an extra claim field for every stored object.
+type claimWriterStore struct {
+	*memStore
+}
+
+func (s claimWriterStore) AddToClaim(_ context.Context, _, name string, data []byte, claimData map[string]string) error {
+	claimData["extra"] = fmt.Sprintf("%s:%d", name, len(data))
+	return nil
+}
+
+func TestStore_ClaimWriter(t *testing.T) {
+	ms := newMemStore()
+	d := newDriver(t, claimWriterStore{ms})
+	claims, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
+
+	// The claim writer is called even if the object already exists.
+	again, err := d.Store(storeCtx(), []*commonpb.Payload{testPayload("x")})
+	require.NoError(t, err)
+	assert.Equal(t, claims, again)
+	assert.Equal(t, int64(1), ms.putCount.Load())
+	data, _ := proto.Marshal(testPayload("x"))
+	assert.Equal(t, fmt.Sprintf("%s:%d", claims[0].ClaimData["name"], len(data)), claims[0].ClaimData["extra"])
+}
+
+func TestDescribeStore(t *testing.T) {
+	ms := newMemStore()
+	d := newDriver(t, ms)
+	ms.describe = nil
+	assert.Equal(t, "", d.describeStore())
+	ms.describe = map[string]string{}
+	assert.Equal(t, "", d.describeStore())
+	ms.describe = map[string]string{"client_project": "my-project", "foo": "bar"}
+	out := d.describeStore()
+	assert.Contains(t, out, ", client_project=my-project")
+	assert.Contains(t, out, ", foo=bar")
+}
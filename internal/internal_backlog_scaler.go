@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	"go.temporal.io/sdk/log"
+)
+
+// maxBacklogScaleFactor bounds how much a single backlog check can multiply the number of pollers.
+const maxBacklogScaleFactor = 2
+
+// backlogScaler periodically reads the backlog stats of a task queue and scales a worker when the
+// oldest backlogged task is older than the latency target.
+type backlogScaler struct {
+	taskQueue     string
+	taskQueueType TaskQueueType
+	target        time.Duration
+	interval      time.Duration
+	logger        log.Logger
+	describe      func(context.Context, DescribeTaskQueueEnhancedOptions) (TaskQueueDescription, error)
+	// scale is called with the ratio of the backlog age to the target after each check, set by
+	// the worker using the scaler.
+	scale func(ratio float64)
+	// buildID is the Build ID of the worker if it is versioned, or empty for the unversioned
+	// queue. Only the backlog of the version the worker polls for is scaled on.
+	buildID string
+
+	lastErr string
+}
+
+// newBacklogScaler returns a backlogScaler for a worker using the given poller behavior, or nil if
+// it does not scale on backlog.
+func newBacklogScaler(
+	client *WorkflowClient,
+	params workerExecutionParameters,
+	pollerBehavior PollerBehavior,
+	taskQueueType TaskQueueType,
+) *backlogScaler {
+	autoscaling, ok := pollerBehavior.(*pollerBehaviorAutoscaling)
+	if !ok || autoscaling.backlogLatencyTarget <= 0 || client == nil {
+		return nil
+	}
+	// Workers using deployment versioning are rejected by NewWorker, the backlog of a deployment
+	// version is not reported for a Build ID.
+	var buildID string
+	if params.UseBuildIDForVersioning {
+		buildID = params.getBuildID()
+	}
+	return &backlogScaler{
+		taskQueue:     params.TaskQueue,
+		taskQueueType: taskQueueType,
+		buildID:       buildID,
+		target:        autoscaling.backlogLatencyTarget,
+		interval:      autoscaling.backlogCheckInterval,
+		logger:        params.Logger,
+		describe:      client.DescribeTaskQueueEnhanced,
+	}
+}
+
+func (s *backlogScaler) run(stopCh <-chan struct{}) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		select {
+		case <-stopCh:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			s.check(ctx)
+		case <-stopCh:
+			return
+		}
+	}
+}
+
+// check reads the backlog and scales the worker on its age.
+func (s *backlogScaler) check(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, s.interval)
+	defer cancel()
+	versions := &TaskQueueVersionSelection{Unversioned: true}
+	if s.buildID != "" {
+		versions = &TaskQueueVersionSelection{BuildIDs: []string{s.buildID}}
+	}
+	description, err := s.describe(ctx, DescribeTaskQueueEnhancedOptions{
+		TaskQueue:      s.taskQueue,
+		Versions:       versions,
+		TaskQueueTypes: []TaskQueueType{s.taskQueueType},
+		ReportStats:    true,
+	})
+	if err != nil {
+		if ctx.Err() == nil && err.Error() != s.lastErr {
+			s.logger.Warn("Unable to read task queue backlog for poller autoscaling", tagError, err)
+		}
+		s.lastErr = err.Error()
+		return
+	}
+	s.lastErr = ""
+	var backlogAge time.Duration
+	if stats := description.VersionsInfo[s.buildID].TypesInfo[s.taskQueueType].Stats; stats != nil {
+		backlogAge = stats.ApproximateBacklogAge
+	}
+	s.scale(float64(backlogAge) / float64(s.target))
+}
+
+// handleBacklog scales up the number of pollers in proportion to the ratio of the backlog age to
+// its target when the backlog is behind.
+func (prh *pollScalerReportHandle) handleBacklog(ratio float64) {
+	if ratio <= 1 {
+		return
+	}
+	factor := math.Min(ratio, maxBacklogScaleFactor)
+	prh.updateTarget(func(target int64) int64 {
+		return int64(math.Ceil(float64(target) * factor))
+	})
+}
+
+// scaleForBacklog scales the pollers, other than sticky ones which do not serve the backlog, and
+// a resource based slot supplier of the worker on the ratio of the backlog age to its target.
+func (bw *baseWorker) scaleForBacklog(ratio float64) {
+	for i := range bw.options.taskPollers {
+		// Only read fields that are not changed by reconfiguration
+		taskWorker := &bw.options.taskPollers[i]
+		if taskWorker.pollerAutoscalerReportHandle != nil && taskWorker.taskPollerType != metrics.PollerTypeWorkflowStickyTask {
+			taskWorker.pollerAutoscalerReportHandle.handleBacklog(ratio)
+		}
+	}
+	if supplier := resourceBasedSlotSupplierOf(bw.slotSupplier.inner); supplier != nil {
+		supplier.scaleForBacklog(ratio, int(bw.slotSupplier.issuedSlotsAtomic.Load()))
+	}
+}
+
+// resourceBasedSlotSupplierOf returns the ResourceBasedSlotSupplier of a slot supplier, looking
+// through the suppliers of this package that wrap another one.
+func resourceBasedSlotSupplierOf(supplier SlotSupplier) *ResourceBasedSlotSupplier {
+	for {
+		switch s := supplier.(type) {
+		case *ResourceBasedSlotSupplier:
+			return s
+		case *ActivityTypeConcurrencySlotSupplier:
+			supplier = s.inner
+		case *RateLimitedSlotSupplier:
+			supplier = s.inner
+		default:
+			return nil
+		}
+	}
+}
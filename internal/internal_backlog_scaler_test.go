@@ -0,0 +1,147 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+func TestBacklogScalerCheck(t *testing.T) {
+	var backlogAge time.Duration
+	var describeErr error
+	var ratios []float64
+	scaler := newBacklogScaler(&WorkflowClient{}, workerExecutionParameters{TaskQueue: "tq", Logger: ilog.NewNopLogger()},
+		NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{BacklogLatencyTarget: 10 * time.Second}),
+		TaskQueueTypeActivity)
+	require.Equal(t, defaultBacklogCheckInterval, scaler.interval)
+	scaler.describe = func(_ context.Context, options DescribeTaskQueueEnhancedOptions) (TaskQueueDescription, error) {
+		require.Equal(t, "tq", options.TaskQueue)
+		require.Equal(t, &TaskQueueVersionSelection{Unversioned: true}, options.Versions)
+		require.True(t, options.ReportStats)
+		return TaskQueueDescription{VersionsInfo: map[string]TaskQueueVersionInfo{
+			"": {TypesInfo: map[TaskQueueType]TaskQueueTypeInfo{
+				TaskQueueTypeActivity: {Stats: &TaskQueueStats{ApproximateBacklogAge: backlogAge}},
+			}},
+			// The backlog of other versions is not served by this worker.
+			"other": {TypesInfo: map[TaskQueueType]TaskQueueTypeInfo{
+				TaskQueueTypeActivity: {Stats: &TaskQueueStats{ApproximateBacklogAge: time.Hour}},
+			}},
+		}}, describeErr
+	}
+	scaler.scale = func(ratio float64) { ratios = append(ratios, ratio) }
+
+	backlogAge = 30 * time.Second
+	scaler.check(context.Background())
+	backlogAge = 0
+	scaler.check(context.Background())
+	describeErr = errors.New("not supported")
+	scaler.check(context.Background())
+	require.Equal(t, []float64{3, 0}, ratios)
+
+	// Versioned workers read the backlog of their Build ID.
+	versioned := newBacklogScaler(&WorkflowClient{},
+		workerExecutionParameters{TaskQueue: "tq", WorkerBuildID: "v1", UseBuildIDForVersioning: true, Logger: ilog.NewNopLogger()},
+		NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{BacklogLatencyTarget: 10 * time.Second}),
+		TaskQueueTypeActivity)
+	versioned.describe = func(_ context.Context, options DescribeTaskQueueEnhancedOptions) (TaskQueueDescription, error) {
+		require.Equal(t, &TaskQueueVersionSelection{BuildIDs: []string{"v1"}}, options.Versions)
+		return TaskQueueDescription{VersionsInfo: map[string]TaskQueueVersionInfo{
+			"v1": {TypesInfo: map[TaskQueueType]TaskQueueTypeInfo{
+				TaskQueueTypeActivity: {Stats: &TaskQueueStats{ApproximateBacklogAge: 20 * time.Second}},
+			}},
+		}}, nil
+	}
+	versioned.scale = func(ratio float64) { ratios = append(ratios, ratio) }
+	versioned.check(context.Background())
+	require.Equal(t, []float64{3, 0, 2}, ratios)
+
+	require.Nil(t, newBacklogScaler(&WorkflowClient{}, workerExecutionParameters{},
+		NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{}), TaskQueueTypeActivity))
+	require.Nil(t, newBacklogScaler(nil, workerExecutionParameters{},
+		NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{BacklogLatencyTarget: time.Second}), TaskQueueTypeActivity))
+}
+
+func TestPollScalerHandleBacklog(t *testing.T) {
+	var permits []int
+	ps := newPollScalerReportHandle(pollScalerReportHandleOptions{
+		initialPollerCount: 3,
+		maxPollerCount:     10,
+		minPollerCount:     1,
+		scaleCallback:      func(p int) { permits = append(permits, p) },
+	})
+	ps.handleBacklog(0.5)
+	ps.handleBacklog(1.5)
+	ps.handleBacklog(5)
+	ps.handleBacklog(5)
+	require.Equal(t, []int{5, 10, 10}, permits)
+}
+
+func TestResourceBasedSlotSupplierBacklogSlotTarget(t *testing.T) {
+	sysInfo := &FakeSystemInfoSupplier{memUse: 0.5, cpuUse: 0.5}
+	controllerOptions := DefaultResourceControllerOptions()
+	controllerOptions.InfoSupplier = sysInfo
+	// The PID controller never issues slots beyond the minimum, so only the slot target does.
+	controllerOptions.MemOutputThreshold = math.Inf(1)
+	supplier, err := NewResourceBasedSlotSupplier(NewResourceController(controllerOptions), ResourceBasedSlotSupplierOptions{
+		MinSlots:     2,
+		MaxSlots:     10,
+		RampThrottle: time.Hour,
+	})
+	require.NoError(t, err)
+	issued := &atomic.Int32{}
+	info := slotReserveInfoImpl{taskQueue: "tq", issuedSlots: issued, logger: ilog.NewNopLogger(), metrics: metrics.NopHandler}
+	reserveAll := func() {
+		for supplier.TryReserveSlot(info) != nil {
+			issued.Add(1)
+		}
+	}
+
+	// Slots beyond MinSlots wait for RampThrottle and the PID controller without a target.
+	issued.Store(2)
+	require.Nil(t, supplier.TryReserveSlot(info))
+
+	// The target grows from the slots issued, by at most twice per check, up to MaxSlots.
+	supplier.scaleForBacklog(1.5, int(issued.Load()))
+	reserveAll()
+	require.EqualValues(t, 3, issued.Load())
+	supplier.scaleForBacklog(5, int(issued.Load()))
+	reserveAll()
+	require.EqualValues(t, 6, issued.Load())
+	supplier.scaleForBacklog(5, int(issued.Load()))
+	reserveAll()
+	require.EqualValues(t, 10, issued.Load())
+
+	// Slots within the target are only issued while resources are below their targets.
+	issued.Store(4)
+	sysInfo.memUse = 0.9
+	require.Nil(t, supplier.TryReserveSlot(info))
+	sysInfo.memUse = 0.5
+	require.NotNil(t, supplier.TryReserveSlot(info))
+
+	// The target is dropped once the backlog is no longer behind.
+	supplier.scaleForBacklog(0.5, int(issued.Load()))
+	require.Nil(t, supplier.TryReserveSlot(info))
+	require.Same(t, supplier, resourceBasedSlotSupplierOf(newActivityTypeConcurrencySlotSupplier(supplier, nil)))
+}
+
+func TestBacklogLatencyTargetWithDeploymentVersioning(t *testing.T) {
+	require.PanicsWithValue(t, "cannot set both BacklogLatencyTarget and DeploymentOptions.UseVersioning", func() {
+		NewAggregatedWorker(&WorkflowClient{}, "tq", WorkerOptions{
+			DeploymentOptions: WorkerDeploymentOptions{
+				UseVersioning: true,
+				Version:       WorkerDeploymentVersion{DeploymentName: "deployment", BuildID: "v1"},
+			},
+			ActivityTaskPollerBehavior: NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{
+				BacklogLatencyTarget: time.Second,
+			}),
+		})
+	})
+}
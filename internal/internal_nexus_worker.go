@@ -58,6 +58,7 @@ func newNexusWorker(opts nexusWorkerOptions) (*nexusWorker, error) {
 		},
 		taskProcessor:  poller,
 		workerType:     "NexusWorker",
+		taskType:       WorkerTaskTypeNexus,
 		identity:       params.Identity,
 		buildId:        params.getBuildID(),
 		logger:         params.Logger,
@@ -67,7 +68,11 @@ func newNexusWorker(opts nexusWorkerOptions) (*nexusWorker, error) {
 		slotReservationData: slotReservationData{
 			taskQueue: params.TaskQueue,
 		},
-		isInternalWorker: params.isInternalWorker(),
+		isInternalWorker:        params.isInternalWorker(),
+		slotReservationObserver: slotReservationObserverOf(params.Tuner),
+	}
+	if client, ok := opts.client.(*WorkflowClient); ok {
+		bwo.backlogScaler = newBacklogScaler(client, params, params.NexusTaskPollerBehavior, TaskQueueTypeNexus)
 	}
 
 	baseWorker := newBaseWorker(bwo)
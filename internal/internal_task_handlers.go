@@ -140,6 +140,7 @@ type (
 		contextPropagators        []ContextPropagator
 		cache                     *WorkerCache
 		deadlockDetectionTimeout  time.Duration
+		deadlockDetections        *atomic.Int64
 		capabilities              *workflowservice.GetSystemInfoResponse_Capabilities
 	}
 
@@ -582,6 +583,7 @@ func newWorkflowTaskHandler(params workerExecutionParameters, ppMgr pressurePoin
 		contextPropagators:        params.ContextPropagators,
 		cache:                     params.cache,
 		deadlockDetectionTimeout:  params.DeadlockDetectionTimeout,
+		deadlockDetections:        params.deadlockDetections,
 		capabilities:              params.capabilities,
 	}
 }
@@ -640,6 +642,9 @@ func (w *workflowExecutionContextImpl) completeWorkflow(result *commonpb.Payload
 	w.isWorkflowCompleted = true
 	w.result = result
 	w.err = err
+	if panicErr, ok := err.(*workflowPanicError); ok && panicErr.potentialDeadlock && w.wth.deadlockDetections != nil {
+		w.wth.deadlockDetections.Add(1)
+	}
 }
 
 func (w *workflowExecutionContextImpl) onEviction() {
@@ -6,6 +6,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math"
 	"sort"
 	"sync"
 	"sync/atomic"
@@ -73,6 +74,12 @@ type (
 		isEmpty() bool
 	}
 
+	// slotTaskInfoProvider is implemented by tasks that can describe themselves to the slot
+	// supplier when their slot is marked used.
+	slotTaskInfoProvider interface {
+		slotTaskInfo() SlotTaskInfo
+	}
+
 	// basePoller is the base class for all poller implementations
 	basePoller struct {
 		metricsHandler metrics.Handler // base metric handler used for rpc calls
@@ -164,13 +171,15 @@ type (
 	// activityTaskPoller implements polling/processing a workflow task
 	activityTaskPoller struct {
 		basePoller
-		namespace           string
-		taskQueueName       string
-		identity            string
-		service             workflowservice.WorkflowServiceClient
-		taskHandler         ActivityTaskHandler
-		logger              log.Logger
-		activitiesPerSecond float64
+		namespace     string
+		taskQueueName string
+		identity      string
+		service       workflowservice.WorkflowServiceClient
+		taskHandler   ActivityTaskHandler
+		logger        log.Logger
+		// activitiesPerSecond holds the float64 bits of the task queue rate limit sent with each
+		// poll, it may be changed while polling by Reconfigure.
+		activitiesPerSecond atomic.Uint64
 		numPollerMetric     *numPollerMetric
 	}
 
@@ -1362,7 +1371,7 @@ func newGetHistoryPageFunc(
 }
 
 func newActivityTaskPoller(taskHandler ActivityTaskHandler, service workflowservice.WorkflowServiceClient, params workerExecutionParameters) *activityTaskPoller {
-	return &activityTaskPoller{
+	atp := &activityTaskPoller{
 		basePoller: basePoller{
 			metricsHandler:               params.MetricsHandler,
 			stopC:                        params.WorkerStopChannel,
@@ -1374,15 +1383,24 @@ func newActivityTaskPoller(taskHandler ActivityTaskHandler, service workflowserv
 			workerInstanceKey:            params.workerInstanceKey,
 			workerPollCompleteOnShutdown: params.workerPollCompleteOnShutdown,
 		},
-		taskHandler:         taskHandler,
-		service:             service,
-		namespace:           params.Namespace,
-		taskQueueName:       params.TaskQueue,
-		identity:            params.Identity,
-		logger:              params.Logger,
-		activitiesPerSecond: params.TaskQueueActivitiesPerSecond,
-		numPollerMetric:     newNumPollerMetric(params.MetricsHandler, metrics.PollerTypeActivityTask),
-	}
+		taskHandler:     taskHandler,
+		service:         service,
+		namespace:       params.Namespace,
+		taskQueueName:   params.TaskQueue,
+		identity:        params.Identity,
+		logger:          params.Logger,
+		numPollerMetric: newNumPollerMetric(params.MetricsHandler, metrics.PollerTypeActivityTask),
+	}
+	atp.setActivitiesPerSecond(params.TaskQueueActivitiesPerSecond)
+	return atp
+}
+
+func (atp *activityTaskPoller) getActivitiesPerSecond() float64 {
+	return math.Float64frombits(atp.activitiesPerSecond.Load())
+}
+
+func (atp *activityTaskPoller) setActivitiesPerSecond(activitiesPerSecond float64) {
+	atp.activitiesPerSecond.Store(math.Float64bits(activitiesPerSecond))
 }
 
 // Poll the activity task queue and update the num_poller metric
@@ -1402,7 +1420,7 @@ func (atp *activityTaskPoller) poll(ctx context.Context) (taskForWorker, error)
 		Namespace:         atp.namespace,
 		TaskQueue:         &taskqueuepb.TaskQueue{Name: atp.taskQueueName, Kind: enumspb.TASK_QUEUE_KIND_NORMAL},
 		Identity:          atp.identity,
-		TaskQueueMetadata: &taskqueuepb.TaskQueueMetadata{MaxTasksPerSecond: wrapperspb.Double(atp.activitiesPerSecond)},
+		TaskQueueMetadata: &taskqueuepb.TaskQueueMetadata{MaxTasksPerSecond: wrapperspb.Double(atp.getActivitiesPerSecond())},
 		WorkerVersionCapabilities: &commonpb.WorkerVersionCapabilities{
 			BuildId:              atp.workerBuildID,
 			UseVersioning:        atp.useBuildIDVersioning,
@@ -1722,6 +1740,10 @@ func (wft *workflowTask) isEmpty() bool {
 	return wft.task == nil
 }
 
+func (wft *workflowTask) slotTaskInfo() SlotTaskInfo {
+	return SlotTaskInfo{WorkflowType: wft.task.GetWorkflowType().GetName()}
+}
+
 func (wft *workflowTask) scaleDecision() (pollerScaleDecision, bool) {
 	if wft.task == nil || wft.task.PollerScalingDecision == nil {
 		return pollerScaleDecision{}, false
@@ -1735,6 +1757,13 @@ func (at *activityTask) isEmpty() bool {
 	return at.task == nil
 }
 
+func (at *activityTask) slotTaskInfo() SlotTaskInfo {
+	return SlotTaskInfo{
+		ActivityType: at.task.GetActivityType().GetName(),
+		Priority:     convertFromPBPriority(at.task.GetPriority()),
+	}
+}
+
 func (at *activityTask) scaleDecision() (pollerScaleDecision, bool) {
 	if at.task == nil || at.task.PollerScalingDecision == nil {
 		return pollerScaleDecision{}, false
@@ -1748,6 +1777,10 @@ func (*localActivityTask) isEmpty() bool {
 	return false
 }
 
+func (lat *localActivityTask) slotTaskInfo() SlotTaskInfo {
+	return SlotTaskInfo{ActivityType: lat.params.ActivityType}
+}
+
 func (*localActivityTask) scaleDecision() (pollerScaleDecision, bool) {
 	return pollerScaleDecision{}, false
 }
@@ -1756,6 +1789,10 @@ func (*eagerWorkflowTask) isEmpty() bool {
 	return false
 }
 
+func (ewt *eagerWorkflowTask) slotTaskInfo() SlotTaskInfo {
+	return SlotTaskInfo{WorkflowType: ewt.task.GetWorkflowType().GetName()}
+}
+
 func (*eagerWorkflowTask) scaleDecision() (pollerScaleDecision, bool) {
 	return pollerScaleDecision{}, false
 }
@@ -52,6 +52,8 @@ const (
 	defaultAutoscalingMinimumNumberOfPollers = 1   // Default minimum number of pollers when using autoscaling.
 	defaultAutoscalingMaximumNumberOfPollers = 100 // Default maximum number of pollers when using autoscaling.
 
+	defaultBacklogCheckInterval = 10 * time.Second // Default interval between task queue backlog checks.
+
 	defaultMaxConcurrentActivityExecutionSize = 1000   // Large concurrent activity execution size (1k)
 	defaultWorkerActivitiesPerSecond          = 100000 // Large activity executions/sec (unlimited)
 
@@ -99,6 +101,11 @@ type (
 		worker              *baseWorker
 		identity            string
 		stopC               chan struct{}
+		registry            *registry
+		// typeLimiter applies the concurrency limits of activity types, if enabled
+		typeLimiter *ActivityTypeConcurrencySlotSupplier
+		// typeLimitsLock guards the limits of the execution parameters, which can be reconfigured
+		typeLimitsLock sync.Mutex
 	}
 
 	// sessionWorker wraps the code for hosting session creation, completion and
@@ -130,6 +137,12 @@ type (
 		// Defines rate limiting on number of activity tasks that can be executed per second per worker.
 		WorkerActivitiesPerSecond float64
 
+		// Limits the number of concurrently running activity tasks per activity type.
+		MaxConcurrentActivityTypeExecutionSize map[string]int
+
+		// Whether the limits of activity types are applied.
+		EnableActivityTypeConcurrencyLimits bool
+
 		// Defines rate limiting on number of local activities that can be executed per second per worker.
 		WorkerLocalActivitiesPerSecond float64
 
@@ -218,6 +231,9 @@ type (
 
 		pollTimeTracker *pollTimeTracker
 
+		// deadlockDetections counts the workflow tasks that failed on potential deadlocks.
+		deadlockDetections *atomic.Int64
+
 		workerInstanceKey string
 
 		workerPollCompleteOnShutdown *atomic.Bool
@@ -290,6 +306,9 @@ func ensureRequiredParams(params *workerExecutionParameters) {
 	if params.pollTimeTracker == nil {
 		params.pollTimeTracker = &pollTimeTracker{}
 	}
+	if params.deadlockDetections == nil {
+		params.deadlockDetections = &atomic.Int64{}
+	}
 }
 
 // getBuildID returns either the user-defined build ID if it was provided, or an autogenerated one
@@ -382,6 +401,7 @@ func newWorkflowTaskWorkerInternal(
 		taskPollers:       scalableTaskPollers,
 		taskProcessor:     taskProcessor,
 		workerType:        "WorkflowWorker",
+		taskType:          WorkerTaskTypeWorkflow,
 		identity:          params.Identity,
 		buildId:           params.getBuildID(),
 		deploymentOptions: params.DeploymentOptions,
@@ -392,6 +412,8 @@ func newWorkflowTaskWorkerInternal(
 		slotReservationData: slotReservationData{
 			taskQueue: params.TaskQueue,
 		},
+		backlogScaler:           newBacklogScaler(client, params, params.WorkflowTaskPollerBehavior, TaskQueueTypeWorkflow),
+		slotReservationObserver: slotReservationObserverOf(params.Tuner),
 	}
 
 	worker := newBaseWorker(bwo)
@@ -431,6 +453,7 @@ func newWorkflowTaskWorkerInternal(
 		},
 		taskProcessor:  localActivityTaskPoller,
 		workerType:     "LocalActivityWorker",
+		taskType:       WorkerTaskTypeLocalActivity,
 		identity:       laParams.Identity,
 		buildId:        laParams.getBuildID(),
 		logger:         laParams.Logger,
@@ -440,6 +463,7 @@ func newWorkflowTaskWorkerInternal(
 		slotReservationData: slotReservationData{
 			taskQueue: params.TaskQueue,
 		},
+		slotReservationObserver: slotReservationObserverOf(laParams.Tuner),
 	},
 	)
 
@@ -573,6 +597,12 @@ func newActivityWorker(
 	} else {
 		slotSupplier = params.Tuner.GetActivityTaskSlotSupplier()
 	}
+	var typeLimiter *ActivityTypeConcurrencySlotSupplier
+	if params.EnableActivityTypeConcurrencyLimits {
+		// The limits are set when the worker starts, once activities are registered.
+		typeLimiter = newActivityTypeConcurrencySlotSupplier(slotSupplier, nil)
+		slotSupplier = typeLimiter
+	}
 	bwo := baseWorkerOptions{
 		pollerRate:       defaultPollerRate,
 		slotSupplier:     slotSupplier,
@@ -582,6 +612,7 @@ func newActivityWorker(
 		},
 		taskProcessor:           poller,
 		workerType:              "ActivityWorker",
+		taskType:                WorkerTaskTypeActivity,
 		identity:                params.Identity,
 		buildId:                 params.getBuildID(),
 		logger:                  params.Logger,
@@ -593,6 +624,11 @@ func newActivityWorker(
 		slotReservationData: slotReservationData{
 			taskQueue: params.TaskQueue,
 		},
+		slotReservationObserver: slotReservationObserverOf(params.Tuner),
+	}
+	if overrides == nil {
+		// Session task queues are not scaled on backlog
+		bwo.backlogScaler = newBacklogScaler(client, params, params.ActivityTaskPollerBehavior, TaskQueueTypeActivity)
 	}
 
 	base := newBaseWorker(bwo)
@@ -603,15 +639,44 @@ func newActivityWorker(
 		poller:              poller,
 		identity:            params.Identity,
 		stopC:               workerStopChannel,
+		registry:            env,
+		typeLimiter:         typeLimiter,
 	}
 }
 
 // Start the worker.
 func (aw *activityWorker) Start() error {
+	if aw.typeLimiter != nil {
+		aw.typeLimitsLock.Lock()
+		aw.typeLimiter.setLimits(aw.activityConcurrencyLimits())
+		aw.typeLimitsLock.Unlock()
+	}
 	aw.worker.Start()
 	return nil // TODO: propagate errors
 }
 
+// setMaxConcurrentActivityTypeExecutionSize replaces the limits of the worker options, which are
+// applied if the worker has a typeLimiter.
+func (aw *activityWorker) setMaxConcurrentActivityTypeExecutionSize(limits map[string]int) {
+	aw.typeLimitsLock.Lock()
+	defer aw.typeLimitsLock.Unlock()
+	aw.executionParameters.MaxConcurrentActivityTypeExecutionSize = limits
+	aw.typeLimiter.setLimits(aw.activityConcurrencyLimits())
+}
+
+// activityConcurrencyLimits returns the concurrency limits of activity types, with the ones of the
+// worker options taking precedence over the ones set at registration.
+func (aw *activityWorker) activityConcurrencyLimits() map[string]int {
+	limits := make(map[string]int)
+	if aw.registry != nil {
+		limits = aw.registry.getActivityConcurrencyLimits()
+	}
+	for activityType, limit := range aw.executionParameters.MaxConcurrentActivityTypeExecutionSize {
+		limits[activityType] = limit
+	}
+	return limits
+}
+
 // Stop the worker.
 func (aw *activityWorker) Stop() {
 	close(aw.stopC)
@@ -626,6 +691,7 @@ type registry struct {
 	workflowVersioningBehaviorMap map[string]VersioningBehavior
 	activityFuncMap               map[string]activity
 	activityAliasMap              map[string]string
+	activityConcurrencyLimits     map[string]int
 	dynamicWorkflow               interface{}
 	dynamicWorkflowOptions        DynamicRegisterWorkflowOptions
 	dynamicActivity               activity
@@ -723,6 +789,9 @@ func (r *registry) RegisterActivityWithOptions(
 	af interface{},
 	options RegisterActivityOptions,
 ) {
+	if options.MaxConcurrentExecutions < 0 {
+		panic("MaxConcurrentExecutions must not be negative")
+	}
 	// Support direct registration of activity
 	a, ok := af.(activity)
 	if ok {
@@ -733,6 +802,9 @@ func (r *registry) RegisterActivityWithOptions(
 			panic(temporalPrefixError)
 		}
 		r.addActivityWithLock(options.Name, a)
+		r.Lock()
+		r.setActivityConcurrencyLimitNoLock(options.Name, options.MaxConcurrentExecutions)
+		r.Unlock()
 		return
 	}
 	// Validate that it is a function
@@ -767,6 +839,7 @@ func (r *registry) RegisterActivityWithOptions(
 		}
 	}
 	r.activityFuncMap[registerName] = &activityExecutor{name: registerName, fn: af}
+	r.setActivityConcurrencyLimitNoLock(registerName, options.MaxConcurrentExecutions)
 	if len(alias) > 0 && r.activityAliasMap != nil {
 		r.activityAliasMap[fnName] = alias
 	}
@@ -801,6 +874,7 @@ func (r *registry) registerActivityStructWithOptions(aStruct interface{}, option
 			}
 		}
 		r.activityFuncMap[registerName] = &activityExecutor{name: registerName, fn: methodValue.Interface()}
+		r.setActivityConcurrencyLimitNoLock(registerName, options.MaxConcurrentExecutions)
 		count++
 	}
 	if count == 0 {
@@ -886,6 +960,24 @@ func (r *registry) addActivityWithLock(fnName string, a activity) {
 	r.activityFuncMap[fnName] = a
 }
 
+func (r *registry) setActivityConcurrencyLimitNoLock(activityType string, limit int) {
+	if limit > 0 {
+		r.activityConcurrencyLimits[activityType] = limit
+	} else {
+		delete(r.activityConcurrencyLimits, activityType)
+	}
+}
+
+func (r *registry) getActivityConcurrencyLimits() map[string]int {
+	r.Lock()
+	defer r.Unlock()
+	limits := make(map[string]int, len(r.activityConcurrencyLimits))
+	for activityType, limit := range r.activityConcurrencyLimits {
+		limits[activityType] = limit
+	}
+	return limits
+}
+
 func (r *registry) GetActivity(fnName string) (activity, bool) {
 	r.Lock()
 	defer r.Unlock()
@@ -1054,6 +1146,7 @@ func newRegistryWithOptions(options registryOptions) *registry {
 		workflowFuncMap:               make(map[string]interface{}),
 		workflowVersioningBehaviorMap: make(map[string]VersioningBehavior),
 		activityFuncMap:               make(map[string]activity),
+		activityConcurrencyLimits:     make(map[string]int),
 		nexusServices:                 make(map[string]*nexus.Service),
 	}
 	if !options.disableAliasing {
@@ -1212,6 +1305,12 @@ type AggregatedWorker struct {
 	heartbeatMetrics             *heartbeatMetricsHandler
 	heartbeatCallback            func() *workerpb.WorkerHeartbeat
 	workerPollCompleteOnShutdown *atomic.Bool
+
+	// reconfigureLock serializes Reconfigure, Pause and Resume, and guards the nexus worker while it
+	// is created on Start.
+	reconfigureLock sync.Mutex
+	// nexusPaused is whether nexus polling is paused, applied to the nexus worker once created.
+	nexusPaused bool
 }
 
 // RegisterWorkflow registers workflow implementation with the AggregatedWorker
@@ -1358,6 +1457,17 @@ func (aw *AggregatedWorker) start() error {
 		}
 	}
 	if !util.IsInterfaceNil(aw.activityWorker) {
+		if !aw.executionParams.EnableActivityTypeConcurrencyLimits {
+			var activityTypes []string
+			for activityType := range aw.registry.getActivityConcurrencyLimits() {
+				activityTypes = append(activityTypes, activityType)
+			}
+			if len(activityTypes) > 0 {
+				sort.Strings(activityTypes)
+				aw.logger.Warn("Ignoring the MaxConcurrentExecutions of registered activities, "+
+					"EnableActivityTypeConcurrencyLimits is not set", "ActivityTypes", activityTypes)
+			}
+		}
 		if err := aw.activityWorker.Start(); err != nil {
 			// stop workflow worker.
 			if !util.IsInterfaceNil(aw.workflowWorker) {
@@ -1389,6 +1499,8 @@ func (aw *AggregatedWorker) start() error {
 			return err
 		}
 	}
+	aw.reconfigureLock.Lock()
+	defer aw.reconfigureLock.Unlock()
 	nexusServices := aw.registry.getRegisteredNexusServices()
 	if len(nexusServices) > 0 {
 		reg := nexus.NewServiceRegistry()
@@ -1412,6 +1524,9 @@ func (aw *AggregatedWorker) start() error {
 		if err != nil {
 			return fmt.Errorf("failed to create a nexus worker: %w", err)
 		}
+		if aw.nexusPaused {
+			aw.nexusWorker.worker.pause()
+		}
 		if err := aw.nexusWorker.Start(); err != nil {
 			return fmt.Errorf("failed to start a nexus worker: %w", err)
 		}
@@ -1500,6 +1615,147 @@ func (aw *AggregatedWorker) Run(interruptCh <-chan interface{}) error {
 	return nil
 }
 
+// Reconfigure changes settings of the worker while it is running, without interrupting tasks in
+// progress. All changes are validated before any is applied, so an error leaves the worker
+// unchanged.
+func (aw *AggregatedWorker) Reconfigure(options WorkerReconfigureOptions) error {
+	select {
+	case <-aw.stopC:
+		return errors.New("cannot reconfigure a stopped worker")
+	default:
+	}
+	aw.reconfigureLock.Lock()
+	defer aw.reconfigureLock.Unlock()
+
+	var changes []func()
+	tuner := aw.executionParams.Tuner
+	slotChanges := []struct {
+		name     string
+		numSlots int
+		supplier func() SlotSupplier
+	}{
+		{"MaxConcurrentWorkflowTaskExecutionSize", options.MaxConcurrentWorkflowTaskExecutionSize, tuner.GetWorkflowTaskSlotSupplier},
+		{"MaxConcurrentActivityExecutionSize", options.MaxConcurrentActivityExecutionSize, tuner.GetActivityTaskSlotSupplier},
+		{"MaxConcurrentLocalActivityExecutionSize", options.MaxConcurrentLocalActivityExecutionSize, tuner.GetLocalActivitySlotSupplier},
+		{"MaxConcurrentNexusTaskExecutionSize", options.MaxConcurrentNexusTaskExecutionSize, tuner.GetNexusSlotSupplier},
+	}
+	for _, c := range slotChanges {
+		if c.numSlots == 0 {
+			continue
+		}
+		if c.numSlots < 0 {
+			return fmt.Errorf("%s must be positive", c.name)
+		}
+		supplier := c.supplier()
+		fixed, ok := supplier.(*FixedSizeSlotSupplier)
+		if !ok {
+			return fmt.Errorf("%s can only be changed for a fixed size slot supplier, not %s",
+				c.name, getSlotSupplierKind(supplier))
+		}
+		numSlots := c.numSlots
+		changes = append(changes, func() { fixed.setNumSlots(numSlots) })
+	}
+
+	if options.WorkflowTaskPollerBehavior != nil {
+		if util.IsInterfaceNil(aw.workflowWorker) {
+			return errors.New("cannot change WorkflowTaskPollerBehavior, the workflow worker is disabled")
+		}
+		if err := aw.workflowWorker.worker.checkPollerBehavior(options.WorkflowTaskPollerBehavior); err != nil {
+			return fmt.Errorf("invalid WorkflowTaskPollerBehavior: %w", err)
+		}
+		changes = append(changes, func() {
+			aw.workflowWorker.worker.setPollerBehavior(options.WorkflowTaskPollerBehavior)
+		})
+	}
+	if options.ActivityTaskPollerBehavior != nil {
+		if util.IsInterfaceNil(aw.activityWorker) {
+			return errors.New("cannot change ActivityTaskPollerBehavior, the activity worker is disabled")
+		}
+		if err := aw.activityWorker.worker.checkPollerBehavior(options.ActivityTaskPollerBehavior); err != nil {
+			return fmt.Errorf("invalid ActivityTaskPollerBehavior: %w", err)
+		}
+		changes = append(changes, func() {
+			aw.activityWorker.worker.setPollerBehavior(options.ActivityTaskPollerBehavior)
+		})
+	}
+	if options.NexusTaskPollerBehavior != nil {
+		// The nexus worker is only created on Start, until then the behavior it will be created
+		// with is changed instead.
+		_, autoscaling := aw.executionParams.NexusTaskPollerBehavior.(*pollerBehaviorAutoscaling)
+		if err := checkPollerBehaviorKind(autoscaling, options.NexusTaskPollerBehavior); err != nil {
+			return fmt.Errorf("invalid NexusTaskPollerBehavior: %w", err)
+		}
+		changes = append(changes, func() {
+			aw.executionParams.NexusTaskPollerBehavior = options.NexusTaskPollerBehavior
+			if !util.IsInterfaceNil(aw.nexusWorker) {
+				aw.nexusWorker.worker.setPollerBehavior(options.NexusTaskPollerBehavior)
+			}
+		})
+	}
+
+	if options.MaxConcurrentActivityTypeExecutionSize != nil {
+		if util.IsInterfaceNil(aw.activityWorker) {
+			return errors.New("cannot change MaxConcurrentActivityTypeExecutionSize, the activity worker is disabled")
+		}
+		if aw.activityWorker.typeLimiter == nil {
+			return errors.New("cannot change MaxConcurrentActivityTypeExecutionSize, EnableActivityTypeConcurrencyLimits is not set")
+		}
+		limits := make(map[string]int, len(options.MaxConcurrentActivityTypeExecutionSize))
+		for activityType, limit := range options.MaxConcurrentActivityTypeExecutionSize {
+			if limit <= 0 {
+				return fmt.Errorf("MaxConcurrentActivityTypeExecutionSize for activity type %q must be positive", activityType)
+			}
+			limits[activityType] = limit
+		}
+		changes = append(changes, func() {
+			aw.activityWorker.setMaxConcurrentActivityTypeExecutionSize(limits)
+		})
+	}
+
+	if options.WorkerActivitiesPerSecond < 0 {
+		return errors.New("WorkerActivitiesPerSecond must be positive")
+	} else if options.WorkerActivitiesPerSecond > 0 {
+		if util.IsInterfaceNil(aw.activityWorker) {
+			return errors.New("cannot change WorkerActivitiesPerSecond, the activity worker is disabled")
+		}
+		changes = append(changes, func() {
+			aw.activityWorker.worker.setMaxTaskPerSecond(options.WorkerActivitiesPerSecond)
+		})
+	}
+	if options.TaskQueueActivitiesPerSecond < 0 {
+		return errors.New("TaskQueueActivitiesPerSecond must be positive")
+	} else if options.TaskQueueActivitiesPerSecond > 0 {
+		if util.IsInterfaceNil(aw.activityWorker) {
+			return errors.New("cannot change TaskQueueActivitiesPerSecond, the activity worker is disabled")
+		}
+		poller, ok := aw.activityWorker.poller.(*activityTaskPoller)
+		if !ok {
+			return errors.New("cannot change TaskQueueActivitiesPerSecond of this activity worker")
+		}
+		changes = append(changes, func() {
+			poller.setActivitiesPerSecond(options.TaskQueueActivitiesPerSecond)
+		})
+	}
+	if options.WorkerLocalActivitiesPerSecond < 0 {
+		return errors.New("WorkerLocalActivitiesPerSecond must be positive")
+	} else if options.WorkerLocalActivitiesPerSecond > 0 {
+		if util.IsInterfaceNil(aw.workflowWorker) {
+			return errors.New("cannot change WorkerLocalActivitiesPerSecond, the workflow worker is disabled")
+		}
+		changes = append(changes, func() {
+			aw.workflowWorker.localActivityWorker.setMaxTaskPerSecond(options.WorkerLocalActivitiesPerSecond)
+		})
+	}
+
+	for _, change := range changes {
+		change()
+	}
+	if len(changes) > 0 {
+		aw.logger.Info("Reconfigured Worker")
+	}
+	return nil
+}
+
 // Stop the worker.
 func (aw *AggregatedWorker) Stop() {
 	// Only attempt stop if we haven't attempted before
@@ -2185,6 +2441,15 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 	if options.MaxConcurrentWorkflowTaskExternalStorageVisits < 0 {
 		panic("MaxConcurrentWorkflowTaskExternalStorageVisits must not be negative")
 	}
+	if options.DeploymentOptions.UseVersioning {
+		for _, pollerBehavior := range []PollerBehavior{
+			options.WorkflowTaskPollerBehavior, options.ActivityTaskPollerBehavior, options.NexusTaskPollerBehavior,
+		} {
+			if autoscaling, ok := pollerBehavior.(*pollerBehaviorAutoscaling); ok && autoscaling.backlogLatencyTarget > 0 {
+				panic("cannot set both BacklogLatencyTarget and DeploymentOptions.UseVersioning")
+			}
+		}
+	}
 
 	// Need reference to result for fatal error handler
 	var aw *AggregatedWorker
@@ -2252,32 +2517,34 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 	cache := NewWorkerCache()
 	workerPollCompleteOnShutdown := &atomic.Bool{}
 	workerParams := workerExecutionParameters{
-		Namespace:                        client.namespace,
-		TaskQueue:                        taskQueue,
-		Tuner:                            options.Tuner,
-		WorkerActivitiesPerSecond:        options.WorkerActivitiesPerSecond,
-		WorkerLocalActivitiesPerSecond:   options.WorkerLocalActivitiesPerSecond,
-		Identity:                         identity,
-		WorkerBuildID:                    options.BuildID,
-		UseBuildIDForVersioning:          options.UseBuildIDForVersioning || options.DeploymentOptions.UseVersioning,
-		DeploymentOptions:                options.DeploymentOptions,
-		MetricsHandler:                   metricsHandler,
-		Logger:                           logger,
-		EnableLoggingInReplay:            options.EnableLoggingInReplay,
-		BackgroundContext:                backgroundActivityContext,
-		BackgroundContextCancel:          backgroundActivityContextCancel,
-		StickyScheduleToStartTimeout:     options.StickyScheduleToStartTimeout,
-		TaskQueueActivitiesPerSecond:     options.TaskQueueActivitiesPerSecond,
-		WorkflowPanicPolicy:              options.WorkflowPanicPolicy,
-		DataConverter:                    client.dataConverter,
-		FailureConverter:                 client.failureConverter,
-		WorkerStopTimeout:                options.WorkerStopTimeout,
-		WorkerFatalErrorCallback:         fatalErrorCallback,
-		ContextPropagators:               client.contextPropagators,
-		DeadlockDetectionTimeout:         options.DeadlockDetectionTimeout,
-		DefaultHeartbeatThrottleInterval: options.DefaultHeartbeatThrottleInterval,
-		MaxHeartbeatThrottleInterval:     options.MaxHeartbeatThrottleInterval,
-		cache:                            cache,
+		Namespace:                              client.namespace,
+		TaskQueue:                              taskQueue,
+		Tuner:                                  options.Tuner,
+		WorkerActivitiesPerSecond:              options.WorkerActivitiesPerSecond,
+		MaxConcurrentActivityTypeExecutionSize: options.MaxConcurrentActivityTypeExecutionSize,
+		EnableActivityTypeConcurrencyLimits:    options.EnableActivityTypeConcurrencyLimits,
+		WorkerLocalActivitiesPerSecond:         options.WorkerLocalActivitiesPerSecond,
+		Identity:                               identity,
+		WorkerBuildID:                          options.BuildID,
+		UseBuildIDForVersioning:                options.UseBuildIDForVersioning || options.DeploymentOptions.UseVersioning,
+		DeploymentOptions:                      options.DeploymentOptions,
+		MetricsHandler:                         metricsHandler,
+		Logger:                                 logger,
+		EnableLoggingInReplay:                  options.EnableLoggingInReplay,
+		BackgroundContext:                      backgroundActivityContext,
+		BackgroundContextCancel:                backgroundActivityContextCancel,
+		StickyScheduleToStartTimeout:           options.StickyScheduleToStartTimeout,
+		TaskQueueActivitiesPerSecond:           options.TaskQueueActivitiesPerSecond,
+		WorkflowPanicPolicy:                    options.WorkflowPanicPolicy,
+		DataConverter:                          client.dataConverter,
+		FailureConverter:                       client.failureConverter,
+		WorkerStopTimeout:                      options.WorkerStopTimeout,
+		WorkerFatalErrorCallback:               fatalErrorCallback,
+		ContextPropagators:                     client.contextPropagators,
+		DeadlockDetectionTimeout:               options.DeadlockDetectionTimeout,
+		DefaultHeartbeatThrottleInterval:       options.DefaultHeartbeatThrottleInterval,
+		MaxHeartbeatThrottleInterval:           options.MaxHeartbeatThrottleInterval,
+		cache:                                  cache,
 		eagerActivityExecutor: newEagerActivityExecutor(eagerActivityExecutorOptions{
 			disabled:      options.DisableEagerActivities,
 			taskQueue:     taskQueue,
@@ -2285,6 +2552,7 @@ func NewAggregatedWorker(client *WorkflowClient, taskQueue string, options Worke
 		}),
 		capabilities:                 &capabilities,
 		pollTimeTracker:              &pollTimeTracker{},
+		deadlockDetections:           &atomic.Int64{},
 		workerInstanceKey:            workerInstanceKey,
 		workerPollCompleteOnShutdown: workerPollCompleteOnShutdown,
 		serverSupportsAutoscaling:    &atomic.Bool{},
@@ -2632,6 +2900,14 @@ func setWorkerOptionsDefaults(options *WorkerOptions) {
 	if options.WorkerActivitiesPerSecond == 0 {
 		options.WorkerActivitiesPerSecond = defaultWorkerActivitiesPerSecond
 	}
+	for activityType, limit := range options.MaxConcurrentActivityTypeExecutionSize {
+		if limit <= 0 {
+			panic(fmt.Sprintf("MaxConcurrentActivityTypeExecutionSize for activity type %q must be positive", activityType))
+		}
+	}
+	if len(options.MaxConcurrentActivityTypeExecutionSize) > 0 && !options.EnableActivityTypeConcurrencyLimits {
+		panic("cannot set MaxConcurrentActivityTypeExecutionSize without EnableActivityTypeConcurrencyLimits")
+	}
 	if options.MaxConcurrentActivityTaskPollers != 0 && options.ActivityTaskPollerBehavior != nil {
 		panic("cannot set both MaxConcurrentActivityTaskPollers and ActivityTaskPollerBehavior")
 	} else if options.ActivityTaskPollerBehavior == nil && options.MaxConcurrentActivityTaskPollers <= 0 {
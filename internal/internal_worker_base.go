@@ -167,6 +167,7 @@ type (
 		taskPoller                   taskPoller
 		pollerAutoscalerReportHandle *pollScalerReportHandle
 		pollerSemaphore              *pollerSemaphore
+		health                       *pollerHealth
 	}
 
 	// baseWorkerOptions options to configure base worker.
@@ -177,6 +178,7 @@ type (
 		taskPollers             []scalableTaskPoller
 		taskProcessor           taskProcessor
 		workerType              string
+		taskType                WorkerTaskType
 		identity                string
 		buildId                 string
 		deploymentOptions       WorkerDeploymentOptions
@@ -188,6 +190,10 @@ type (
 		sessionTokenBucket      *sessionTokenBucket
 		slotReservationData     slotReservationData
 		isInternalWorker        bool
+		// backlogScaler, if set, scales the worker on the backlog of its task queue.
+		backlogScaler *backlogScaler
+		// slotReservationObserver, if set, receives the slot reservation decisions of the worker.
+		slotReservationObserver SlotReservationObserver
 	}
 
 	// baseWorker that wraps worker activities.
@@ -204,6 +210,23 @@ type (
 		logger               log.Logger
 		metricsHandler       metrics.Handler
 
+		// pollersLock guards starting pollers, which may also happen when reconfigured.
+		pollersLock    sync.Mutex
+		pollersStarted bool
+
+		// pausedCh is set while polling is paused and closed when resumed.
+		pauseLock sync.Mutex
+		pausedCh  chan struct{}
+
+		runningTasksLock sync.Mutex
+		runningTasks     map[*SlotPermit]WorkerRunningTask
+		// pollsInProgress counts the slots held by pollTask.
+		pollsInProgress atomic.Int32
+
+		// drainNotify is signaled whenever a slot is released while the worker is drained.
+		drainNotifyLock sync.Mutex
+		drainNotify     chan<- struct{}
+
 		slotSupplier       *trackingSlotSupplier
 		taskQueueCh        chan eagerOrPolledTask
 		eagerTaskQueueCh   chan eagerTask
@@ -244,8 +267,8 @@ type (
 	}
 
 	pollScalerReportHandle struct {
-		minPollerCount            int
-		maxPollerCount            int
+		minPollerCount            atomic.Int64
+		maxPollerCount            atomic.Int64
 		logger                    log.Logger
 		target                    atomic.Int64
 		scaleCallback             func(int)
@@ -338,6 +361,8 @@ func newBaseWorker(
 		metricsHandler: metricsHandler,
 		workerBuildId:  options.buildId,
 		workerIdentity: options.identity,
+		taskType:       options.taskType,
+		observer:       options.slotReservationObserver,
 	})
 	bw := &baseWorker{
 		options:        options,
@@ -359,12 +384,16 @@ func newBaseWorker(
 		limiterContext:       ctx,
 		limiterContextCancel: cancel,
 		sessionTokenBucket:   options.sessionTokenBucket,
+		runningTasks:         make(map[*SlotPermit]WorkerRunningTask),
 	}
 	// Set secondary retrier as resource exhausted
 	bw.retrier.SetSecondaryRetryPolicy(pollResourceExhaustedRetryPolicy)
 	if options.pollerRate > 0 {
 		bw.pollLimiter = rate.NewLimiter(rate.Limit(options.pollerRate), 1)
 	}
+	if options.backlogScaler != nil {
+		options.backlogScaler.scale = bw.scaleForBacklog
+	}
 	// If we have multiple task workers, we need to balance the pollers
 	if len(options.taskPollers) > 1 {
 		bw.pollerBalancer = &pollerBalancer{
@@ -384,6 +413,8 @@ func (bw *baseWorker) Start() {
 
 	bw.metricsHandler.Counter(metrics.WorkerStartCounter).Inc(1)
 
+	bw.pollersLock.Lock()
+	defer bw.pollersLock.Unlock()
 	for _, taskWorker := range bw.options.taskPollers {
 		if bw.pollerBalancer != nil {
 			bw.pollerBalancer.registerPollerType(taskWorker.taskPollerType)
@@ -403,6 +434,14 @@ func (bw *baseWorker) Start() {
 		}
 	}
 
+	if bw.options.backlogScaler != nil {
+		bw.stopWG.Add(1)
+		go func() {
+			defer bw.stopWG.Done()
+			bw.options.backlogScaler.run(bw.stopCh)
+		}()
+	}
+
 	bw.stopWG.Add(1)
 	go bw.runTaskDispatcher()
 
@@ -410,6 +449,7 @@ func (bw *baseWorker) Start() {
 	go bw.runEagerTaskDispatcher()
 
 	bw.isWorkerStarted = true
+	bw.pollersStarted = true
 	traceLog(func() {
 		bw.logger.Info("Started Worker",
 			"MaxTaskPerSecond", bw.options.maxTaskPerSecond,
@@ -417,6 +457,73 @@ func (bw *baseWorker) Start() {
 	})
 }
 
+// checkPollerBehavior returns an error if the pollers of the worker cannot be changed to the given
+// behavior.
+func (bw *baseWorker) checkPollerBehavior(pollerBehavior PollerBehavior) error {
+	for _, taskWorker := range bw.options.taskPollers {
+		if err := checkPollerBehaviorKind(taskWorker.pollerAutoscalerReportHandle != nil, pollerBehavior); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkPollerBehaviorKind returns an error if pollers created with an autoscaling or simple
+// maximum behavior cannot be changed to the given behavior, which must be of the same kind.
+func checkPollerBehaviorKind(autoscaling bool, pollerBehavior PollerBehavior) error {
+	switch p := pollerBehavior.(type) {
+	case *pollerBehaviorSimpleMaximum:
+		if autoscaling {
+			return errors.New("cannot change an autoscaling poller behavior to a simple maximum")
+		}
+	case *pollerBehaviorAutoscaling:
+		if !autoscaling {
+			return errors.New("cannot change a simple maximum poller behavior to autoscaling")
+		}
+		if p.minimumNumberOfPollers > p.maximumNumberOfPollers {
+			return errors.New("MinimumNumberOfPollers must not exceed MaximumNumberOfPollers")
+		}
+	default:
+		return fmt.Errorf("unsupported poller behavior %T", pollerBehavior)
+	}
+	return nil
+}
+
+// setPollerBehavior changes the number of pollers of the worker, starting more pollers if
+// needed. Pollers beyond a reduced maximum stop polling once their current poll completes. The
+// behavior must have been checked with checkPollerBehavior.
+func (bw *baseWorker) setPollerBehavior(pollerBehavior PollerBehavior) {
+	bw.pollersLock.Lock()
+	defer bw.pollersLock.Unlock()
+	for i := range bw.options.taskPollers {
+		taskWorker := &bw.options.taskPollers[i]
+		var maxPollers int
+		switch p := pollerBehavior.(type) {
+		case *pollerBehaviorSimpleMaximum:
+			maxPollers = p.maximumNumberOfPollers
+			taskWorker.pollerSemaphore.updatePermits(maxPollers)
+		case *pollerBehaviorAutoscaling:
+			maxPollers = p.maximumNumberOfPollers
+			taskWorker.pollerAutoscalerReportHandle.setPollerCountBounds(p.minimumNumberOfPollers, maxPollers)
+		}
+		if maxPollers <= taskWorker.pollerCount {
+			continue
+		}
+		if bw.pollersStarted && !bw.isStop() {
+			for j := taskWorker.pollerCount; j < maxPollers; j++ {
+				bw.stopWG.Add(1)
+				go bw.runPoller(*taskWorker)
+			}
+		}
+		taskWorker.pollerCount = maxPollers
+	}
+}
+
+// setMaxTaskPerSecond changes the rate limit on tasks processed by the worker.
+func (bw *baseWorker) setMaxTaskPerSecond(maxTaskPerSecond float64) {
+	bw.taskLimiter.SetLimit(rate.Limit(maxTaskPerSecond))
+}
+
 func (bw *baseWorker) isStop() bool {
 	select {
 	case <-bw.stopCh:
@@ -440,6 +547,9 @@ func (bw *baseWorker) runPoller(taskWorker scalableTaskPoller) {
 			if bw.noRepoll.Load() {
 				return true
 			}
+			if !bw.waitIfPaused() {
+				return true
+			}
 			if taskWorker.pollerSemaphore != nil {
 				if taskWorker.pollerSemaphore.acquire(bw.limiterContext) != nil {
 					return true
@@ -486,6 +596,11 @@ func (bw *baseWorker) runPoller(taskWorker scalableTaskPoller) {
 					}
 					return false
 				}
+				if bw.isPaused() {
+					// Paused while reserving, don't start another poll
+					bw.releaseSlot(permit, SlotReleaseReasonUnused)
+					return false
+				}
 				if bw.sessionTokenBucket != nil {
 					bw.sessionTokenBucket.waitForAvailableToken()
 				}
@@ -505,7 +620,7 @@ func (bw *baseWorker) runPoller(taskWorker scalableTaskPoller) {
 }
 
 func (bw *baseWorker) tryReserveSlot() *SlotPermit {
-	if bw.isStop() {
+	if bw.isStop() || bw.isPaused() {
 		return nil
 	}
 	return bw.slotSupplier.TryReserveSlot(&bw.options.slotReservationData)
@@ -513,6 +628,7 @@ func (bw *baseWorker) tryReserveSlot() *SlotPermit {
 
 func (bw *baseWorker) releaseSlot(permit *SlotPermit, reason SlotReleaseReason) {
 	bw.slotSupplier.ReleaseSlot(permit, reason)
+	bw.notifyDrain()
 }
 
 func (bw *baseWorker) pushEagerTask(task eagerTask) {
@@ -533,10 +649,16 @@ func (bw *baseWorker) processTaskAsync(eagerOrPolled eagerOrPolledTask) {
 		permit := eagerOrPolled.getPermit()
 
 		if !task.isEmpty() {
-			bw.slotSupplier.MarkSlotUsed(permit)
+			var taskInfo SlotTaskInfo
+			if provider, ok := task.(slotTaskInfoProvider); ok {
+				taskInfo = provider.slotTaskInfo()
+			}
+			bw.slotSupplier.MarkSlotUsed(permit, taskInfo)
+			bw.addRunningTask(permit, task)
 		}
 
 		defer func() {
+			bw.removeRunningTask(permit)
 			bw.releaseSlot(permit, SlotReleaseReasonTaskProcessed)
 
 			if p := recover(); p != nil {
@@ -603,7 +725,12 @@ func (bw *baseWorker) pollTask(taskWorker scalableTaskPoller, slotPermit *SlotPe
 	var err error
 	var task taskForWorker
 	didSendTask := false
+	bw.pollsInProgress.Add(1)
+	// A worker paused while reserving the slot may be drained, which waits for slots held outside
+	// of polls.
+	bw.notifyDrain()
 	defer func() {
+		bw.pollsInProgress.Add(-1)
 		if !didSendTask {
 			bw.releaseSlot(slotPermit, SlotReleaseReasonUnused)
 		}
@@ -611,7 +738,9 @@ func (bw *baseWorker) pollTask(taskWorker scalableTaskPoller, slotPermit *SlotPe
 
 	bw.retrier.Throttle(bw.stopCh)
 	if bw.pollLimiter == nil || bw.pollLimiter.Wait(bw.limiterContext) == nil {
+		taskWorker.health.pollStarted()
 		task, err = taskWorker.taskPoller.PollTask()
+		taskWorker.health.pollCompleted(err, bw.isStop())
 		bw.logPollTaskError(err)
 		if err != nil {
 			// We retry "non retriable" errors while long polling for a while, because some proxies return
@@ -727,12 +856,12 @@ func newPollScalerReportHandle(options pollScalerReportHandleOptions) *pollScale
 		serverSupportsAutoscaling = &atomic.Bool{}
 	}
 	psr := &pollScalerReportHandle{
-		maxPollerCount:            options.maxPollerCount,
-		minPollerCount:            options.minPollerCount,
 		logger:                    logger,
 		scaleCallback:             options.scaleCallback,
 		serverSupportsAutoscaling: serverSupportsAutoscaling,
 	}
+	psr.minPollerCount.Store(int64(options.minPollerCount))
+	psr.maxPollerCount.Store(int64(options.maxPollerCount))
 	psr.target.Store(int64(options.initialPollerCount))
 	return psr
 }
@@ -769,20 +898,10 @@ func (prh *pollScalerReportHandle) handleTask(task taskForWorker) {
 
 func (prh *pollScalerReportHandle) updateTarget(f func(int64) int64) {
 	target := prh.target.Load()
-	newTarget := f(target)
-	if newTarget < int64(prh.minPollerCount) {
-		newTarget = int64(prh.minPollerCount)
-	} else if newTarget > int64(prh.maxPollerCount) {
-		newTarget = int64(prh.maxPollerCount)
-	}
+	newTarget := prh.clampTarget(f(target))
 	for !prh.target.CompareAndSwap(target, newTarget) {
 		target = prh.target.Load()
-		newTarget = f(target)
-		if newTarget < int64(prh.minPollerCount) {
-			newTarget = int64(prh.minPollerCount)
-		} else if newTarget > int64(prh.maxPollerCount) {
-			newTarget = int64(prh.maxPollerCount)
-		}
+		newTarget = prh.clampTarget(f(target))
 	}
 	permits := int(newTarget)
 	if prh.scaleCallback != nil {
@@ -793,6 +912,23 @@ func (prh *pollScalerReportHandle) updateTarget(f func(int64) int64) {
 	}
 }
 
+func (prh *pollScalerReportHandle) clampTarget(target int64) int64 {
+	if minPollerCount := prh.minPollerCount.Load(); target < minPollerCount {
+		return minPollerCount
+	} else if maxPollerCount := prh.maxPollerCount.Load(); target > maxPollerCount {
+		return maxPollerCount
+	}
+	return target
+}
+
+// setPollerCountBounds changes the minimum and maximum number of pollers, moving the current
+// target within them.
+func (prh *pollScalerReportHandle) setPollerCountBounds(minPollerCount, maxPollerCount int) {
+	prh.minPollerCount.Store(int64(minPollerCount))
+	prh.maxPollerCount.Store(int64(maxPollerCount))
+	prh.updateTarget(func(target int64) int64 { return target })
+}
+
 func (prh *pollScalerReportHandle) handleError(err error) {
 	// If we have never seen a scaling decision and the server doesn't support
 	// poller autoscaling, we don't want to scale down on errors, because we
@@ -883,6 +1019,11 @@ func (ps *pollerSemaphore) release() {
 func (ps *pollerSemaphore) updatePermits(maxPermits int) {
 	// Acquire barrier.
 	b := <-ps.bs
+	if maxPermits > ps.maxPermits {
+		// Release all waiters, they may now be able to acquire a permit.
+		close(b)
+		b = make(barrier)
+	}
 	ps.maxPermits = maxPermits
 	// Release barrier.
 	ps.bs <- b
@@ -898,6 +1039,7 @@ func newScalableTaskPoller(
 	tw := scalableTaskPoller{
 		taskPoller:     poller,
 		taskPollerType: taskPollerType,
+		health:         &pollerHealth{},
 	}
 	switch p := pollerBehavior.(type) {
 	case *pollerBehaviorAutoscaling:
@@ -915,6 +1057,8 @@ func newScalableTaskPoller(
 		})
 	case *pollerBehaviorSimpleMaximum:
 		tw.pollerCount = p.maximumNumberOfPollers
+		// All pollers may poll, the semaphore is only used to reduce their number on reconfiguration.
+		tw.pollerSemaphore = newPollerSemaphore(p.maximumNumberOfPollers)
 	}
 	return tw
 }
@@ -237,6 +237,43 @@ func (s *ScalableTaskPollerSuite) TestAutoscalingScalesDownToMinimum() {
 	}, 200*time.Millisecond, 10*time.Millisecond, "should not scale below minimum")
 }
 
+func (s *ScalableTaskPollerSuite) TestSimpleMaximumReconfigured() {
+	behavior := NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 2})
+
+	blockingPoller := newSemaphoreProbeTaskPoller()
+	poller := newScalableTaskPoller(blockingPoller, ilog.NewNopLogger(), behavior, "", nil)
+
+	bw := newBaseWorker(baseWorkerOptions{
+		slotSupplier:     &testSlotSupplier{},
+		maxTaskPerSecond: 1000,
+		taskPollers:      []scalableTaskPoller{poller},
+		taskProcessor:    noopTaskProcessor{},
+		workerType:       "ReconfigureTest",
+		logger:           ilog.NewNopLogger(),
+		stopTimeout:      time.Second,
+		metricsHandler:   metrics.NopHandler,
+	})
+
+	bw.Start()
+	defer func() {
+		allowBlockedPollers(blockingPoller, poller.pollerSemaphore)
+		blockingPoller.Close()
+		bw.Stop()
+	}()
+
+	eventuallySemaphoreState(s.T(), blockingPoller, poller.pollerSemaphore, 2, 2, "expected initial pollers")
+
+	s.NoError(bw.checkPollerBehavior(NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 4})))
+	bw.setPollerBehavior(NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 4}))
+	eventuallySemaphoreState(s.T(), blockingPoller, poller.pollerSemaphore, 4, 4, "expected additional pollers to start")
+
+	bw.setPollerBehavior(NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 1}))
+	eventuallySemaphoreState(s.T(), blockingPoller, poller.pollerSemaphore, 1, 1, "expected pollers to reduce")
+
+	s.EqualError(bw.checkPollerBehavior(NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{})),
+		"cannot change a simple maximum poller behavior to autoscaling")
+}
+
 type semaphoreProbeTaskPoller struct {
 	signals chan struct{}
 	done    chan struct{}
@@ -130,6 +130,14 @@ func (wc *WorkerCache) removeWorkflowContext(runID string) {
 	(*wc.sharedCache.workflowCache).Delete(runID)
 }
 
+// workflowCacheSize returns the number of workflows in the sticky cache.
+func (wc *WorkerCache) workflowCacheSize() int {
+	if wc == nil || wc.sharedCache.workflowCache == nil {
+		return 0
+	}
+	return (*wc.sharedCache.workflowCache).Size()
+}
+
 // MaxWorkflowCacheSize returns the maximum allowed size of the sticky cache
 func (wc *WorkerCache) MaxWorkflowCacheSize() int {
 	if wc == nil {
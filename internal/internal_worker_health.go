@@ -0,0 +1,205 @@
+package internal
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+// WorkerHealthSnapshot describes the health of a worker at a point in time.
+//
+// Exposed as: [go.temporal.io/sdk/worker.HealthSnapshot]
+//
+// NOTE: Experimental
+type WorkerHealthSnapshot struct {
+	// Running is whether the worker is started and not stopped.
+	Running bool
+	// ShuttingDown is whether the worker is stopping.
+	ShuttingDown bool
+	// FatalError is the error the worker stopped on, if any. See WorkerOptions.OnFatalError.
+	FatalError error
+	// Pollers describes the pollers of the worker, one per kind of poll. Session activities are
+	// not included.
+	Pollers []WorkerPollerHealth
+	// Slots describes the slots of the worker, one per task type. Session activities are not
+	// included.
+	Slots []WorkerSlotHealth
+	// StickyCacheSize is the number of workflows in the sticky cache. The cache is shared by the
+	// workers of the process.
+	StickyCacheSize int
+	// StickyCacheCapacity is the maximum number of workflows in the sticky cache.
+	StickyCacheCapacity int
+	// DeadlockDetections is the number of workflow tasks of the worker that failed because a
+	// workflow goroutine did not yield within the deadlock detection timeout.
+	DeadlockDetections int64
+}
+
+// WorkerPollerHealth describes the pollers of a worker for one kind of poll.
+//
+// Exposed as: [go.temporal.io/sdk/worker.PollerHealth]
+//
+// NOTE: Experimental
+type WorkerPollerHealth struct {
+	TaskType WorkerTaskType
+	// Sticky is whether the pollers poll the sticky task queue of the worker.
+	Sticky bool
+	// Autoscaling is whether the number of pollers is autoscaled.
+	Autoscaling bool
+	// Paused is whether polling is paused, see [go.temporal.io/sdk/worker.Pause].
+	Paused bool
+	// CurrentPolls is the number of polls in progress.
+	CurrentPolls int
+	// LastSuccessfulPollTime is when a poll last received a task, zero if none has.
+	LastSuccessfulPollTime time.Time
+	// Failing is whether the last completed poll failed.
+	Failing bool
+	// LastPollError is the error of the last failed poll, if any.
+	LastPollError error
+	// LastPollErrorTime is when a poll last failed, zero if none has.
+	LastPollErrorTime time.Time
+}
+
+// WorkerSlotHealth describes the slots of a worker for one task type.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotHealth]
+//
+// NOTE: Experimental
+type WorkerSlotHealth struct {
+	TaskType WorkerTaskType
+	// SlotSupplierKind is the kind of SlotSupplier issuing the slots, such as "Fixed",
+	// "ResourceBased" or "Custom".
+	SlotSupplierKind string
+	// IssuedSlots is the number of slots issued, including those reserved by pollers waiting for
+	// a task.
+	IssuedSlots int
+	// UsedSlots is the number of issued slots that are running tasks.
+	UsedSlots int
+	// MaxSlots is the maximum number of slots of the SlotSupplier, or 0 if it has none.
+	MaxSlots int
+	// ExhaustedSince is when all MaxSlots slots were issued, zero while some are available. New
+	// tasks are not polled for while slots are exhausted.
+	ExhaustedSince time.Time
+}
+
+// pollerHealth tracks the polls of a scalableTaskPoller for health snapshots. The methods may be
+// called on a nil pollerHealth.
+type pollerHealth struct {
+	currentPolls atomic.Int32
+
+	lock        sync.Mutex
+	failing     bool
+	lastErr     error
+	lastErrTime time.Time
+}
+
+func (ph *pollerHealth) pollStarted() {
+	if ph != nil {
+		ph.currentPolls.Add(1)
+	}
+}
+
+// pollCompleted records the result of a poll, which is not recorded when the worker is stopping.
+func (ph *pollerHealth) pollCompleted(err error, stopping bool) {
+	if ph == nil {
+		return
+	}
+	ph.currentPolls.Add(-1)
+	if stopping {
+		return
+	}
+	ph.lock.Lock()
+	defer ph.lock.Unlock()
+	ph.failing = err != nil
+	if err != nil {
+		ph.lastErr = err
+		ph.lastErrTime = time.Now()
+	}
+}
+
+// pollersHealth returns the health of the pollers of the worker.
+func (bw *baseWorker) pollersHealth(taskType WorkerTaskType, tracker *pollTimeTracker) []WorkerPollerHealth {
+	paused := bw.isPaused()
+	var result []WorkerPollerHealth
+	for i := range bw.options.taskPollers {
+		// Only read fields that are not changed by reconfiguration
+		taskWorker := &bw.options.taskPollers[i]
+		health := WorkerPollerHealth{
+			TaskType:    taskType,
+			Sticky:      taskWorker.taskPollerType == metrics.PollerTypeWorkflowStickyTask,
+			Autoscaling: taskWorker.pollerAutoscalerReportHandle != nil,
+			Paused:      paused,
+		}
+		if tracker != nil {
+			health.LastSuccessfulPollTime = tracker.getLastPollTime(taskWorker.taskPollerType)
+		}
+		if ph := taskWorker.health; ph != nil {
+			health.CurrentPolls = int(ph.currentPolls.Load())
+			ph.lock.Lock()
+			health.Failing = ph.failing
+			health.LastPollError = ph.lastErr
+			health.LastPollErrorTime = ph.lastErrTime
+			ph.lock.Unlock()
+		}
+		result = append(result, health)
+	}
+	return result
+}
+
+// health returns the health of the slots of the supplier.
+func (t *trackingSlotSupplier) health() WorkerSlotHealth {
+	t.slotsMutex.Lock()
+	usedSlots := len(t.usedSlots)
+	exhaustedSince := t.atMaxSince
+	t.slotsMutex.Unlock()
+	return WorkerSlotHealth{
+		TaskType:         t.taskType,
+		SlotSupplierKind: t.GetSlotSupplierKind(),
+		IssuedSlots:      int(t.issuedSlotsAtomic.Load()),
+		UsedSlots:        usedSlots,
+		MaxSlots:         t.inner.MaxSlots(),
+		ExhaustedSince:   exhaustedSince,
+	}
+}
+
+// Health returns a snapshot of the health of the worker.
+func (aw *AggregatedWorker) Health() WorkerHealthSnapshot {
+	snapshot := WorkerHealthSnapshot{
+		Running:      aw.started.Load(),
+		ShuttingDown: aw.shuttingDown.Load(),
+	}
+	select {
+	case <-aw.stopC:
+		snapshot.Running = false
+	default:
+	}
+	aw.fatalErrLock.Lock()
+	snapshot.FatalError = aw.fatalErr
+	aw.fatalErrLock.Unlock()
+
+	tracker := aw.executionParams.pollTimeTracker
+	if aw.workflowWorker != nil {
+		snapshot.Pollers = append(snapshot.Pollers, aw.workflowWorker.worker.pollersHealth(WorkerTaskTypeWorkflow, tracker)...)
+		snapshot.Slots = append(snapshot.Slots,
+			aw.workflowWorker.worker.slotSupplier.health(),
+			aw.workflowWorker.localActivityWorker.slotSupplier.health())
+		snapshot.StickyCacheSize = aw.executionParams.cache.workflowCacheSize()
+		snapshot.StickyCacheCapacity = aw.executionParams.cache.MaxWorkflowCacheSize()
+	}
+	if aw.activityWorker != nil {
+		snapshot.Pollers = append(snapshot.Pollers, aw.activityWorker.worker.pollersHealth(WorkerTaskTypeActivity, tracker)...)
+		snapshot.Slots = append(snapshot.Slots, aw.activityWorker.worker.slotSupplier.health())
+	}
+	aw.reconfigureLock.Lock()
+	nexusWorker := aw.nexusWorker
+	aw.reconfigureLock.Unlock()
+	if nexusWorker != nil {
+		snapshot.Pollers = append(snapshot.Pollers, nexusWorker.worker.pollersHealth(WorkerTaskTypeNexus, tracker)...)
+		snapshot.Slots = append(snapshot.Slots, nexusWorker.worker.slotSupplier.health())
+	}
+	if aw.executionParams.deadlockDetections != nil {
+		snapshot.DeadlockDetections = aw.executionParams.deadlockDetections.Load()
+	}
+	return snapshot
+}
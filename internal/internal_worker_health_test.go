@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+// failingTestPoller fails every poll after a few milliseconds.
+type failingTestPoller struct{}
+
+func (failingTestPoller) PollTask() (taskForWorker, error) {
+	time.Sleep(5 * time.Millisecond)
+	return nil, errors.New("poll failed")
+}
+
+func TestWorkerHealth(t *testing.T) {
+	bw := newBaseWorker(baseWorkerOptions{
+		slotSupplier:     &testSlotSupplier{},
+		maxTaskPerSecond: 1000,
+		taskPollers: []scalableTaskPoller{newScalableTaskPoller(failingTestPoller{}, nil,
+			NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 1}),
+			metrics.PollerTypeActivityTask, nil)},
+		taskProcessor:  noopTaskProcessor{},
+		workerType:     "ActivityWorker",
+		taskType:       WorkerTaskTypeActivity,
+		logger:         getLogger(),
+		stopTimeout:    time.Second,
+		metricsHandler: metrics.NopHandler,
+	})
+	tracker := &pollTimeTracker{}
+	tracker.recordPollSuccess(metrics.PollerTypeActivityTask)
+	deadlockDetections := &atomic.Int64{}
+	aw := &AggregatedWorker{
+		activityWorker: &activityWorker{worker: bw},
+		logger:         getLogger(),
+		stopC:          make(chan struct{}),
+		executionParams: workerExecutionParameters{
+			pollTimeTracker:    tracker,
+			deadlockDetections: deadlockDetections,
+		},
+	}
+	aw.started.Store(true)
+	bw.Start()
+	t.Cleanup(bw.Stop)
+
+	require.Eventually(t, func() bool {
+		return len(aw.Health().Pollers) == 1 && aw.Health().Pollers[0].Failing
+	}, time.Second, time.Millisecond)
+	health := aw.Health()
+	require.True(t, health.Running)
+	require.NoError(t, health.FatalError)
+	poller := health.Pollers[0]
+	require.Equal(t, WorkerTaskTypeActivity, poller.TaskType)
+	require.False(t, poller.Sticky)
+	require.EqualError(t, poller.LastPollError, "poll failed")
+	require.False(t, poller.LastPollErrorTime.IsZero())
+	require.Equal(t, tracker.getLastPollTime(metrics.PollerTypeActivityTask), poller.LastSuccessfulPollTime)
+	require.Equal(t, []WorkerSlotHealth{{
+		TaskType:         WorkerTaskTypeActivity,
+		SlotSupplierKind: "Custom",
+		IssuedSlots:      health.Slots[0].IssuedSlots,
+	}}, health.Slots)
+
+	// Potential deadlocks are counted when they complete the workflow.
+	wec := &workflowExecutionContextImpl{wth: &workflowTaskHandlerImpl{deadlockDetections: deadlockDetections}}
+	wec.completeWorkflow(nil, &workflowPanicError{value: "panic"})
+	wec.completeWorkflow(nil, &workflowPanicError{value: "deadlock", potentialDeadlock: true})
+	require.Equal(t, int64(1), aw.Health().DeadlockDetections)
+}
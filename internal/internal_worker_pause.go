@@ -0,0 +1,381 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.temporal.io/sdk/internal/common/util"
+)
+
+// WorkerTaskType is a kind of task run by a worker.
+//
+// Exposed as: [go.temporal.io/sdk/worker.TaskType]
+//
+// NOTE: Experimental
+type WorkerTaskType int
+
+const (
+	// WorkerTaskTypeWorkflow is the type of workflow tasks.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.TaskTypeWorkflow]
+	WorkerTaskTypeWorkflow WorkerTaskType = iota + 1
+	// WorkerTaskTypeActivity is the type of activity tasks, including the activities of sessions.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.TaskTypeActivity]
+	WorkerTaskTypeActivity
+	// WorkerTaskTypeLocalActivity is the type of local activities. They are not polled for, so
+	// they cannot be paused, and are started only by running workflow tasks.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.TaskTypeLocalActivity]
+	WorkerTaskTypeLocalActivity
+	// WorkerTaskTypeNexus is the type of nexus tasks.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.TaskTypeNexus]
+	WorkerTaskTypeNexus
+)
+
+func (t WorkerTaskType) String() string {
+	switch t {
+	case WorkerTaskTypeWorkflow:
+		return "Workflow"
+	case WorkerTaskTypeActivity:
+		return "Activity"
+	case WorkerTaskTypeLocalActivity:
+		return "LocalActivity"
+	case WorkerTaskTypeNexus:
+		return "Nexus"
+	default:
+		return fmt.Sprintf("WorkerTaskType(%d)", int(t))
+	}
+}
+
+// WorkerRunningTask describes a task that a worker is running. Fields that do not apply to the
+// type of task are empty.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RunningTask]
+//
+// NOTE: Experimental
+type WorkerRunningTask struct {
+	TaskType     WorkerTaskType
+	WorkflowType string
+	WorkflowID   string
+	RunID        string
+	ActivityType string
+	ActivityID   string
+	// StartTime is when the worker started running the task.
+	StartTime time.Time
+}
+
+// WorkerDrainResult describes the work that a worker was still doing when Drain returned.
+//
+// Exposed as: [go.temporal.io/sdk/worker.DrainResult]
+//
+// NOTE: Experimental
+type WorkerDrainResult struct {
+	// RunningTasks are the tasks that were still running, oldest first.
+	RunningTasks []WorkerRunningTask
+	// PendingPolls is the number of polls that were in progress. Drain does not wait for them, since a
+	// long poll that gets no task only completes after about a minute. Polls are not interrupted, and
+	// a task they deliver after Drain returns is run. Stopping the worker completes them.
+	PendingPolls int
+}
+
+// runningTaskDescriber is implemented by tasks that can describe themselves while running.
+type runningTaskDescriber interface {
+	describeRunningTask() WorkerRunningTask
+}
+
+func (wft *workflowTask) describeRunningTask() WorkerRunningTask {
+	return WorkerRunningTask{
+		TaskType:     WorkerTaskTypeWorkflow,
+		WorkflowType: wft.task.GetWorkflowType().GetName(),
+		WorkflowID:   wft.task.GetWorkflowExecution().GetWorkflowId(),
+		RunID:        wft.task.GetWorkflowExecution().GetRunId(),
+	}
+}
+
+func (ewt *eagerWorkflowTask) describeRunningTask() WorkerRunningTask {
+	return WorkerRunningTask{
+		TaskType:     WorkerTaskTypeWorkflow,
+		WorkflowType: ewt.task.GetWorkflowType().GetName(),
+		WorkflowID:   ewt.task.GetWorkflowExecution().GetWorkflowId(),
+		RunID:        ewt.task.GetWorkflowExecution().GetRunId(),
+	}
+}
+
+func (at *activityTask) describeRunningTask() WorkerRunningTask {
+	return WorkerRunningTask{
+		TaskType:     WorkerTaskTypeActivity,
+		WorkflowType: at.task.GetWorkflowType().GetName(),
+		WorkflowID:   at.task.GetWorkflowExecution().GetWorkflowId(),
+		RunID:        at.task.GetWorkflowExecution().GetRunId(),
+		ActivityType: at.task.GetActivityType().GetName(),
+		ActivityID:   at.task.GetActivityId(),
+	}
+}
+
+func (lat *localActivityTask) describeRunningTask() WorkerRunningTask {
+	task := WorkerRunningTask{
+		TaskType:     WorkerTaskTypeLocalActivity,
+		ActivityType: lat.params.ActivityType,
+		ActivityID:   lat.activityID,
+	}
+	if info := lat.params.WorkflowInfo; info != nil {
+		task.WorkflowType = info.WorkflowType.Name
+		task.WorkflowID = info.WorkflowExecution.ID
+		task.RunID = info.WorkflowExecution.RunID
+	}
+	return task
+}
+
+func (nt *nexusTask) describeRunningTask() WorkerRunningTask {
+	return WorkerRunningTask{TaskType: WorkerTaskTypeNexus}
+}
+
+// pause stops the pollers of the worker from starting new polls until resumed. Polls in
+// progress are not interrupted.
+func (bw *baseWorker) pause() {
+	bw.pauseLock.Lock()
+	defer bw.pauseLock.Unlock()
+	if bw.pausedCh == nil {
+		bw.pausedCh = make(chan struct{})
+	}
+}
+
+// resume lets the pollers of a paused worker poll again.
+func (bw *baseWorker) resume() {
+	bw.pauseLock.Lock()
+	defer bw.pauseLock.Unlock()
+	if bw.pausedCh != nil {
+		close(bw.pausedCh)
+		bw.pausedCh = nil
+	}
+}
+
+func (bw *baseWorker) isPaused() bool {
+	bw.pauseLock.Lock()
+	defer bw.pauseLock.Unlock()
+	return bw.pausedCh != nil
+}
+
+// waitIfPaused blocks while the worker is paused, returning false if the worker was stopped.
+func (bw *baseWorker) waitIfPaused() bool {
+	bw.pauseLock.Lock()
+	pausedCh := bw.pausedCh
+	bw.pauseLock.Unlock()
+	if pausedCh == nil {
+		return true
+	}
+	select {
+	case <-pausedCh:
+		return true
+	case <-bw.stopCh:
+		return false
+	}
+}
+
+func (bw *baseWorker) addRunningTask(permit *SlotPermit, task taskForWorker) {
+	var running WorkerRunningTask
+	if describer, ok := task.(runningTaskDescriber); ok {
+		running = describer.describeRunningTask()
+	}
+	running.StartTime = time.Now()
+	bw.runningTasksLock.Lock()
+	defer bw.runningTasksLock.Unlock()
+	bw.runningTasks[permit] = running
+}
+
+func (bw *baseWorker) removeRunningTask(permit *SlotPermit) {
+	bw.runningTasksLock.Lock()
+	defer bw.runningTasksLock.Unlock()
+	delete(bw.runningTasks, permit)
+}
+
+// getRunningTasks returns the tasks the worker is running, the number of polls in progress and the
+// number of slots that are held for other reasons, which are mostly tasks that were polled and
+// have not started running yet.
+func (bw *baseWorker) getRunningTasks() (tasks []WorkerRunningTask, polls int, otherSlots int) {
+	bw.runningTasksLock.Lock()
+	defer bw.runningTasksLock.Unlock()
+	for _, task := range bw.runningTasks {
+		tasks = append(tasks, task)
+	}
+	polls = int(bw.pollsInProgress.Load())
+	otherSlots = int(bw.slotSupplier.issuedSlotsAtomic.Load()) - len(tasks) - polls
+	if otherSlots < 0 {
+		otherSlots = 0
+	}
+	return tasks, polls, otherSlots
+}
+
+// setDrainNotify sets the channel signaled whenever the worker releases a slot or starts a poll,
+// or clears it if nil.
+func (bw *baseWorker) setDrainNotify(notify chan<- struct{}) {
+	bw.drainNotifyLock.Lock()
+	defer bw.drainNotifyLock.Unlock()
+	bw.drainNotify = notify
+}
+
+func (bw *baseWorker) notifyDrain() {
+	bw.drainNotifyLock.Lock()
+	defer bw.drainNotifyLock.Unlock()
+	if bw.drainNotify != nil {
+		select {
+		case bw.drainNotify <- struct{}{}:
+		default:
+		}
+	}
+}
+
+// pausableWorkers returns the base workers that poll for the given task type.
+func (aw *AggregatedWorker) pausableWorkers(taskType WorkerTaskType) ([]*baseWorker, error) {
+	var workers []*baseWorker
+	switch taskType {
+	case WorkerTaskTypeWorkflow:
+		if !util.IsInterfaceNil(aw.workflowWorker) {
+			workers = append(workers, aw.workflowWorker.worker)
+		}
+	case WorkerTaskTypeActivity:
+		if !util.IsInterfaceNil(aw.activityWorker) {
+			workers = append(workers, aw.activityWorker.worker)
+		}
+		if !util.IsInterfaceNil(aw.sessionWorker) {
+			workers = append(workers, aw.sessionWorker.creationWorker.worker, aw.sessionWorker.activityWorker.worker)
+		}
+	case WorkerTaskTypeNexus:
+		if !util.IsInterfaceNil(aw.nexusWorker) {
+			workers = append(workers, aw.nexusWorker.worker)
+		}
+	case WorkerTaskTypeLocalActivity:
+		return nil, errors.New("local activities cannot be paused, pause workflow tasks instead")
+	default:
+		return nil, fmt.Errorf("unknown task type %v", taskType)
+	}
+	return workers, nil
+}
+
+// setPaused pauses or resumes polling for the given task types, or all of them if none are
+// given. The state of nexus polling is kept until the nexus worker is created on Start.
+func (aw *AggregatedWorker) setPaused(paused bool, taskTypes []WorkerTaskType) error {
+	if len(taskTypes) == 0 {
+		taskTypes = []WorkerTaskType{WorkerTaskTypeWorkflow, WorkerTaskTypeActivity, WorkerTaskTypeNexus}
+	}
+	aw.reconfigureLock.Lock()
+	defer aw.reconfigureLock.Unlock()
+	var workers []*baseWorker
+	for _, taskType := range taskTypes {
+		w, err := aw.pausableWorkers(taskType)
+		if err != nil {
+			return err
+		}
+		workers = append(workers, w...)
+	}
+	for _, taskType := range taskTypes {
+		if taskType == WorkerTaskTypeNexus {
+			aw.nexusPaused = paused
+		}
+	}
+	for _, w := range workers {
+		if paused {
+			w.pause()
+		} else {
+			w.resume()
+		}
+	}
+	return nil
+}
+
+// Pause stops polling for the given task types, or all of them if none are given. Tasks that are
+// running, and tasks delivered by polls already in progress, are run to completion, and cached
+// workflows are kept so that they can continue on this worker once resumed.
+func (aw *AggregatedWorker) Pause(taskTypes ...WorkerTaskType) error {
+	if err := aw.setPaused(true, taskTypes); err != nil {
+		return err
+	}
+	aw.logger.Info("Paused Worker", "TaskTypes", taskTypes)
+	return nil
+}
+
+// Resume starts polling again for the given task types, or all of them if none are given.
+func (aw *AggregatedWorker) Resume(taskTypes ...WorkerTaskType) error {
+	if err := aw.setPaused(false, taskTypes); err != nil {
+		return err
+	}
+	aw.logger.Info("Resumed Worker", "TaskTypes", taskTypes)
+	return nil
+}
+
+// Drain pauses polling for all task types and waits until the tasks that are running have
+// completed. Polls in progress are not waited for, see WorkerDrainResult.PendingPolls. If the
+// context is done first, it returns the context error along with the work that was still in
+// progress. The worker stays paused, it is usually stopped next.
+func (aw *AggregatedWorker) Drain(ctx context.Context) (WorkerDrainResult, error) {
+	if err := aw.Pause(); err != nil {
+		return WorkerDrainResult{}, err
+	}
+	// Workers signal every released slot and started poll, so the work in progress is only checked
+	// when it changes.
+	notify := make(chan struct{}, 1)
+	for _, w := range aw.drainableWorkers() {
+		w.setDrainNotify(notify)
+		defer w.setDrainNotify(nil)
+	}
+	for {
+		result, unstartedTasks := aw.drainResult()
+		if len(result.RunningTasks) == 0 && unstartedTasks == 0 {
+			aw.logger.Info("Drained Worker")
+			return result, nil
+		}
+		select {
+		case <-notify:
+		case <-ctx.Done():
+			aw.logger.Warn("Worker drain did not complete",
+				"RunningTasks", len(result.RunningTasks), "PendingPolls", result.PendingPolls)
+			return result, ctx.Err()
+		}
+	}
+}
+
+// drainableWorkers returns the base workers whose work Drain waits for.
+func (aw *AggregatedWorker) drainableWorkers() []*baseWorker {
+	aw.reconfigureLock.Lock()
+	defer aw.reconfigureLock.Unlock()
+	var workers []*baseWorker
+	for _, taskType := range []WorkerTaskType{WorkerTaskTypeWorkflow, WorkerTaskTypeActivity, WorkerTaskTypeNexus} {
+		w, _ := aw.pausableWorkers(taskType)
+		workers = append(workers, w...)
+	}
+	if !util.IsInterfaceNil(aw.workflowWorker) {
+		workers = append(workers, aw.workflowWorker.localActivityWorker)
+	}
+	return workers
+}
+
+// drainResult returns the work in progress, and the number of slots held by tasks that were
+// polled and have not started running yet, which Drain waits for too.
+func (aw *AggregatedWorker) drainResult() (result WorkerDrainResult, unstartedTasks int) {
+	aw.reconfigureLock.Lock()
+	defer aw.reconfigureLock.Unlock()
+	for _, taskType := range []WorkerTaskType{WorkerTaskTypeWorkflow, WorkerTaskTypeActivity, WorkerTaskTypeNexus} {
+		workers, _ := aw.pausableWorkers(taskType)
+		for _, w := range workers {
+			tasks, polls, otherSlots := w.getRunningTasks()
+			result.RunningTasks = append(result.RunningTasks, tasks...)
+			result.PendingPolls += polls
+			unstartedTasks += otherSlots
+		}
+	}
+	if !util.IsInterfaceNil(aw.workflowWorker) {
+		// Local activity pollers hold slots while waiting for local activities, so only the
+		// running ones count.
+		tasks, _, _ := aw.workflowWorker.localActivityWorker.getRunningTasks()
+		result.RunningTasks = append(result.RunningTasks, tasks...)
+	}
+	sort.SliceStable(result.RunningTasks, func(i, j int) bool {
+		return result.RunningTasks[i].StartTime.Before(result.RunningTasks[j].StartTime)
+	})
+	return result, unstartedTasks
+}
@@ -0,0 +1,160 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	commonpb "go.temporal.io/api/common/v1"
+	"go.temporal.io/api/workflowservice/v1"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+// pauseTestPoller delivers the tasks sent to it, polling again every few milliseconds otherwise.
+type pauseTestPoller struct {
+	tasks chan taskForWorker
+	polls atomic.Int32
+}
+
+func (p *pauseTestPoller) PollTask() (taskForWorker, error) {
+	p.polls.Add(1)
+	select {
+	case task := <-p.tasks:
+		return task, nil
+	case <-time.After(5 * time.Millisecond):
+		return nil, nil
+	}
+}
+
+// longPollTestPoller blocks every poll until released, like long polls that get no task.
+type longPollTestPoller struct {
+	release chan struct{}
+}
+
+func (p longPollTestPoller) PollTask() (taskForWorker, error) {
+	<-p.release
+	return nil, nil
+}
+
+// blockingTaskProcessor runs each task until release is closed.
+type blockingTaskProcessor struct {
+	release chan struct{}
+}
+
+func (p blockingTaskProcessor) ProcessTask(interface{}) error {
+	<-p.release
+	return nil
+}
+
+func newPauseTestWorker(t *testing.T, poller taskPoller, processor taskProcessor) *baseWorker {
+	bw := newBaseWorker(baseWorkerOptions{
+		slotSupplier:     &testSlotSupplier{},
+		maxTaskPerSecond: 1000,
+		taskPollers: []scalableTaskPoller{newScalableTaskPoller(poller, ilog.NewNopLogger(),
+			NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 2}), "", nil)},
+		taskProcessor:  processor,
+		workerType:     "PauseTest",
+		logger:         ilog.NewNopLogger(),
+		stopTimeout:    time.Second,
+		metricsHandler: metrics.NopHandler,
+	})
+	bw.Start()
+	t.Cleanup(bw.Stop)
+	return bw
+}
+
+func TestBaseWorkerPauseResume(t *testing.T) {
+	poller := &pauseTestPoller{tasks: make(chan taskForWorker)}
+	bw := newPauseTestWorker(t, poller, noopTaskProcessor{})
+	require.Eventually(t, func() bool { return poller.polls.Load() > 0 }, time.Second, time.Millisecond)
+
+	bw.pause()
+	require.Nil(t, bw.tryReserveSlot())
+	// Let polls in progress complete.
+	time.Sleep(20 * time.Millisecond)
+	polls := poller.polls.Load()
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, polls, poller.polls.Load())
+
+	bw.resume()
+	require.Eventually(t, func() bool { return poller.polls.Load() > polls }, time.Second, time.Millisecond)
+	require.NotNil(t, bw.tryReserveSlot())
+}
+
+func TestWorkerDrain(t *testing.T) {
+	poller := &pauseTestPoller{tasks: make(chan taskForWorker)}
+	processor := blockingTaskProcessor{release: make(chan struct{})}
+	bw := newPauseTestWorker(t, poller, processor)
+	aw := &AggregatedWorker{activityWorker: &activityWorker{worker: bw}, logger: ilog.NewNopLogger()}
+
+	poller.tasks <- &activityTask{task: &workflowservice.PollActivityTaskQueueResponse{
+		WorkflowExecution: &commonpb.WorkflowExecution{WorkflowId: "wid", RunId: "rid"},
+		ActivityId:        "1",
+		ActivityType:      &commonpb.ActivityType{Name: "Slow"},
+	}}
+	require.Eventually(t, func() bool {
+		tasks, _, _ := bw.getRunningTasks()
+		return len(tasks) == 1
+	}, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	result, err := aw.Drain(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Len(t, result.RunningTasks, 1)
+	running := result.RunningTasks[0]
+	require.Equal(t, WorkerTaskTypeActivity, running.TaskType)
+	require.Equal(t, "Slow", running.ActivityType)
+	require.Equal(t, "wid", running.WorkflowID)
+	require.Equal(t, "1", running.ActivityID)
+	require.Zero(t, result.PendingPolls)
+	require.True(t, bw.isPaused())
+
+	require.Nil(t, bw.drainNotify)
+
+	// Drain is woken up by the task completing.
+	drained := make(chan WorkerDrainResult)
+	go func() {
+		result, err := aw.Drain(context.Background())
+		assert.NoError(t, err)
+		drained <- result
+	}()
+	require.Eventually(t, func() bool {
+		bw.drainNotifyLock.Lock()
+		defer bw.drainNotifyLock.Unlock()
+		return bw.drainNotify != nil
+	}, time.Second, time.Millisecond)
+	close(processor.release)
+	select {
+	case result = <-drained:
+	case <-time.After(time.Second):
+		t.Fatal("drain did not complete after the task completed")
+	}
+	require.Empty(t, result.RunningTasks)
+	require.Nil(t, bw.drainNotify)
+
+	require.EqualError(t, aw.Pause(WorkerTaskTypeLocalActivity), "local activities cannot be paused, pause workflow tasks instead")
+	require.NoError(t, aw.Resume(WorkerTaskTypeActivity))
+	require.False(t, bw.isPaused())
+}
+
+func TestWorkerDrainDoesNotWaitForPolls(t *testing.T) {
+	poller := longPollTestPoller{release: make(chan struct{})}
+	bw := newPauseTestWorker(t, poller, noopTaskProcessor{})
+	// Complete the polls before the worker is stopped.
+	t.Cleanup(func() { close(poller.release) })
+	aw := &AggregatedWorker{activityWorker: &activityWorker{worker: bw}, logger: ilog.NewNopLogger()}
+	require.Eventually(t, func() bool { return bw.pollsInProgress.Load() == 2 }, time.Second, time.Millisecond)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	result, err := aw.Drain(ctx)
+	require.NoError(t, err)
+	require.Empty(t, result.RunningTasks)
+	require.Equal(t, 2, result.PendingPolls)
+}
@@ -2659,6 +2659,42 @@ func TestRegisterStructWithInvalidActivityWithWorkflowContextFails(t *testing.T)
 	assert.Panics(t, testRegisterStructWithInvalidActivityWithWorkflowContextFails)
 }
 
+func TestRegisterActivityMaxConcurrentExecutions(t *testing.T) {
+	registry := newRegistry()
+	registry.RegisterActivityWithOptions(testActivityByteArgs, RegisterActivityOptions{
+		Name:                    "limited",
+		MaxConcurrentExecutions: 2,
+	})
+	registry.RegisterActivityWithOptions(&testActivityStructWithFns{}, RegisterActivityOptions{
+		Name:                       "struct_",
+		SkipInvalidStructFunctions: true,
+		MaxConcurrentExecutions:    3,
+	})
+	registry.RegisterActivity(testActivityMultipleArgs)
+	unlimited, _ := getFunctionName(testActivityMultipleArgs)
+
+	limits := registry.getActivityConcurrencyLimits()
+	assert.Equal(t, 2, limits["limited"])
+	assert.Equal(t, 3, limits["struct_ValidActivity"])
+	assert.NotContains(t, limits, unlimited)
+	assert.Panics(t, func() {
+		registry.RegisterActivityWithOptions(testActivityByteArgs, RegisterActivityOptions{
+			Name:                    "negative",
+			MaxConcurrentExecutions: -1,
+		})
+	})
+
+	// Limits of the worker options take precedence over the ones set at registration.
+	worker := &activityWorker{
+		executionParameters: workerExecutionParameters{
+			MaxConcurrentActivityTypeExecutionSize: map[string]int{"limited": 1, "other": 4},
+		},
+		registry: registry,
+	}
+	assert.Equal(t, map[string]int{"limited": 1, "other": 4, "struct_ValidActivity": 3}, worker.activityConcurrencyLimits())
+	assert.Empty(t, (&activityWorker{registry: newRegistry()}).activityConcurrencyLimits())
+}
+
 func TestVariousActivitySchedulingOption(t *testing.T) {
 	w := &activitiesCallingOptionsWorkflow{t: t}
 
@@ -3175,6 +3211,83 @@ func TestIsNonRetriableError(t *testing.T) {
 	}
 }
 
+func TestWorkerReconfigure(t *testing.T) {
+	aggWorker := NewAggregatedWorker(&WorkflowClient{}, "worker-reconfigure-tq", WorkerOptions{
+		WorkflowTaskPollerBehavior: NewPollerBehaviorAutoscaling(PollerBehaviorAutoscalingOptions{}),
+	})
+	tuner := aggWorker.executionParams.Tuner
+
+	require.NoError(t, aggWorker.Reconfigure(WorkerReconfigureOptions{
+		MaxConcurrentActivityExecutionSize: 5,
+		ActivityTaskPollerBehavior:         NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{MaximumNumberOfPollers: 4}),
+		WorkerActivitiesPerSecond:          10,
+		TaskQueueActivitiesPerSecond:       20,
+		WorkerLocalActivitiesPerSecond:     30,
+	}))
+	require.Equal(t, 5, tuner.GetActivityTaskSlotSupplier().MaxSlots())
+	require.Equal(t, 4, aggWorker.activityWorker.worker.options.taskPollers[0].pollerCount)
+	require.EqualValues(t, 10, aggWorker.activityWorker.worker.taskLimiter.Limit())
+	require.EqualValues(t, 20, aggWorker.activityWorker.poller.(*activityTaskPoller).getActivitiesPerSecond())
+	require.EqualValues(t, 30, aggWorker.workflowWorker.localActivityWorker.taskLimiter.Limit())
+
+	// Invalid changes leave the worker unchanged.
+	err := aggWorker.Reconfigure(WorkerReconfigureOptions{
+		MaxConcurrentActivityExecutionSize: 6,
+		WorkflowTaskPollerBehavior:         NewPollerBehaviorSimpleMaximum(PollerBehaviorSimpleMaximumOptions{}),
+	})
+	require.EqualError(t, err, "invalid WorkflowTaskPollerBehavior: cannot change an autoscaling poller behavior to a simple maximum")
+	require.Equal(t, 5, tuner.GetActivityTaskSlotSupplier().MaxSlots())
+	err = aggWorker.Reconfigure(WorkerReconfigureOptions{MaxConcurrentWorkflowTaskExecutionSize: -1})
+	require.EqualError(t, err, "MaxConcurrentWorkflowTaskExecutionSize must be positive")
+
+	fixed, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	priority, err := NewPrioritySlotSupplier(PrioritySlotSupplierOptions{NumSlots: 10})
+	require.NoError(t, err)
+	compositeTuner, err := NewCompositeTuner(CompositeTunerOptions{
+		WorkflowSlotSupplier:        fixed,
+		ActivitySlotSupplier:        priority,
+		LocalActivitySlotSupplier:   fixed,
+		NexusSlotSupplier:           fixed,
+		SessionActivitySlotSupplier: fixed,
+	})
+	require.NoError(t, err)
+	aggWorker = NewAggregatedWorker(&WorkflowClient{}, "worker-reconfigure-tq", WorkerOptions{Tuner: compositeTuner})
+	err = aggWorker.Reconfigure(WorkerReconfigureOptions{MaxConcurrentActivityExecutionSize: 5})
+	require.EqualError(t, err, "MaxConcurrentActivityExecutionSize can only be changed for a fixed size slot supplier, not Priority")
+}
+
+func TestWorkerActivityTypeConcurrencyLimits(t *testing.T) {
+	aggWorker := NewAggregatedWorker(&WorkflowClient{}, "worker-type-limits-tq", WorkerOptions{})
+	require.Nil(t, aggWorker.activityWorker.typeLimiter)
+	err := aggWorker.Reconfigure(WorkerReconfigureOptions{MaxConcurrentActivityTypeExecutionSize: map[string]int{"a": 1}})
+	require.EqualError(t, err, "cannot change MaxConcurrentActivityTypeExecutionSize, EnableActivityTypeConcurrencyLimits is not set")
+	require.PanicsWithValue(t, "cannot set MaxConcurrentActivityTypeExecutionSize without EnableActivityTypeConcurrencyLimits", func() {
+		NewAggregatedWorker(&WorkflowClient{}, "worker-type-limits-tq", WorkerOptions{
+			MaxConcurrentActivityTypeExecutionSize: map[string]int{"a": 1},
+		})
+	})
+
+	aggWorker = NewAggregatedWorker(&WorkflowClient{}, "worker-type-limits-tq", WorkerOptions{
+		MaxConcurrentActivityTypeExecutionSize: map[string]int{"a": 1},
+		EnableActivityTypeConcurrencyLimits:    true,
+	})
+	aggWorker.RegisterActivityWithOptions(testActivityByteArgs, RegisterActivityOptions{Name: "b", MaxConcurrentExecutions: 2})
+	typeLimiter := aggWorker.activityWorker.typeLimiter
+	require.Same(t, typeLimiter, aggWorker.activityWorker.worker.slotSupplier.inner)
+	require.NoError(t, aggWorker.activityWorker.Start())
+	defer aggWorker.activityWorker.Stop()
+	require.Equal(t, map[string]int{"a": 1, "b": 2}, typeLimiter.limits)
+
+	require.NoError(t, aggWorker.Reconfigure(WorkerReconfigureOptions{
+		MaxConcurrentActivityTypeExecutionSize: map[string]int{"a": 3, "c": 4},
+	}))
+	require.Equal(t, map[string]int{"a": 3, "b": 2, "c": 4}, typeLimiter.limits)
+	err = aggWorker.Reconfigure(WorkerReconfigureOptions{MaxConcurrentActivityTypeExecutionSize: map[string]int{"a": 0}})
+	require.EqualError(t, err, `MaxConcurrentActivityTypeExecutionSize for activity type "a" must be positive`)
+	require.Equal(t, 3, typeLimiter.limits["a"])
+}
+
 func TestWorkerRegisterDisabledWorkflow(t *testing.T) {
 	// Expect panic
 	var recovered interface{}
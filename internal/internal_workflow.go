@@ -72,6 +72,13 @@ type (
 		locked bool
 	}
 
+	// Implements RWMutex interface
+	rwMutexImpl struct {
+		locked         bool
+		readers        int
+		waitingWriters int
+	}
+
 	// Implements Semaphore interface
 	semaphoreImpl struct {
 		size int64
@@ -1164,7 +1171,7 @@ func (s *coroutineState) call(timeout time.Duration) {
 		msg := fmt.Sprintf("[TMPRL1101] Potential deadlock detected: "+
 			"workflow goroutine %q didn't yield for over a second", s.name)
 		s.closed.Store(true)
-		s.panicError = newWorkflowPanicError(msg, st)
+		s.panicError = &workflowPanicError{value: msg, stackTrace: st, potentialDeadlock: true}
 	}
 }
 
@@ -1994,6 +2001,70 @@ func (m *mutexImpl) IsLocked() bool {
 	return m.locked
 }
 
+func (m *rwMutexImpl) Lock(ctx Context) error {
+	m.waitingWriters++
+	err := Await(ctx, func() bool {
+		return !m.locked && m.readers == 0
+	})
+	m.waitingWriters--
+	if err != nil {
+		return err
+	}
+	m.locked = true
+	return nil
+}
+
+func (m *rwMutexImpl) TryLock(ctx Context) bool {
+	assertNotInReadOnlyState(ctx)
+	if m.locked || m.readers > 0 {
+		return false
+	}
+	m.locked = true
+	return true
+}
+
+func (m *rwMutexImpl) Unlock() {
+	if !m.locked {
+		panic("RWMutex.Unlock() was called on a mutex not locked for writing")
+	}
+	m.locked = false
+}
+
+func (m *rwMutexImpl) RLock(ctx Context) error {
+	err := Await(ctx, func() bool {
+		return !m.locked && m.waitingWriters == 0
+	})
+	if err != nil {
+		return err
+	}
+	m.readers++
+	return nil
+}
+
+func (m *rwMutexImpl) TryRLock(ctx Context) bool {
+	assertNotInReadOnlyState(ctx)
+	if m.locked || m.waitingWriters > 0 {
+		return false
+	}
+	m.readers++
+	return true
+}
+
+func (m *rwMutexImpl) RUnlock() {
+	if m.readers == 0 {
+		panic("RWMutex.RUnlock() was called on a mutex not locked for reading")
+	}
+	m.readers--
+}
+
+func (m *rwMutexImpl) IsLocked() bool {
+	return m.locked
+}
+
+func (m *rwMutexImpl) Readers() int {
+	return m.readers
+}
+
 func (s *semaphoreImpl) Acquire(ctx Context, n int64) error {
 	err := Await(ctx, func() bool {
 		return s.size-s.cur >= n
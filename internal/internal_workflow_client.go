@@ -3078,3 +3078,19 @@ func buildUserMetadata(
 	}
 	return ret, nil
 }
+
+// CollectHistoryExternalStorageClaims consumes iter and returns the external
+// storage claims referenced by the payloads of every history event it yields.
+func CollectHistoryExternalStorageClaims(ctx context.Context, iter HistoryEventIterator) ([]converter.ExternalStorageClaim, error) {
+	collector := extstore.NewClaimCollector()
+	for iter.HasNext() {
+		event, err := iter.Next()
+		if err != nil {
+			return nil, err
+		}
+		if err := collector.Collect(ctx, event); err != nil {
+			return nil, err
+		}
+	}
+	return collector.Claims(), nil
+}
@@ -3,17 +3,24 @@ package internal
 import (
 	"context"
 	"errors"
+	"fmt"
+	"math"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
 	"go.temporal.io/sdk/log"
 )
 
 // Metric names emitted by the resource-based tuner
 const (
-	resourceSlotsCPUUsage = "temporal_resource_slots_cpu_usage"
-	resourceSlotsMemUsage = "temporal_resource_slots_mem_usage"
+	resourceSlotsCPUUsage    = "temporal_resource_slots_cpu_usage"
+	resourceSlotsMemUsage    = "temporal_resource_slots_mem_usage"
+	resourceSlotsCPUPressure = "temporal_resource_slots_cpu_pressure"
+	resourceSlotsMemPressure = "temporal_resource_slots_mem_pressure"
+	resourceSlotsIOPressure  = "temporal_resource_slots_io_pressure"
 )
 
 // SysInfoProvider implementations provide information about system resources.
@@ -35,6 +42,25 @@ type SysInfoContext struct {
 	Logger log.Logger
 }
 
+// PressureInfoProvider is an optional interface that SysInfoProvider implementations can implement
+// to report resource pressure, the share of time in which tasks were stalled waiting for a
+// resource, such as Linux pressure stall information (PSI). Pressure measures slowdown directly,
+// which makes it a better signal than utilization for workers whose tasks mostly wait on IO.
+//
+// Each method returns a fraction between 0 and 1, typically averaged over a short recent window.
+// Implementations return an error wrapping errors.ErrUnsupported when the signal is not available,
+// e.g. on systems without PSI, so that pressure thresholds are rejected rather than never reached.
+//
+// Exposed as: [go.temporal.io/sdk/worker.PressureInfoProvider]
+type PressureInfoProvider interface {
+	// CpuPressure returns the share of time in which some tasks were stalled waiting for CPU.
+	CpuPressure(infoContext *SysInfoContext) (float64, error)
+	// MemoryPressure returns the share of time in which some tasks were stalled waiting for memory.
+	MemoryPressure(infoContext *SysInfoContext) (float64, error)
+	// IoPressure returns the share of time in which some tasks were stalled waiting for IO.
+	IoPressure(infoContext *SysInfoContext) (float64, error)
+}
+
 // HasSysInfoProvider is an optional interface that SlotSupplier implementations can implement
 // to expose their SysInfoProvider. This allows the SDK to access system metrics (CPU/memory)
 // for features like worker heartbeats without coupling to specific SlotSupplier implementations.
@@ -57,6 +83,15 @@ type ResourceBasedTunerOptions struct {
 	// InfoSupplier provides CPU and memory usage information. This is required.
 	// Use contrib/sysinfo.SysInfoProvider() for a gopsutil-based implementation.
 	InfoSupplier SysInfoProvider
+	// MaxCpuPressure, MaxMemPressure and MaxIoPressure are the resource pressures as values between
+	// 0 and 1 at or above which no slots beyond the minimums are issued. Zero disables the check.
+	// Setting any of them requires an InfoSupplier that implements PressureInfoProvider and reports
+	// the pressures, such as contrib/sysinfo.SysInfoProvider() on Linux with PSI enabled. If reading
+	// a pressure fails later on, slots are issued based on CPU and memory usage alone until it
+	// succeeds again.
+	MaxCpuPressure float64
+	MaxMemPressure float64
+	MaxIoPressure  float64
 	// Passed to ResourceBasedSlotSupplierOptions.RampThrottle for activities.
 	// If not set, the default value is 50ms.
 	ActivityRampThrottle time.Duration
@@ -76,12 +111,17 @@ func NewResourceBasedTuner(opts ResourceBasedTunerOptions) (WorkerTuner, error)
 	if opts.InfoSupplier == nil {
 		return nil, errors.New("InfoSupplier is required for resource-based tuning")
 	}
-
 	controllerOpts := DefaultResourceControllerOptions()
 	controllerOpts.MemTargetPercent = opts.TargetMem
 	controllerOpts.CpuTargetPercent = opts.TargetCpu
 	controllerOpts.InfoSupplier = opts.InfoSupplier
+	controllerOpts.CpuPressureThreshold = opts.MaxCpuPressure
+	controllerOpts.MemPressureThreshold = opts.MaxMemPressure
+	controllerOpts.IoPressureThreshold = opts.MaxIoPressure
 	controller := NewResourceController(controllerOpts)
+	if err := controller.validatePressureThresholds(); err != nil {
+		return nil, err
+	}
 
 	wfSS := &ResourceBasedSlotSupplier{controller: controller,
 		options: DefaultWorkflowResourceBasedSlotSupplierOptions()}
@@ -150,6 +190,13 @@ func DefaultActivityResourceBasedSlotSupplierOptions() ResourceBasedSlotSupplier
 
 // ResourceBasedSlotSupplier is a SlotSupplier that issues slots based on system resource usage.
 //
+// When the worker using it scales on the task queue backlog, see
+// PollerBehaviorAutoscalingOptions.BacklogLatencyTarget, the supplier also has a slot target while
+// the backlog is behind: the number of slots issued at the last backlog check, at least MinSlots,
+// multiplied by how far behind the backlog is, at most twice, and bounded by MaxSlots. Slots below
+// the target are issued without waiting for RampThrottle and for the controller to settle, as long
+// as memory and CPU usage are below their targets and no pressure threshold is reached.
+//
 // Exposed as: [go.temporal.io/sdk/worker.ResourceBasedSlotSupplier]
 type ResourceBasedSlotSupplier struct {
 	controller *ResourceController
@@ -157,6 +204,9 @@ type ResourceBasedSlotSupplier struct {
 
 	lastIssuedMu     sync.Mutex
 	lastSlotIssuedAt time.Time
+
+	// backlogSlotTarget is the slot target while the task queue backlog is behind, or 0.
+	backlogSlotTarget atomic.Int64
 }
 
 // NewResourceBasedSlotSupplier creates a ResourceBasedSlotSupplier given the provided
@@ -174,6 +224,11 @@ func NewResourceBasedSlotSupplier(
 	if options.RampThrottle < 0 {
 		return nil, errors.New("RampThrottle must be non-negative")
 	}
+	if controller != nil {
+		if err := controller.validatePressureThresholds(); err != nil {
+			return nil, err
+		}
+	}
 	return &ResourceBasedSlotSupplier{controller: controller, options: options}, nil
 }
 
@@ -182,9 +237,9 @@ func (r *ResourceBasedSlotSupplier) ReserveSlot(ctx context.Context, info SlotRe
 		if info.NumIssuedSlots() < r.options.MinSlots {
 			return &SlotPermit{}, nil
 		}
-		if r.options.RampThrottle > 0 {
+		if rampThrottle := r.options.RampThrottle; rampThrottle > 0 && info.NumIssuedSlots() >= r.slotTarget() {
 			r.lastIssuedMu.Lock()
-			mustWaitFor := r.options.RampThrottle - time.Since(r.lastSlotIssuedAt)
+			mustWaitFor := rampThrottle - time.Since(r.lastSlotIssuedAt)
 			if mustWaitFor > 0 {
 				select {
 				case <-time.After(mustWaitFor):
@@ -209,6 +264,18 @@ func (r *ResourceBasedSlotSupplier) TryReserveSlot(info SlotReservationInfo) *Sl
 	defer r.lastIssuedMu.Unlock()
 
 	numIssued := info.NumIssuedSlots()
+	if numIssued >= r.options.MinSlots && numIssued < r.slotTarget() {
+		available, err := r.controller.belowTargets(info.Logger(), info.MetricsHandler())
+		if err != nil {
+			info.Logger().Error("Error calculating resource usage", "error", err)
+			return nil
+		}
+		if available {
+			r.lastSlotIssuedAt = time.Now()
+			return &SlotPermit{}
+		}
+		return nil
+	}
 	if numIssued < r.options.MinSlots || (numIssued < r.options.MaxSlots &&
 		time.Since(r.lastSlotIssuedAt) > r.options.RampThrottle) {
 		decision, err := r.controller.pidDecision(info.Logger(), info.MetricsHandler())
@@ -224,6 +291,21 @@ func (r *ResourceBasedSlotSupplier) TryReserveSlot(info SlotReservationInfo) *Sl
 	return nil
 }
 
+func (r *ResourceBasedSlotSupplier) slotTarget() int {
+	return int(r.backlogSlotTarget.Load())
+}
+
+// scaleForBacklog sets the slot target from the ratio of the task queue backlog age to its target
+// and the number of slots issued, or clears it once the backlog is no longer behind.
+func (r *ResourceBasedSlotSupplier) scaleForBacklog(ratio float64, numIssued int) {
+	if ratio <= 1 {
+		r.backlogSlotTarget.Store(0)
+		return
+	}
+	target := math.Ceil(float64(max(numIssued, r.options.MinSlots, 1)) * math.Min(ratio, maxBacklogScaleFactor))
+	r.backlogSlotTarget.Store(int64(math.Min(target, float64(r.options.MaxSlots))))
+}
+
 func (r *ResourceBasedSlotSupplier) MarkSlotUsed(SlotMarkUsedInfo) {}
 func (r *ResourceBasedSlotSupplier) ReleaseSlot(SlotReleaseInfo)   {}
 func (r *ResourceBasedSlotSupplier) MaxSlots() int {
@@ -253,6 +335,16 @@ type ResourceControllerOptions struct {
 	MemOutputThreshold float64
 	CpuOutputThreshold float64
 
+	// CpuPressureThreshold, MemPressureThreshold and IoPressureThreshold are the resource pressures
+	// as values between 0 and 1 at or above which the controller issues no slots. Zero disables
+	// the check. Setting any of them requires an InfoSupplier that implements
+	// PressureInfoProvider and reports the pressures, otherwise NewResourceBasedSlotSupplier
+	// returns an error. If reading a pressure fails later on, the controller decides based on CPU
+	// and memory usage alone until it succeeds again.
+	CpuPressureThreshold float64
+	MemPressureThreshold float64
+	IoPressureThreshold  float64
+
 	MemPGain float64
 	MemIGain float64
 	MemDGain float64
@@ -309,6 +401,8 @@ type ResourceController struct {
 	lastRefresh  time.Time
 	memPid       *pidController
 	cpuPid       *pidController
+	// pressureFailing is set while reading a pressure fails, so the failure is only logged once
+	pressureFailing bool
 }
 
 // NewResourceController creates a new ResourceController with the provided options.
@@ -341,17 +435,13 @@ func (rc *ResourceController) pidDecision(logger log.Logger, metricsHandler metr
 	rc.mu.Lock()
 	defer rc.mu.Unlock()
 
-	memUsage, err := rc.infoSupplier.MemoryUsage(&SysInfoContext{Logger: logger})
+	memUsage, cpuUsage, err := rc.usage(logger, metricsHandler)
 	if err != nil {
 		return false, err
 	}
-	cpuUsage, err := rc.infoSupplier.CpuUsage(&SysInfoContext{Logger: logger})
-	if err != nil {
-		return false, err
-	}
-	rc.publishResourceMetrics(metricsHandler, memUsage, cpuUsage)
-	if memUsage >= rc.options.MemTargetPercent {
-		// Never allow going over the memory target
+	underPressure := rc.underPressure(logger, metricsHandler)
+	if memUsage >= rc.options.MemTargetPercent || underPressure {
+		// Never allow going over the memory target or a pressure threshold
 		return false, nil
 	}
 	elapsedTime := time.Since(rc.lastRefresh)
@@ -368,6 +458,34 @@ func (rc *ResourceController) pidDecision(logger log.Logger, metricsHandler metr
 		rc.cpuPid.controlSignal > rc.options.CpuOutputThreshold, nil
 }
 
+// belowTargets reports whether memory and CPU usage are below their targets and no pressure
+// threshold is reached, without waiting for the PID controllers to settle.
+func (rc *ResourceController) belowTargets(logger log.Logger, metricsHandler metrics.Handler) (bool, error) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+
+	memUsage, cpuUsage, err := rc.usage(logger, metricsHandler)
+	if err != nil {
+		return false, err
+	}
+	return memUsage < rc.options.MemTargetPercent && cpuUsage < rc.options.CpuTargetPercent &&
+		!rc.underPressure(logger, metricsHandler), nil
+}
+
+// usage reads and publishes the memory and CPU usage. Must be called with the lock held.
+func (rc *ResourceController) usage(logger log.Logger, metricsHandler metrics.Handler) (memUsage, cpuUsage float64, err error) {
+	memUsage, err = rc.infoSupplier.MemoryUsage(&SysInfoContext{Logger: logger})
+	if err != nil {
+		return 0, 0, err
+	}
+	cpuUsage, err = rc.infoSupplier.CpuUsage(&SysInfoContext{Logger: logger})
+	if err != nil {
+		return 0, 0, err
+	}
+	rc.publishResourceMetrics(metricsHandler, memUsage, cpuUsage)
+	return memUsage, cpuUsage, nil
+}
+
 func (rc *ResourceController) publishResourceMetrics(metricsHandler metrics.Handler, memUsage, cpuUsage float64) {
 	if metricsHandler == nil {
 		return
@@ -375,3 +493,82 @@ func (rc *ResourceController) publishResourceMetrics(metricsHandler metrics.Hand
 	metricsHandler.Gauge(resourceSlotsMemUsage).Update(memUsage * 100)
 	metricsHandler.Gauge(resourceSlotsCPUUsage).Update(cpuUsage * 100)
 }
+
+// validatePressureThresholds checks that the pressure thresholds are valid, and that the
+// InfoSupplier reports the pressures that have one.
+func (rc *ResourceController) validatePressureThresholds() error {
+	checks := rc.pressureChecks()
+	for _, check := range checks {
+		if check.threshold < 0 || check.threshold > 1 {
+			return errors.New("pressure thresholds must be between 0 and 1")
+		}
+	}
+	for _, check := range checks {
+		if check.threshold == 0 {
+			continue
+		}
+		if check.pressure == nil {
+			return errors.New("pressure thresholds require an InfoSupplier that implements PressureInfoProvider")
+		}
+		// Other errors may be transient, and are handled when making decisions
+		if _, err := check.pressure(&SysInfoContext{Logger: ilog.NewNopLogger()}); errors.Is(err, errors.ErrUnsupported) {
+			return fmt.Errorf("%s pressure threshold is set but pressure is not available: %w", check.resource, err)
+		}
+	}
+	return nil
+}
+
+type pressureCheck struct {
+	resource string
+	// pressure is nil if the InfoSupplier does not implement PressureInfoProvider
+	pressure  func(*SysInfoContext) (float64, error)
+	threshold float64
+	metric    string
+}
+
+func (rc *ResourceController) pressureChecks() []pressureCheck {
+	checks := []pressureCheck{
+		{resource: "CPU", threshold: rc.options.CpuPressureThreshold, metric: resourceSlotsCPUPressure},
+		{resource: "memory", threshold: rc.options.MemPressureThreshold, metric: resourceSlotsMemPressure},
+		{resource: "IO", threshold: rc.options.IoPressureThreshold, metric: resourceSlotsIOPressure},
+	}
+	if provider, ok := rc.infoSupplier.(PressureInfoProvider); ok {
+		checks[0].pressure = provider.CpuPressure
+		checks[1].pressure = provider.MemoryPressure
+		checks[2].pressure = provider.IoPressure
+	}
+	return checks
+}
+
+// underPressure reports whether any resource pressure with a threshold is at or above it.
+// Pressures that cannot be read are ignored, leaving the decision to CPU and memory usage. Must be
+// called with the lock held.
+func (rc *ResourceController) underPressure(logger log.Logger, metricsHandler metrics.Handler) bool {
+	infoContext := &SysInfoContext{Logger: logger}
+	underPressure, failing := false, false
+	for _, check := range rc.pressureChecks() {
+		if check.pressure == nil {
+			return false
+		}
+		pressure, err := check.pressure(infoContext)
+		if err != nil && check.threshold == 0 {
+			// Only reported as a metric
+			continue
+		} else if err != nil {
+			if !rc.pressureFailing {
+				logger.Warn("Failed to read resource pressure, deciding on CPU and memory usage only",
+					"resource", check.resource, "error", err)
+			}
+			failing = true
+			continue
+		}
+		if metricsHandler != nil {
+			metricsHandler.Gauge(check.metric).Update(pressure * 100)
+		}
+		if check.threshold > 0 && pressure >= check.threshold {
+			underPressure = true
+		}
+	}
+	rc.pressureFailing = failing
+	return underPressure
+}
@@ -1,6 +1,9 @@
 package internal
 
 import (
+	"errors"
+	"fmt"
+
 	"github.com/stretchr/testify/assert"
 	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/internal/log"
@@ -103,3 +106,84 @@ func TestPidDecisionEmitsUsageMetrics(t *testing.T) {
 	assert.Equal(t, 70.0, gaugesByName[resourceSlotsMemUsage])
 	assert.Equal(t, 90.0, gaugesByName[resourceSlotsCPUUsage])
 }
+
+type FakePressureInfoSupplier struct {
+	FakeSystemInfoSupplier
+	cpuPressure float64
+	memPressure float64
+	ioPressure  float64
+	pressureErr error
+}
+
+func (f *FakePressureInfoSupplier) CpuPressure(_ *SysInfoContext) (float64, error) {
+	return f.cpuPressure, f.pressureErr
+}
+
+func (f *FakePressureInfoSupplier) MemoryPressure(_ *SysInfoContext) (float64, error) {
+	return f.memPressure, f.pressureErr
+}
+
+func (f *FakePressureInfoSupplier) IoPressure(_ *SysInfoContext) (float64, error) {
+	return f.ioPressure, f.pressureErr
+}
+
+func TestPidDecisionPressureThresholds(t *testing.T) {
+	logger := &log.NoopLogger{}
+	metricsHandler := metrics.NewCapturingHandler()
+	fakeSupplier := &FakePressureInfoSupplier{
+		FakeSystemInfoSupplier: FakeSystemInfoSupplier{memUse: 0.5, cpuUse: 0.5},
+		ioPressure:             0.1,
+	}
+	rcOpts := DefaultResourceControllerOptions()
+	rcOpts.InfoSupplier = fakeSupplier
+	rcOpts.IoPressureThreshold = 0.2
+	rc := NewResourceController(rcOpts)
+
+	decision, err := rc.pidDecision(logger, metricsHandler)
+	assert.NoError(t, err)
+	assert.True(t, decision)
+
+	fakeSupplier.ioPressure = 0.3
+	decision, err = rc.pidDecision(logger, metricsHandler)
+	assert.NoError(t, err)
+	assert.False(t, decision)
+
+	gaugesByName := make(map[string]float64)
+	for _, gauge := range metricsHandler.Gauges() {
+		gaugesByName[gauge.Name] = gauge.Value()
+	}
+	assert.Equal(t, 30.0, gaugesByName[resourceSlotsIOPressure])
+
+	// Pressure read errors leave the decision to CPU and memory usage.
+	fakeSupplier.pressureErr = errors.New("read failed")
+	decision, err = rc.pidDecision(logger, metricsHandler)
+	assert.NoError(t, err)
+	assert.True(t, decision)
+
+	// Thresholds are rejected when pressure is not available.
+	fakeSupplier.pressureErr = fmt.Errorf("no PSI: %w", errors.ErrUnsupported)
+	_, err = NewResourceBasedSlotSupplier(rc, DefaultActivityResourceBasedSlotSupplierOptions())
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+	assert.ErrorContains(t, err, "IO pressure threshold is set but pressure is not available")
+	_, err = NewResourceBasedTuner(ResourceBasedTunerOptions{
+		TargetMem:      0.8,
+		TargetCpu:      0.9,
+		InfoSupplier:   fakeSupplier,
+		MaxCpuPressure: 0.5,
+	})
+	assert.ErrorIs(t, err, errors.ErrUnsupported)
+	rcOpts.IoPressureThreshold = 0
+	_, err = NewResourceBasedSlotSupplier(NewResourceController(rcOpts), DefaultActivityResourceBasedSlotSupplierOptions())
+	assert.NoError(t, err)
+	rcOpts.IoPressureThreshold = 2
+	_, err = NewResourceBasedSlotSupplier(NewResourceController(rcOpts), DefaultActivityResourceBasedSlotSupplierOptions())
+	assert.EqualError(t, err, "pressure thresholds must be between 0 and 1")
+
+	_, err = NewResourceBasedTuner(ResourceBasedTunerOptions{
+		TargetMem:     0.8,
+		TargetCpu:     0.9,
+		InfoSupplier:  &FakeSystemInfoSupplier{},
+		MaxIoPressure: 0.2,
+	})
+	assert.EqualError(t, err, "pressure thresholds require an InfoSupplier that implements PressureInfoProvider")
+}
@@ -0,0 +1,317 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+// SlotPoolOptions are the options used by NewSlotPool.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotPoolOptions]
+type SlotPoolOptions struct {
+	// NumSlots is the total number of slots shared by the members of the pool. Required.
+	NumSlots int
+}
+
+// SlotPool is a budget of slots shared by several SlotPoolSupplier instances, typically the slot
+// suppliers of different workers in the same process. Each member is guaranteed its MinSlots,
+// and the remaining slots are shared: when members contend for them, waiting reservations are
+// granted to the member using the fewest slots relative to its Weight.
+//
+// Create one pool per kind of slot to be shared, e.g. one for the activity slot suppliers of all
+// workers, and never give the same member to more than one worker. Close the member of a worker
+// that is stopped for good to give its MinSlots back to the pool.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotPool]
+type SlotPool struct {
+	numSlots int
+
+	lock       sync.Mutex
+	members    map[string]*SlotPoolSupplier
+	totalMin   int
+	sharedUsed int
+	waiters    []*slotPoolWaiter
+}
+
+type slotPoolWaiter struct {
+	member  *SlotPoolSupplier
+	granted chan struct{}
+}
+
+// NewSlotPool creates a SlotPool with the given options.
+//
+// Exposed as: [go.temporal.io/sdk/worker.NewSlotPool]
+func NewSlotPool(options SlotPoolOptions) (*SlotPool, error) {
+	if options.NumSlots <= 0 {
+		return nil, errors.New("NumSlots must be positive")
+	}
+	return &SlotPool{
+		numSlots: options.NumSlots,
+		members:  make(map[string]*SlotPoolSupplier),
+	}, nil
+}
+
+// SlotPoolSupplierOptions are the options used by NewSlotPoolSupplier.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotPoolSupplierOptions]
+type SlotPoolSupplierOptions struct {
+	// Name identifies the member within its pool and is used as the slot_pool_member tag of its
+	// metrics. Required, and must be unique within the pool.
+	Name string
+	// Weight is the share of contended slots this member receives relative to the other members.
+	// Defaults to 1.
+	Weight float64
+	// MinSlots is the number of slots reserved for this member, which other members never use.
+	// The minimums of all members of a pool must not exceed its NumSlots.
+	MinSlots int
+}
+
+// SlotPoolSupplier is a SlotSupplier that issues slots from a SlotPool.
+//
+// It publishes the temporal_slot_pool_slots_used gauge and the temporal_slot_pool_slots_issued
+// counter, tagged with slot_pool_member, through the metrics handler of the worker using it, to
+// show how much of the pool each member consumes.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotPoolSupplier]
+type SlotPoolSupplier struct {
+	pool     *SlotPool
+	name     string
+	weight   float64
+	// minSlots, issued and closed are guarded by the pool lock
+	minSlots int
+	issued   int
+	closed   chan struct{}
+}
+
+// NewSlotPoolSupplier adds a member to the pool and returns the SlotSupplier that issues its
+// slots.
+//
+// Exposed as: [go.temporal.io/sdk/worker.NewSlotPoolSupplier]
+func NewSlotPoolSupplier(pool *SlotPool, options SlotPoolSupplierOptions) (*SlotPoolSupplier, error) {
+	if pool == nil {
+		return nil, errors.New("pool is required")
+	}
+	if options.Name == "" {
+		return nil, errors.New("Name is required")
+	}
+	if options.Weight < 0 {
+		return nil, errors.New("Weight must be non-negative")
+	}
+	if options.MinSlots < 0 {
+		return nil, errors.New("MinSlots must be non-negative")
+	}
+	weight := options.Weight
+	if weight == 0 {
+		weight = 1
+	}
+	pool.lock.Lock()
+	defer pool.lock.Unlock()
+	if _, ok := pool.members[options.Name]; ok {
+		return nil, fmt.Errorf("slot pool already has a member named %q", options.Name)
+	}
+	if pool.totalMin+options.MinSlots > pool.numSlots {
+		return nil, fmt.Errorf("MinSlots of %d exceeds the %d unreserved slots of the pool",
+			options.MinSlots, pool.numSlots-pool.totalMin)
+	}
+	member := &SlotPoolSupplier{
+		pool:     pool,
+		name:     options.Name,
+		weight:   weight,
+		minSlots: options.MinSlots,
+		closed:   make(chan struct{}),
+	}
+	pool.members[options.Name] = member
+	pool.totalMin += options.MinSlots
+	return member, nil
+}
+
+// Close removes the member from its pool, so that its MinSlots are shared by the other members and
+// its name can be given to a new member. Slots still issued to it are returned to the pool when
+// they are released, and reservations fail from then on. Close the member once the worker using
+// it has stopped. Closing a member again has no effect.
+func (s *SlotPoolSupplier) Close() {
+	p := s.pool
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if s.isClosedLocked() {
+		return
+	}
+	close(s.closed)
+	delete(p.members, s.name)
+	// Slots issued within the minimum now count against the shared slots, which the minimum is
+	// added to.
+	p.sharedUsed += min(s.issued, s.minSlots)
+	p.totalMin -= s.minSlots
+	s.minSlots = 0
+	waiters := p.waiters[:0]
+	for _, w := range p.waiters {
+		if w.member != s {
+			waiters = append(waiters, w)
+		}
+	}
+	p.waiters = waiters
+	p.grantWaiters()
+}
+
+func (s *SlotPoolSupplier) ReserveSlot(ctx context.Context, info SlotReservationInfo) (*SlotPermit, error) {
+	p := s.pool
+	p.lock.Lock()
+	if s.isClosedLocked() {
+		p.lock.Unlock()
+		return nil, fmt.Errorf("slot pool member %q is closed", s.name)
+	}
+	if p.canTake(s) {
+		p.take(s)
+		used := s.issued
+		p.lock.Unlock()
+		s.publishIssued(info.MetricsHandler(), used)
+		return &SlotPermit{}, nil
+	}
+	waiter := &slotPoolWaiter{member: s, granted: make(chan struct{})}
+	p.waiters = append(p.waiters, waiter)
+	p.lock.Unlock()
+
+	select {
+	case <-waiter.granted:
+		p.lock.Lock()
+		used := s.issued
+		p.lock.Unlock()
+		s.publishIssued(info.MetricsHandler(), used)
+		return &SlotPermit{}, nil
+	case <-s.closed:
+		return nil, fmt.Errorf("slot pool member %q is closed", s.name)
+	case <-ctx.Done():
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if !p.removeWaiter(waiter) {
+			// Granted concurrently with cancellation, give the slot to someone else
+			p.release(s)
+			p.grantWaiters()
+		}
+		return nil, fmt.Errorf("failed to acquire slot: %w", ctx.Err())
+	}
+}
+
+func (s *SlotPoolSupplier) TryReserveSlot(info SlotReservationInfo) *SlotPermit {
+	p := s.pool
+	p.lock.Lock()
+	if s.isClosedLocked() || !p.canTake(s) {
+		p.lock.Unlock()
+		return nil
+	}
+	p.take(s)
+	used := s.issued
+	p.lock.Unlock()
+	s.publishIssued(info.MetricsHandler(), used)
+	return &SlotPermit{}
+}
+
+func (s *SlotPoolSupplier) MarkSlotUsed(SlotMarkUsedInfo) {}
+
+func (s *SlotPoolSupplier) ReleaseSlot(info SlotReleaseInfo) {
+	p := s.pool
+	p.lock.Lock()
+	p.release(s)
+	used := s.issued
+	p.grantWaiters()
+	p.lock.Unlock()
+	s.metricsHandler(info.MetricsHandler()).Gauge(metrics.SlotPoolSlotsUsed).Update(float64(used))
+}
+
+// MaxSlots returns the most slots this member can hold, which is the pool size less the minimums
+// of the other members.
+func (s *SlotPoolSupplier) MaxSlots() int {
+	p := s.pool
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return p.numSlots - p.totalMin + s.minSlots
+}
+
+func (s *SlotPoolSupplier) metricsHandler(handler metrics.Handler) metrics.Handler {
+	if handler == nil {
+		return metrics.NopHandler
+	}
+	return handler.WithTags(map[string]string{metrics.SlotPoolMemberTagName: s.name})
+}
+
+func (s *SlotPoolSupplier) publishIssued(handler metrics.Handler, used int) {
+	handler = s.metricsHandler(handler)
+	handler.Counter(metrics.SlotPoolSlotsIssued).Inc(1)
+	handler.Gauge(metrics.SlotPoolSlotsUsed).Update(float64(used))
+}
+
+// isClosedLocked reports whether the member is closed. Must be called with the pool lock held.
+func (s *SlotPoolSupplier) isClosedLocked() bool {
+	select {
+	case <-s.closed:
+		return true
+	default:
+		return false
+	}
+}
+
+// canTake reports whether the member may be issued a slot, either from its minimum or from the
+// shared slots. Slots of a minimum are only issued while the shared slots fit in the rest of the
+// pool, which they may not for a while after a member is added in place of a closed one. Must be
+// called with the lock held.
+func (p *SlotPool) canTake(member *SlotPoolSupplier) bool {
+	shared := p.numSlots - p.totalMin
+	return (member.issued < member.minSlots && p.sharedUsed <= shared) || p.sharedUsed < shared
+}
+
+// take issues a slot to the member. Must be called with the lock held.
+func (p *SlotPool) take(member *SlotPoolSupplier) {
+	if member.issued >= member.minSlots {
+		p.sharedUsed++
+	}
+	member.issued++
+}
+
+// release returns a slot of the member. Must be called with the lock held.
+func (p *SlotPool) release(member *SlotPoolSupplier) {
+	member.issued--
+	if member.issued >= member.minSlots {
+		p.sharedUsed--
+	}
+}
+
+// grantWaiters issues slots to waiting reservations that can take one, preferring the member
+// using the fewest slots relative to its weight and first come first served within a member.
+// Must be called with the lock held.
+func (p *SlotPool) grantWaiters() {
+	for {
+		best := -1
+		for i, w := range p.waiters {
+			if !p.canTake(w.member) {
+				continue
+			}
+			if best < 0 || float64(w.member.issued)/w.member.weight <
+				float64(p.waiters[best].member.issued)/p.waiters[best].member.weight {
+				best = i
+			}
+		}
+		if best < 0 {
+			return
+		}
+		waiter := p.waiters[best]
+		p.take(waiter.member)
+		p.waiters = append(p.waiters[:best], p.waiters[best+1:]...)
+		close(waiter.granted)
+	}
+}
+
+// removeWaiter removes the waiter, returning false if it was not waiting. Must be called with the
+// lock held.
+func (p *SlotPool) removeWaiter(waiter *slotPoolWaiter) bool {
+	for i, w := range p.waiters {
+		if w == waiter {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
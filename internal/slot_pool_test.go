@@ -0,0 +1,153 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+)
+
+func TestSlotPoolWeightedFairShare(t *testing.T) {
+	pool, err := NewSlotPool(SlotPoolOptions{NumSlots: 4})
+	require.NoError(t, err)
+	a, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "a", MinSlots: 1})
+	require.NoError(t, err)
+	b, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "b", Weight: 3})
+	require.NoError(t, err)
+	require.Equal(t, 4, a.MaxSlots())
+	require.Equal(t, 3, b.MaxSlots())
+	require.Equal(t, "SlotPool", getSlotSupplierKind(b))
+
+	handler := metrics.NewCapturingHandler()
+	info := slotReserveInfoImpl{taskQueue: "tq", issuedSlots: &atomic.Int32{}, metrics: handler}
+	release := slotReleaseContextImpl{metrics: handler}
+
+	// Without contention a member may use every slot not reserved for others.
+	for i := 0; i < 4; i++ {
+		require.NotNil(t, a.TryReserveSlot(info))
+	}
+	require.Nil(t, b.TryReserveSlot(info))
+
+	granted := make(chan string, 3)
+	reserve := func(s *SlotPoolSupplier) {
+		_, err := s.ReserveSlot(context.Background(), info)
+		require.NoError(t, err)
+		granted <- s.name
+	}
+	go reserve(a)
+	require.Eventually(t, func() bool { return pool.numWaiters() == 1 }, time.Second, time.Millisecond)
+	go reserve(b)
+	go reserve(b)
+	require.Eventually(t, func() bool { return pool.numWaiters() == 3 }, time.Second, time.Millisecond)
+
+	// Freed slots go to the member using the fewest slots relative to its weight.
+	a.ReleaseSlot(release)
+	require.Equal(t, "b", <-granted)
+	a.ReleaseSlot(release)
+	require.Equal(t, "b", <-granted)
+	a.ReleaseSlot(release)
+	require.Equal(t, "a", <-granted)
+
+	var used, issued = map[string]float64{}, map[string]int64{}
+	for _, g := range handler.Gauges() {
+		if g.Name == metrics.SlotPoolSlotsUsed {
+			used[g.Tags[metrics.SlotPoolMemberTagName]] = g.Value()
+		}
+	}
+	for _, c := range handler.Counters() {
+		if c.Name == metrics.SlotPoolSlotsIssued {
+			issued[c.Tags[metrics.SlotPoolMemberTagName]] = c.Value()
+		}
+	}
+	require.Equal(t, map[string]float64{"a": 2, "b": 2}, used)
+	require.Equal(t, map[string]int64{"a": 5, "b": 2}, issued)
+}
+
+func TestSlotPoolMinSlots(t *testing.T) {
+	pool, err := NewSlotPool(SlotPoolOptions{NumSlots: 2})
+	require.NoError(t, err)
+	guaranteed, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "guaranteed", MinSlots: 1})
+	require.NoError(t, err)
+	other, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "other"})
+	require.NoError(t, err)
+
+	info := reserveInfo("tq")
+	require.NotNil(t, other.TryReserveSlot(info))
+	require.Nil(t, other.TryReserveSlot(info))
+	require.NotNil(t, guaranteed.TryReserveSlot(info))
+	require.Nil(t, guaranteed.TryReserveSlot(info))
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = other.ReserveSlot(ctx, info)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 0, pool.numWaiters())
+
+	_, err = NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "other"})
+	require.EqualError(t, err, `slot pool already has a member named "other"`)
+	_, err = NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "greedy", MinSlots: 2})
+	require.EqualError(t, err, "MinSlots of 2 exceeds the 1 unreserved slots of the pool")
+	_, err = NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{})
+	require.EqualError(t, err, "Name is required")
+	_, err = NewSlotPool(SlotPoolOptions{})
+	require.EqualError(t, err, "NumSlots must be positive")
+}
+
+func TestSlotPoolSupplierClose(t *testing.T) {
+	pool, err := NewSlotPool(SlotPoolOptions{NumSlots: 3})
+	require.NoError(t, err)
+	closing, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "closing", MinSlots: 2})
+	require.NoError(t, err)
+	other, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "other"})
+	require.NoError(t, err)
+
+	info := reserveInfo("tq")
+	require.NotNil(t, closing.TryReserveSlot(info))
+	require.NotNil(t, closing.TryReserveSlot(info))
+	require.NotNil(t, other.TryReserveSlot(info))
+	closingErr := make(chan error)
+	go func() {
+		_, err := closing.ReserveSlot(context.Background(), info)
+		closingErr <- err
+	}()
+	otherErr := make(chan error)
+	go func() {
+		_, err := other.ReserveSlot(context.Background(), info)
+		otherErr <- err
+	}()
+	require.Eventually(t, func() bool { return pool.numWaiters() == 2 }, time.Second, time.Millisecond)
+
+	// Closing fails the reservations of the member, and its slots go to the others once released.
+	closing.Close()
+	closing.Close()
+	require.EqualError(t, <-closingErr, `slot pool member "closing" is closed`)
+	require.Nil(t, closing.TryReserveSlot(info))
+	_, err = closing.ReserveSlot(context.Background(), info)
+	require.EqualError(t, err, `slot pool member "closing" is closed`)
+	require.Equal(t, 3, other.MaxSlots())
+	closing.ReleaseSlot(slotReleaseContextImpl{})
+	require.NoError(t, <-otherErr)
+
+	// The name and the minimum can be given to a new member, which gets its minimum once the slots
+	// issued to the others fit in the rest of the pool.
+	replacement, err := NewSlotPoolSupplier(pool, SlotPoolSupplierOptions{Name: "closing", MinSlots: 2})
+	require.NoError(t, err)
+	require.Nil(t, replacement.TryReserveSlot(info))
+	closing.ReleaseSlot(slotReleaseContextImpl{})
+	require.Nil(t, replacement.TryReserveSlot(info))
+	other.ReleaseSlot(slotReleaseContextImpl{})
+	require.NotNil(t, replacement.TryReserveSlot(info))
+	require.NotNil(t, replacement.TryReserveSlot(info))
+	require.Nil(t, replacement.TryReserveSlot(info))
+	require.Nil(t, other.TryReserveSlot(info))
+}
+
+func (p *SlotPool) numWaiters() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.waiters)
+}
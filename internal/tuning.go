@@ -2,11 +2,11 @@ package internal
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"sync/atomic"
-
-	"golang.org/x/sync/semaphore"
+	"time"
 
 	"go.temporal.io/sdk/internal/common/metrics"
 	"go.temporal.io/sdk/log"
@@ -60,6 +60,19 @@ type SlotReservationInfo interface {
 	MetricsHandler() metrics.Handler
 }
 
+// SlotTaskInfo describes the task that a slot is being used for. Fields that do not apply to
+// the kind of task are empty.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotTaskInfo]
+type SlotTaskInfo struct {
+	// WorkflowType is the workflow type of a workflow task.
+	WorkflowType string
+	// ActivityType is the activity type of an activity task or local activity.
+	ActivityType string
+	// Priority is the priority of an activity task.
+	Priority Priority
+}
+
 // SlotMarkUsedInfo contains information that SlotSupplier instances can use during
 // SlotSupplier.MarkSlotUsed calls.
 //
@@ -67,6 +80,8 @@ type SlotReservationInfo interface {
 type SlotMarkUsedInfo interface {
 	// Permit returns the permit that is being marked as used.
 	Permit() *SlotPermit
+	// TaskInfo returns information about the task that the slot is being used for.
+	TaskInfo() SlotTaskInfo
 	// Logger returns an appropriately tagged logger.
 	Logger() log.Logger
 	// MetricsHandler returns an appropriately tagged metrics handler that can be used to record
@@ -131,16 +146,132 @@ type SlotSupplier interface {
 }
 
 func getSlotSupplierKind(s SlotSupplier) string {
-	switch s.(type) {
+	switch s := s.(type) {
 	case *FixedSizeSlotSupplier:
 		return "Fixed"
 	case *ResourceBasedSlotSupplier:
 		return "ResourceBased"
+	case *RateLimitedSlotSupplier:
+		return "RateLimited"
+	case *PrioritySlotSupplier:
+		return "Priority"
+	case *SlotPoolSupplier:
+		return "SlotPool"
+	case *ActivityTypeConcurrencySlotSupplier:
+		// Workers may wrap activity slot suppliers with this one to apply limits, so report what issues the slots
+		return getSlotSupplierKind(s.inner)
 	default:
 		return "Custom"
 	}
 }
 
+// SlotReservationOutcome is the decision made on a slot reservation.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotReservationOutcome]
+//
+// NOTE: Experimental
+type SlotReservationOutcome int
+
+const (
+	// SlotReservationOutcomeReserved means a slot was reserved.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.SlotReservationOutcomeReserved]
+	SlotReservationOutcomeReserved SlotReservationOutcome = iota
+	// SlotReservationOutcomeUnavailable means no slot was available for a reservation that does
+	// not wait, such as for an eager workflow or activity.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.SlotReservationOutcomeUnavailable]
+	SlotReservationOutcomeUnavailable
+	// SlotReservationOutcomeCanceled means the worker stopped waiting for a slot, typically
+	// because it is shutting down.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.SlotReservationOutcomeCanceled]
+	SlotReservationOutcomeCanceled
+	// SlotReservationOutcomeFailed means the SlotSupplier returned an error. The reservation is
+	// retried.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.SlotReservationOutcomeFailed]
+	SlotReservationOutcomeFailed
+)
+
+func (o SlotReservationOutcome) String() string {
+	switch o {
+	case SlotReservationOutcomeReserved:
+		return "reserved"
+	case SlotReservationOutcomeUnavailable:
+		return "unavailable"
+	case SlotReservationOutcomeCanceled:
+		return "canceled"
+	case SlotReservationOutcomeFailed:
+		return "failed"
+	default:
+		return fmt.Sprintf("SlotReservationOutcome(%d)", int(o))
+	}
+}
+
+// SlotReservationEvent describes a decision made on a slot reservation by a worker.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotReservationEvent]
+//
+// NOTE: Experimental
+type SlotReservationEvent struct {
+	// TaskType is the type of task the slot was reserved for. Slots of session activities are
+	// reported as activity slots.
+	TaskType WorkerTaskType
+	// SlotSupplierKind is the kind of SlotSupplier that made the decision, such as "Fixed",
+	// "ResourceBased" or "Custom".
+	SlotSupplierKind string
+	// TaskQueue is the task queue the slot was reserved for.
+	TaskQueue string
+	// Eager is true for reservations that do not wait for a slot, which are made for eager
+	// workflows and activities.
+	Eager bool
+	// Outcome is the decision made on the reservation.
+	Outcome SlotReservationOutcome
+	// Err is the error returned by the SlotSupplier, set when the outcome is
+	// SlotReservationOutcomeFailed or SlotReservationOutcomeCanceled.
+	Err error
+	// WaitTime is how long the reservation waited for the decision.
+	WaitTime time.Duration
+	// IssuedSlots is the number of slots issued after the decision.
+	IssuedSlots int
+	// UsedSlots is the number of issued slots that are running tasks.
+	UsedSlots int
+	// MaxSlots is the maximum number of slots of the SlotSupplier, or 0 if it has none.
+	MaxSlots int
+}
+
+// SlotReservationObserver is an optional interface that WorkerTuner implementations can
+// implement to receive an event for every slot reservation decision made by the workers using
+// them, for example to debug why tasks back up. Workers also publish the following metrics for
+// each kind of slot, tagged with worker_type:
+//   - temporal_worker_task_slot_reservation_latency: how long reservations waited for a slot,
+//     tagged with wait_reason, which is "slots" when waiting for the SlotSupplier to issue a slot,
+//     or "rate_limit" or "activity_type_limit" when held back by a RateLimitedSlotSupplier or an
+//     ActivityTypeConcurrencySlotSupplier.
+//   - temporal_worker_task_slot_reservation_denied: reservations that were denied, tagged with
+//     denial_reason, which is "unavailable" or "failed", or "rate_limit" or "activity_type_limit"
+//     when denied by one of the suppliers above. Reservations canceled when the worker shuts down
+//     are not counted.
+//   - temporal_worker_task_slots_at_max_duration: how long all slots stayed issued before one
+//     was available again.
+//
+// OnSlotReservation is called on the path of reserving slots, so implementations must be
+// thread-safe and must not block.
+//
+// Exposed as: [go.temporal.io/sdk/worker.SlotReservationObserver]
+//
+// NOTE: Experimental
+type SlotReservationObserver interface {
+	OnSlotReservation(event SlotReservationEvent)
+}
+
+// slotReservationObserverOf returns the tuner if it observes slot reservations, or nil.
+func slotReservationObserverOf(tuner WorkerTuner) SlotReservationObserver {
+	observer, _ := tuner.(SlotReservationObserver)
+	return observer
+}
+
 // CompositeTuner allows you to build a tuner from multiple slot suppliers.
 type CompositeTuner struct {
 	workflowSlotSupplier        SlotSupplier
@@ -148,6 +279,7 @@ type CompositeTuner struct {
 	localActivitySlotSupplier   SlotSupplier
 	nexusSlotSupplier           SlotSupplier
 	sessionActivitySlotSupplier SlotSupplier
+	slotReservationObserver     SlotReservationObserver
 }
 
 func (c *CompositeTuner) GetWorkflowTaskSlotSupplier() SlotSupplier {
@@ -165,6 +297,11 @@ func (c *CompositeTuner) GetNexusSlotSupplier() SlotSupplier {
 func (c *CompositeTuner) GetSessionActivitySlotSupplier() SlotSupplier {
 	return c.sessionActivitySlotSupplier
 }
+func (c *CompositeTuner) OnSlotReservation(event SlotReservationEvent) {
+	if c.slotReservationObserver != nil {
+		c.slotReservationObserver.OnSlotReservation(event)
+	}
+}
 
 // CompositeTunerOptions are the options used by NewCompositeTuner.
 //
@@ -180,6 +317,10 @@ type CompositeTunerOptions struct {
 	NexusSlotSupplier SlotSupplier
 	// SessionActivitySlotSupplier is the SlotSupplier used for activities within sessions.
 	SessionActivitySlotSupplier SlotSupplier
+	// SlotReservationObserver, if set, receives an event for every slot reservation decision.
+	//
+	// NOTE: Experimental
+	SlotReservationObserver SlotReservationObserver
 }
 
 // NewCompositeTuner creates a WorkerTuner that uses a combination of slot suppliers.
@@ -191,6 +332,7 @@ func NewCompositeTuner(options CompositeTunerOptions) (WorkerTuner, error) {
 		localActivitySlotSupplier:   options.LocalActivitySlotSupplier,
 		nexusSlotSupplier:           options.NexusSlotSupplier,
 		sessionActivitySlotSupplier: options.SessionActivitySlotSupplier,
+		slotReservationObserver:     options.SlotReservationObserver,
 	}, nil
 }
 
@@ -254,10 +396,15 @@ func NewFixedSizeTuner(options FixedSizeTunerOptions) (WorkerTuner, error) {
 }
 
 // FixedSizeSlotSupplier is a slot supplier that will only ever issue at most a fixed number of
-// slots.
+// slots. The number of slots can be changed while workers use it, see
+// [go.temporal.io/sdk/worker.Reconfigure].
 type FixedSizeSlotSupplier struct {
+	lock     sync.Mutex
 	numSlots int
-	sem      *semaphore.Weighted
+	issued   int
+	// waiters are the channels of waiting reservations, closed when a slot is issued to them in the
+	// order they started waiting
+	waiters []chan struct{}
 }
 
 // NewFixedSizeSlotSupplier creates a new FixedSizeSlotSupplier with the given number of slots.
@@ -269,33 +416,87 @@ func NewFixedSizeSlotSupplier(numSlots int) (*FixedSizeSlotSupplier, error) {
 	}
 	return &FixedSizeSlotSupplier{
 		numSlots: numSlots,
-		sem:      semaphore.NewWeighted(int64(numSlots)),
 	}, nil
 }
 
 func (f *FixedSizeSlotSupplier) ReserveSlot(ctx context.Context, _ SlotReservationInfo) (
 	*SlotPermit, error) {
-	err := f.sem.Acquire(ctx, 1)
-	if err != nil {
-		return nil, fmt.Errorf("failed to acquire slot: %w", err)
+	f.lock.Lock()
+	if len(f.waiters) == 0 && f.issued < f.numSlots {
+		f.issued++
+		f.lock.Unlock()
+		return &SlotPermit{}, nil
+	}
+	granted := make(chan struct{})
+	f.waiters = append(f.waiters, granted)
+	f.lock.Unlock()
+
+	select {
+	case <-granted:
+		return &SlotPermit{}, nil
+	case <-ctx.Done():
+		f.lock.Lock()
+		defer f.lock.Unlock()
+		select {
+		case <-granted:
+			// Granted concurrently with cancellation, give the slot to the next waiter
+			f.issued--
+			f.grantWaiters()
+		default:
+			for i, w := range f.waiters {
+				if w == granted {
+					f.waiters = append(f.waiters[:i], f.waiters[i+1:]...)
+					break
+				}
+			}
+		}
+		return nil, fmt.Errorf("failed to acquire slot: %w", ctx.Err())
 	}
-
-	return &SlotPermit{}, nil
 }
 func (f *FixedSizeSlotSupplier) TryReserveSlot(SlotReservationInfo) *SlotPermit {
-	if f.sem.TryAcquire(1) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if len(f.waiters) == 0 && f.issued < f.numSlots {
+		f.issued++
 		return &SlotPermit{}
 	}
 	return nil
 }
 func (f *FixedSizeSlotSupplier) MarkSlotUsed(SlotMarkUsedInfo) {}
 func (f *FixedSizeSlotSupplier) ReleaseSlot(SlotReleaseInfo) {
-	f.sem.Release(1)
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.issued == 0 {
+		panic("FixedSizeSlotSupplier: released more slots than were issued")
+	}
+	f.issued--
+	f.grantWaiters()
 }
 func (f *FixedSizeSlotSupplier) MaxSlots() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
 	return f.numSlots
 }
 
+// setNumSlots changes the number of slots. Slots already issued beyond a reduced number are not
+// revoked, no more are issued until enough of them have been released.
+func (f *FixedSizeSlotSupplier) setNumSlots(numSlots int) {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	f.numSlots = numSlots
+	f.grantWaiters()
+}
+
+// grantWaiters issues the free slots to waiting reservations, first come first served. Must be
+// called with the lock held.
+func (f *FixedSizeSlotSupplier) grantWaiters() {
+	for len(f.waiters) > 0 && f.issued < f.numSlots {
+		f.issued++
+		close(f.waiters[0])
+		f.waiters = f.waiters[1:]
+	}
+}
+
 type slotReservationData struct {
 	taskQueue string
 }
@@ -307,6 +508,27 @@ type slotReserveInfoImpl struct {
 	issuedSlots    *atomic.Int32
 	logger         log.Logger
 	metrics        metrics.Handler
+	// waitReason, if set, receives the reason recorded with recordSlotWaitReason
+	waitReason *string
+}
+
+// Reasons for which a reservation waits or is denied, which tag the slot reservation metrics.
+const (
+	// slotWaitReasonSlots is waiting for the slot supplier to issue a slot
+	slotWaitReasonSlots = "slots"
+	// slotWaitReasonRateLimit is waiting for an activity type to be back within its rate
+	slotWaitReasonRateLimit = "rate_limit"
+	// slotWaitReasonActivityTypeLimit is waiting for an activity type to be below its concurrency
+	// limit
+	slotWaitReasonActivityTypeLimit = "activity_type_limit"
+)
+
+// recordSlotWaitReason records why a slot supplier wrapping another one holds back a reservation
+// made by a worker.
+func recordSlotWaitReason(info SlotReservationInfo, reason string) {
+	if impl, ok := info.(slotReserveInfoImpl); ok && impl.waitReason != nil {
+		*impl.waitReason = reason
+	}
 }
 
 func (s slotReserveInfoImpl) TaskQueue() string {
@@ -334,15 +556,20 @@ func (s slotReserveInfoImpl) MetricsHandler() metrics.Handler {
 }
 
 type slotMarkUsedContextImpl struct {
-	permit  *SlotPermit
-	logger  log.Logger
-	metrics metrics.Handler
+	permit   *SlotPermit
+	taskInfo SlotTaskInfo
+	logger   log.Logger
+	metrics  metrics.Handler
 }
 
 func (s slotMarkUsedContextImpl) Permit() *SlotPermit {
 	return s.permit
 }
 
+func (s slotMarkUsedContextImpl) TaskInfo() SlotTaskInfo {
+	return s.taskInfo
+}
+
 func (s slotMarkUsedContextImpl) Logger() log.Logger {
 	return s.logger
 }
@@ -380,13 +607,20 @@ type trackingSlotSupplier struct {
 	metrics        metrics.Handler
 	workerBuildId  string
 	workerIdentity string
+	taskType       WorkerTaskType
+	observer       SlotReservationObserver
 
 	issuedSlotsAtomic atomic.Int32
 	slotsMutex        sync.Mutex
 	// Values should eventually become slot info types
-	usedSlots               map[*SlotPermit]struct{}
+	usedSlots map[*SlotPermit]struct{}
+	// atMaxSince is when all slots were last issued, zero while some are not
+	atMaxSince              time.Time
 	taskSlotsAvailableGauge metrics.Gauge
 	taskSlotsUsedGauge      metrics.Gauge
+	taskSlotsAtMaxTimer     metrics.Timer
+	// reservationLatency maps wait reasons to the reservation latency timer tagged with them
+	reservationLatency map[string]metrics.Timer
 }
 
 type trackingSlotSupplierOptions struct {
@@ -394,6 +628,9 @@ type trackingSlotSupplierOptions struct {
 	metricsHandler metrics.Handler
 	workerBuildId  string
 	workerIdentity string
+	taskType       WorkerTaskType
+	// observer, if set, receives every reservation decision
+	observer SlotReservationObserver
 }
 
 func newTrackingSlotSupplier(inner SlotSupplier, options trackingSlotSupplierOptions) *trackingSlotSupplier {
@@ -403,9 +640,17 @@ func newTrackingSlotSupplier(inner SlotSupplier, options trackingSlotSupplierOpt
 		metrics:                 options.metricsHandler,
 		workerBuildId:           options.workerBuildId,
 		workerIdentity:          options.workerIdentity,
+		taskType:                options.taskType,
+		observer:                options.observer,
 		usedSlots:               make(map[*SlotPermit]struct{}),
 		taskSlotsAvailableGauge: options.metricsHandler.Gauge(metrics.WorkerTaskSlotsAvailable),
 		taskSlotsUsedGauge:      options.metricsHandler.Gauge(metrics.WorkerTaskSlotsUsed),
+		taskSlotsAtMaxTimer:     options.metricsHandler.Timer(metrics.WorkerTaskSlotsAtMaxDuration),
+		reservationLatency:      make(map[string]metrics.Timer),
+	}
+	for _, reason := range []string{slotWaitReasonSlots, slotWaitReasonRateLimit, slotWaitReasonActivityTypeLimit} {
+		tss.reservationLatency[reason] = options.metricsHandler.
+			WithTags(metrics.SlotReservationWaitTags(reason)).Timer(metrics.WorkerTaskSlotReservationLatency)
 	}
 	return tss
 }
@@ -414,6 +659,8 @@ func (t *trackingSlotSupplier) ReserveSlot(
 	ctx context.Context,
 	data *slotReservationData,
 ) (*SlotPermit, error) {
+	start := time.Now()
+	waitReason := slotWaitReasonSlots
 	permit, err := t.inner.ReserveSlot(ctx, slotReserveInfoImpl{
 		taskQueue:      data.taskQueue,
 		workerBuildId:  t.workerBuildId,
@@ -421,22 +668,26 @@ func (t *trackingSlotSupplier) ReserveSlot(
 		issuedSlots:    &t.issuedSlotsAtomic,
 		logger:         t.logger,
 		metrics:        t.metrics,
+		waitReason:     &waitReason,
 	})
+	if err == nil && permit == nil {
+		err = fmt.Errorf("slot supplier returned nil permit")
+	}
 	if err != nil {
+		outcome := SlotReservationOutcomeFailed
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			outcome = SlotReservationOutcomeCanceled
+		}
+		t.recordReservation(data, false, outcome, waitReason, err, time.Since(start))
 		return nil, err
 	}
-	if permit == nil {
-		return nil, fmt.Errorf("slot supplier returned nil permit")
-	}
 	t.issuedSlotsAtomic.Add(1)
-	t.slotsMutex.Lock()
-	usedSlots := len(t.usedSlots)
-	t.slotsMutex.Unlock()
-	t.publishMetrics(usedSlots)
+	t.recordReservation(data, false, SlotReservationOutcomeReserved, waitReason, nil, time.Since(start))
 	return permit, nil
 }
 
 func (t *trackingSlotSupplier) TryReserveSlot(data *slotReservationData) *SlotPermit {
+	waitReason := slotWaitReasonSlots
 	permit := t.inner.TryReserveSlot(slotReserveInfoImpl{
 		taskQueue:      data.taskQueue,
 		workerBuildId:  t.workerBuildId,
@@ -444,18 +695,66 @@ func (t *trackingSlotSupplier) TryReserveSlot(data *slotReservationData) *SlotPe
 		issuedSlots:    &t.issuedSlotsAtomic,
 		logger:         t.logger,
 		metrics:        t.metrics,
+		waitReason:     &waitReason,
 	})
-	if permit != nil {
-		t.issuedSlotsAtomic.Add(1)
-		t.slotsMutex.Lock()
-		usedSlots := len(t.usedSlots)
-		t.slotsMutex.Unlock()
-		t.publishMetrics(usedSlots)
+	if permit == nil {
+		t.recordReservation(data, true, SlotReservationOutcomeUnavailable, waitReason, nil, 0)
+		return nil
 	}
+	t.issuedSlotsAtomic.Add(1)
+	t.recordReservation(data, true, SlotReservationOutcomeReserved, waitReason, nil, 0)
 	return permit
 }
 
-func (t *trackingSlotSupplier) MarkSlotUsed(permit *SlotPermit) {
+// recordReservation publishes the metrics of a reservation decision and sends it to the observer.
+// The latency of reservations is tagged with the reason they waited for, and reservations denied
+// by a rate or activity type limit are counted with it as their reason. Reservations canceled by
+// the worker, usually when shutting down, are not counted as denied.
+func (t *trackingSlotSupplier) recordReservation(
+	data *slotReservationData,
+	eager bool,
+	outcome SlotReservationOutcome,
+	waitReason string,
+	err error,
+	waitTime time.Duration,
+) {
+	switch outcome {
+	case SlotReservationOutcomeReserved:
+		if !eager {
+			t.reservationLatency[waitReason].Record(waitTime)
+		}
+	case SlotReservationOutcomeCanceled:
+	default:
+		reason := outcome.String()
+		if waitReason != slotWaitReasonSlots {
+			reason = waitReason
+		}
+		t.metrics.WithTags(metrics.SlotReservationDeniedTags(reason)).
+			Counter(metrics.WorkerTaskSlotReservationDenied).Inc(1)
+	}
+	t.slotsMutex.Lock()
+	usedSlots := len(t.usedSlots)
+	t.slotsMutex.Unlock()
+	if outcome == SlotReservationOutcomeReserved {
+		t.publishMetrics(usedSlots)
+	}
+	if t.observer != nil {
+		t.observer.OnSlotReservation(SlotReservationEvent{
+			TaskType:         t.taskType,
+			SlotSupplierKind: t.GetSlotSupplierKind(),
+			TaskQueue:        data.taskQueue,
+			Eager:            eager,
+			Outcome:          outcome,
+			Err:              err,
+			WaitTime:         waitTime,
+			IssuedSlots:      int(t.issuedSlotsAtomic.Load()),
+			UsedSlots:        usedSlots,
+			MaxSlots:         t.inner.MaxSlots(),
+		})
+	}
+}
+
+func (t *trackingSlotSupplier) MarkSlotUsed(permit *SlotPermit, taskInfo SlotTaskInfo) {
 	if permit == nil {
 		panic("Cannot mark nil permit as used")
 	}
@@ -464,9 +763,10 @@ func (t *trackingSlotSupplier) MarkSlotUsed(permit *SlotPermit) {
 	usedSlots := len(t.usedSlots)
 	t.slotsMutex.Unlock()
 	t.inner.MarkSlotUsed(&slotMarkUsedContextImpl{
-		permit:  permit,
-		logger:  t.logger,
-		metrics: t.metrics,
+		permit:   permit,
+		taskInfo: taskInfo,
+		logger:   t.logger,
+		metrics:  t.metrics,
 	})
 	t.publishMetrics(usedSlots)
 }
@@ -494,10 +794,25 @@ func (t *trackingSlotSupplier) ReleaseSlot(permit *SlotPermit, reason SlotReleas
 }
 
 func (t *trackingSlotSupplier) publishMetrics(usedSlots int) {
-	if t.inner.MaxSlots() != 0 {
-		t.taskSlotsAvailableGauge.Update(float64(t.inner.MaxSlots() - usedSlots))
+	maxSlots := t.inner.MaxSlots()
+	if maxSlots != 0 {
+		t.taskSlotsAvailableGauge.Update(float64(maxSlots - usedSlots))
 	}
 	t.taskSlotsUsedGauge.Update(float64(usedSlots))
+	t.updateAtMax(maxSlots)
+}
+
+// updateAtMax records how long all slots were issued each time some become available again.
+func (t *trackingSlotSupplier) updateAtMax(maxSlots int) {
+	t.slotsMutex.Lock()
+	defer t.slotsMutex.Unlock()
+	atMax := maxSlots != 0 && int(t.issuedSlotsAtomic.Load()) >= maxSlots
+	if atMax && t.atMaxSince.IsZero() {
+		t.atMaxSince = time.Now()
+	} else if !atMax && !t.atMaxSince.IsZero() {
+		t.taskSlotsAtMaxTimer.Record(time.Since(t.atMaxSince))
+		t.atMaxSince = time.Time{}
+	}
 }
 
 func (t *trackingSlotSupplier) GetSlotSupplierKind() string {
@@ -0,0 +1,593 @@
+package internal
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// defaultPriorityKey is the priority key of tasks without one when the server uses its default
+// range of 1 to 5.
+const defaultPriorityKey = 3
+
+// RateLimitedSlotSupplierOptions are the options used by NewRateLimitedSlotSupplier.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RateLimitedSlotSupplierOptions]
+type RateLimitedSlotSupplierOptions struct {
+	// SlotSupplier issues the slots whose use is rate limited. Required.
+	SlotSupplier SlotSupplier
+	// TasksPerSecond is the maximum rate at which tasks of each activity type may start. Zero
+	// means activity types without an entry in ActivityTypeTasksPerSecond are not limited.
+	TasksPerSecond float64
+	// ActivityTypeTasksPerSecond overrides TasksPerSecond for individual activity types.
+	ActivityTypeTasksPerSecond map[string]float64
+	// Burst is the number of tasks of each activity type that may start at once after a period
+	// without any. Defaults to 1.
+	Burst int
+}
+
+// RateLimitedSlotSupplier is a SlotSupplier that limits the rate at which tasks of each activity
+// type start, using a token bucket per activity type. Slots are issued by the wrapped
+// SlotSupplier. Because slots are reserved before a task is polled, and so before its activity
+// type is known, a task takes a token from the bucket of its type when its slot is marked used,
+// even if the bucket is empty. New reservations for the task queues that the type was polled
+// from then wait until its bucket has a token again, so while an activity type is over its rate
+// the workers of those task queues stop polling and the rest of the backlog is dispatched to
+// other workers. Reservations for other task queues are not held back. Tasks of a type may exceed
+// its rate by up to the number of polls in flight when the bucket ran out, and the excess is paid
+// back before the next reservation. Tasks without an activity type, such as workflow tasks, are
+// not limited.
+//
+// Activity types that share a task queue with a type over its rate are held back with it, so
+// give each rate limited activity type a task queue of its own, with its own worker, and share
+// one RateLimitedSlotSupplier between the tuners of the workers.
+//
+// Exposed as: [go.temporal.io/sdk/worker.RateLimitedSlotSupplier]
+type RateLimitedSlotSupplier struct {
+	inner          SlotSupplier
+	tasksPerSecond float64
+	perType        map[string]float64
+	burst          int
+
+	lock     sync.Mutex
+	limiters map[string]*rate.Limiter
+	// queueTypes holds the activity types with a limiter that were polled from each task queue,
+	// and permitQueues the task queue that each issued permit was reserved for.
+	queueTypes   map[string]map[string]struct{}
+	permitQueues map[*SlotPermit]string
+}
+
+// NewRateLimitedSlotSupplier creates a RateLimitedSlotSupplier with the given options.
+//
+// Exposed as: [go.temporal.io/sdk/worker.NewRateLimitedSlotSupplier]
+func NewRateLimitedSlotSupplier(options RateLimitedSlotSupplierOptions) (*RateLimitedSlotSupplier, error) {
+	if options.SlotSupplier == nil {
+		return nil, errors.New("SlotSupplier is required")
+	}
+	if options.TasksPerSecond < 0 {
+		return nil, errors.New("TasksPerSecond must be non-negative")
+	}
+	perType := make(map[string]float64, len(options.ActivityTypeTasksPerSecond))
+	for activityType, tps := range options.ActivityTypeTasksPerSecond {
+		if tps <= 0 {
+			return nil, fmt.Errorf("tasks per second for activity type %q must be positive", activityType)
+		}
+		perType[activityType] = tps
+	}
+	if options.Burst < 0 {
+		return nil, errors.New("Burst must be non-negative")
+	}
+	burst := options.Burst
+	if burst == 0 {
+		burst = 1
+	}
+	return &RateLimitedSlotSupplier{
+		inner:          options.SlotSupplier,
+		tasksPerSecond: options.TasksPerSecond,
+		perType:        perType,
+		burst:          burst,
+		limiters:       make(map[string]*rate.Limiter),
+		queueTypes:     make(map[string]map[string]struct{}),
+		permitQueues:   make(map[*SlotPermit]string),
+	}, nil
+}
+
+func (r *RateLimitedSlotSupplier) ReserveSlot(ctx context.Context, info SlotReservationInfo) (*SlotPermit, error) {
+	for {
+		delay := r.tokenDelay(info.TaskQueue(), time.Now())
+		if delay <= 0 {
+			break
+		}
+		recordSlotWaitReason(info, slotWaitReasonRateLimit)
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, fmt.Errorf("failed to acquire slot: %w", ctx.Err())
+		}
+	}
+	permit, err := r.inner.ReserveSlot(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+	r.issued(permit, info.TaskQueue())
+	return permit, nil
+}
+
+func (r *RateLimitedSlotSupplier) TryReserveSlot(info SlotReservationInfo) *SlotPermit {
+	if r.tokenDelay(info.TaskQueue(), time.Now()) > 0 {
+		recordSlotWaitReason(info, slotWaitReasonRateLimit)
+		return nil
+	}
+	permit := r.inner.TryReserveSlot(info)
+	if permit != nil {
+		r.issued(permit, info.TaskQueue())
+	}
+	return permit
+}
+
+func (r *RateLimitedSlotSupplier) MarkSlotUsed(info SlotMarkUsedInfo) {
+	if limiter := r.used(info.Permit(), info.TaskInfo().ActivityType); limiter != nil {
+		// Never waits, a task over the rate delays the following reservations instead
+		limiter.Reserve()
+	}
+	r.inner.MarkSlotUsed(info)
+}
+
+func (r *RateLimitedSlotSupplier) ReleaseSlot(info SlotReleaseInfo) {
+	r.lock.Lock()
+	delete(r.permitQueues, info.Permit())
+	r.lock.Unlock()
+	r.inner.ReleaseSlot(info)
+}
+
+func (r *RateLimitedSlotSupplier) MaxSlots() int {
+	return r.inner.MaxSlots()
+}
+
+func (r *RateLimitedSlotSupplier) issued(permit *SlotPermit, taskQueue string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	r.permitQueues[permit] = taskQueue
+}
+
+// used records that the activity type was polled from the task queue of the permit, and returns
+// the limiter for the type, or nil if it is not limited.
+func (r *RateLimitedSlotSupplier) used(permit *SlotPermit, activityType string) *rate.Limiter {
+	if activityType == "" {
+		return nil
+	}
+	tps, ok := r.perType[activityType]
+	if !ok {
+		tps = r.tasksPerSecond
+	}
+	if tps == 0 {
+		return nil
+	}
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	limiter := r.limiters[activityType]
+	if limiter == nil {
+		limiter = rate.NewLimiter(rate.Limit(tps), r.burst)
+		r.limiters[activityType] = limiter
+	}
+	if taskQueue, ok := r.permitQueues[permit]; ok {
+		types := r.queueTypes[taskQueue]
+		if types == nil {
+			types = make(map[string]struct{})
+			r.queueTypes[taskQueue] = types
+		}
+		types[activityType] = struct{}{}
+	}
+	return limiter
+}
+
+// tokenDelay returns how long from now until the limiter of every activity type polled from the
+// task queue has a token.
+func (r *RateLimitedSlotSupplier) tokenDelay(taskQueue string, now time.Time) time.Duration {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	var delay time.Duration
+	for activityType := range r.queueTypes[taskQueue] {
+		limiter := r.limiters[activityType]
+		if tokens := limiter.TokensAt(now); tokens < 1 {
+			delay = max(delay, time.Duration((1-tokens)/float64(limiter.Limit())*float64(time.Second)))
+		}
+	}
+	return delay
+}
+
+// PrioritySlotSupplierOptions are the options used by NewPrioritySlotSupplier.
+//
+// Exposed as: [go.temporal.io/sdk/worker.PrioritySlotSupplierOptions]
+type PrioritySlotSupplierOptions struct {
+	// NumSlots is the total number of slots the supplier will issue. Required.
+	NumSlots int
+	// ReservedSlots maps a priority key to a number of slots that only reservations with that
+	// priority key or a smaller (higher priority) one may use. For example, with NumSlots of 100
+	// and ReservedSlots of {1: 20, 2: 10}, reservations with priority key 1 may use all 100
+	// slots, priority key 2 may use 80, and all others may use 70. The reserved slots must total
+	// less than NumSlots.
+	ReservedSlots map[int]int
+	// Priority returns the priority of a reservation. Slots are reserved before a task is polled,
+	// so the priority cannot come from the task itself and is typically derived from
+	// SlotReservationInfo.TaskQueue. Only PriorityKey is used, and a PriorityKey of zero is
+	// treated as the default of 3. If unset, every reservation has the default priority.
+	Priority func(info SlotReservationInfo) Priority
+}
+
+// PrioritySlotSupplier is a SlotSupplier that issues a fixed number of slots while holding some
+// of them back for higher priority reservations. When slots are scarce, waiting reservations are
+// served in priority order. Share one PrioritySlotSupplier between the tuners of several workers
+// in the same process to let latency-critical task queues use capacity that batch task queues
+// cannot exhaust.
+//
+// Exposed as: [go.temporal.io/sdk/worker.PrioritySlotSupplier]
+type PrioritySlotSupplier struct {
+	numSlots int
+	// keys holds the priority keys with reserved slots in ascending order, and reservedAbove the
+	// number of slots reserved for priority keys smaller than each of them.
+	keys          []int
+	reservedAbove []int
+	totalReserved int
+	priority      func(info SlotReservationInfo) Priority
+
+	lock    sync.Mutex
+	issued  int
+	waiters []*prioritySlotWaiter
+}
+
+type prioritySlotWaiter struct {
+	key     int
+	granted chan struct{}
+}
+
+// NewPrioritySlotSupplier creates a PrioritySlotSupplier with the given options.
+//
+// Exposed as: [go.temporal.io/sdk/worker.NewPrioritySlotSupplier]
+func NewPrioritySlotSupplier(options PrioritySlotSupplierOptions) (*PrioritySlotSupplier, error) {
+	if options.NumSlots <= 0 {
+		return nil, errors.New("NumSlots must be positive")
+	}
+	var keys []int
+	totalReserved := 0
+	for key, slots := range options.ReservedSlots {
+		if key <= 0 {
+			return nil, fmt.Errorf("priority key %d must be positive", key)
+		}
+		if slots < 0 {
+			return nil, fmt.Errorf("reserved slots for priority key %d must be non-negative", key)
+		}
+		keys = append(keys, key)
+		totalReserved += slots
+	}
+	if totalReserved >= options.NumSlots {
+		return nil, errors.New("ReservedSlots must total less than NumSlots")
+	}
+	sort.Ints(keys)
+	reservedAbove := make([]int, len(keys))
+	for i := 1; i < len(keys); i++ {
+		reservedAbove[i] = reservedAbove[i-1] + options.ReservedSlots[keys[i-1]]
+	}
+	return &PrioritySlotSupplier{
+		numSlots:      options.NumSlots,
+		keys:          keys,
+		reservedAbove: reservedAbove,
+		totalReserved: totalReserved,
+		priority:      options.Priority,
+	}, nil
+}
+
+func (p *PrioritySlotSupplier) ReserveSlot(ctx context.Context, info SlotReservationInfo) (*SlotPermit, error) {
+	key := p.priorityKey(info)
+	p.lock.Lock()
+	if p.canIssue(key) {
+		p.issued++
+		p.lock.Unlock()
+		return &SlotPermit{}, nil
+	}
+	waiter := &prioritySlotWaiter{key: key, granted: make(chan struct{})}
+	p.waiters = append(p.waiters, waiter)
+	p.lock.Unlock()
+
+	select {
+	case <-waiter.granted:
+		return &SlotPermit{}, nil
+	case <-ctx.Done():
+		p.lock.Lock()
+		defer p.lock.Unlock()
+		if !p.removeWaiter(waiter) {
+			// Granted concurrently with cancellation, give the slot to someone else
+			p.issued--
+			p.grantWaiters()
+		}
+		return nil, fmt.Errorf("failed to acquire slot: %w", ctx.Err())
+	}
+}
+
+func (p *PrioritySlotSupplier) TryReserveSlot(info SlotReservationInfo) *SlotPermit {
+	key := p.priorityKey(info)
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	if p.canIssue(key) {
+		p.issued++
+		return &SlotPermit{}
+	}
+	return nil
+}
+
+func (p *PrioritySlotSupplier) MarkSlotUsed(SlotMarkUsedInfo) {}
+
+func (p *PrioritySlotSupplier) ReleaseSlot(SlotReleaseInfo) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	p.issued--
+	p.grantWaiters()
+}
+
+func (p *PrioritySlotSupplier) MaxSlots() int {
+	return p.numSlots
+}
+
+func (p *PrioritySlotSupplier) priorityKey(info SlotReservationInfo) int {
+	if p.priority == nil {
+		return defaultPriorityKey
+	}
+	if key := p.priority(info).PriorityKey; key > 0 {
+		return key
+	}
+	return defaultPriorityKey
+}
+
+// limit returns the number of slots that reservations with the given priority key may use.
+func (p *PrioritySlotSupplier) limit(key int) int {
+	for i, k := range p.keys {
+		if key <= k {
+			return p.numSlots - p.reservedAbove[i]
+		}
+	}
+	return p.numSlots - p.totalReserved
+}
+
+// canIssue reports whether a new reservation with the given priority key may be issued a slot
+// without waiting. Must be called with the lock held.
+func (p *PrioritySlotSupplier) canIssue(key int) bool {
+	for _, w := range p.waiters {
+		if w.key <= key {
+			return false
+		}
+	}
+	return p.issued < p.limit(key)
+}
+
+// grantWaiters issues slots to waiting reservations in priority order, first come first served
+// within a priority, for as long as the highest priority waiter fits. Must be called with the
+// lock held.
+func (p *PrioritySlotSupplier) grantWaiters() {
+	for len(p.waiters) > 0 {
+		best := 0
+		for i, w := range p.waiters {
+			if w.key < p.waiters[best].key {
+				best = i
+			}
+		}
+		waiter := p.waiters[best]
+		if p.issued >= p.limit(waiter.key) {
+			return
+		}
+		p.issued++
+		p.removeWaiter(waiter)
+		close(waiter.granted)
+	}
+}
+
+// removeWaiter removes the waiter, returning false if it was not waiting. Must be called with the
+// lock held.
+func (p *PrioritySlotSupplier) removeWaiter(waiter *prioritySlotWaiter) bool {
+	for i, w := range p.waiters {
+		if w == waiter {
+			p.waiters = append(p.waiters[:i], p.waiters[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// ActivityTypeConcurrencySlotSupplierOptions are the options used by
+// NewActivityTypeConcurrencySlotSupplier.
+//
+// Exposed as: [go.temporal.io/sdk/worker.ActivityTypeConcurrencySlotSupplierOptions]
+type ActivityTypeConcurrencySlotSupplierOptions struct {
+	// SlotSupplier issues the slots. Required.
+	SlotSupplier SlotSupplier
+	// MaxConcurrentExecutions maps an activity type to the maximum number of its tasks that may run
+	// at once. Activity types without an entry are not limited beyond SlotSupplier.
+	MaxConcurrentExecutions map[string]int
+}
+
+// ActivityTypeConcurrencySlotSupplier is a SlotSupplier that caps the number of tasks of each
+// activity type that run at once. Slots are issued by the wrapped SlotSupplier. Because slots are
+// reserved before a task is polled, and so before its activity type is known, the cap is applied
+// when slots are reserved: a slot is only reserved if the task it is used for cannot exceed the cap
+// of any activity type, counting every reserved slot that has no task yet as a possible task of
+// each capped type. So while an activity type is at its cap the worker stops polling, and the rest
+// of the backlog is dispatched to other workers, but tasks never wait after being polled.
+//
+// This costs throughput: while any capped activity type is at its cap, no task of any type is
+// polled, and slots reserved for polls in flight count against every cap, so there are never more
+// polls in flight than the smallest cap. Wrap only the slot supplier of a worker polling a task
+// queue dedicated to the capped activity types. Workers with
+// WorkerOptions.EnableActivityTypeConcurrencyLimits set apply the caps set with
+// WorkerOptions.MaxConcurrentActivityTypeExecutionSize and
+// RegisterActivityOptions.MaxConcurrentExecutions this way to the activity slot supplier of their
+// tuner.
+//
+// Exposed as: [go.temporal.io/sdk/worker.ActivityTypeConcurrencySlotSupplier]
+type ActivityTypeConcurrencySlotSupplier struct {
+	inner  SlotSupplier
+	limits map[string]int
+
+	lock sync.Mutex
+	// changed is closed and replaced whenever a slot is marked used or released
+	changed chan struct{}
+	// pending counts the slots reserved, or being reserved, that have no task yet
+	pending  int
+	reserved map[*SlotPermit]struct{}
+	// running counts the tasks of every activity type, capped or not, so that caps set later apply
+	// to tasks already running
+	running map[string]int
+	permits map[*SlotPermit]string
+}
+
+// NewActivityTypeConcurrencySlotSupplier creates an ActivityTypeConcurrencySlotSupplier with the
+// given options.
+//
+// Exposed as: [go.temporal.io/sdk/worker.NewActivityTypeConcurrencySlotSupplier]
+func NewActivityTypeConcurrencySlotSupplier(
+	options ActivityTypeConcurrencySlotSupplierOptions,
+) (*ActivityTypeConcurrencySlotSupplier, error) {
+	if options.SlotSupplier == nil {
+		return nil, errors.New("SlotSupplier is required")
+	}
+	limits := make(map[string]int, len(options.MaxConcurrentExecutions))
+	for activityType, limit := range options.MaxConcurrentExecutions {
+		if limit <= 0 {
+			return nil, fmt.Errorf("max concurrent executions for activity type %q must be positive", activityType)
+		}
+		limits[activityType] = limit
+	}
+	return newActivityTypeConcurrencySlotSupplier(options.SlotSupplier, limits), nil
+}
+
+// newActivityTypeConcurrencySlotSupplier creates an ActivityTypeConcurrencySlotSupplier with the
+// given caps, which must be positive and must not be modified afterwards. The caps can be replaced
+// with setLimits.
+func newActivityTypeConcurrencySlotSupplier(
+	inner SlotSupplier,
+	limits map[string]int,
+) *ActivityTypeConcurrencySlotSupplier {
+	return &ActivityTypeConcurrencySlotSupplier{
+		inner:    inner,
+		limits:   limits,
+		changed:  make(chan struct{}),
+		reserved: make(map[*SlotPermit]struct{}),
+		running:  make(map[string]int),
+		permits:  make(map[*SlotPermit]string),
+	}
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) ReserveSlot(ctx context.Context, info SlotReservationInfo) (*SlotPermit, error) {
+	for {
+		s.lock.Lock()
+		if s.hasRoomLocked() {
+			s.pending++
+			s.lock.Unlock()
+			break
+		}
+		changed := s.changed
+		s.lock.Unlock()
+		recordSlotWaitReason(info, slotWaitReasonActivityTypeLimit)
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return nil, fmt.Errorf("failed to acquire slot: %w", ctx.Err())
+		}
+	}
+	permit, err := s.inner.ReserveSlot(ctx, info)
+	s.finishReservation(permit)
+	return permit, err
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) TryReserveSlot(info SlotReservationInfo) *SlotPermit {
+	s.lock.Lock()
+	if !s.hasRoomLocked() {
+		s.lock.Unlock()
+		recordSlotWaitReason(info, slotWaitReasonActivityTypeLimit)
+		return nil
+	}
+	s.pending++
+	s.lock.Unlock()
+	permit := s.inner.TryReserveSlot(info)
+	s.finishReservation(permit)
+	return permit
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) MarkSlotUsed(info SlotMarkUsedInfo) {
+	s.lock.Lock()
+	if _, ok := s.reserved[info.Permit()]; ok {
+		delete(s.reserved, info.Permit())
+		s.pending--
+		activityType := info.TaskInfo().ActivityType
+		s.running[activityType]++
+		s.permits[info.Permit()] = activityType
+		s.notifyLocked()
+	}
+	s.lock.Unlock()
+	s.inner.MarkSlotUsed(info)
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) ReleaseSlot(info SlotReleaseInfo) {
+	s.lock.Lock()
+	if _, ok := s.reserved[info.Permit()]; ok {
+		delete(s.reserved, info.Permit())
+		s.pending--
+		s.notifyLocked()
+	} else if activityType, ok := s.permits[info.Permit()]; ok {
+		delete(s.permits, info.Permit())
+		if s.running[activityType]--; s.running[activityType] == 0 {
+			delete(s.running, activityType)
+		}
+		s.notifyLocked()
+	}
+	s.lock.Unlock()
+	s.inner.ReleaseSlot(info)
+}
+
+// hasRoomLocked reports whether another slot may be reserved, that is whether a task of any
+// capped activity type could start in it even if every pending slot gets a task of that type.
+func (s *ActivityTypeConcurrencySlotSupplier) hasRoomLocked() bool {
+	for activityType, limit := range s.limits {
+		if s.running[activityType]+s.pending >= limit {
+			return false
+		}
+	}
+	return true
+}
+
+// setLimits replaces the caps, which must be positive and must not be modified afterwards. Tasks
+// already running are not interrupted, slots are reserved again once their activity types are
+// below the new caps.
+func (s *ActivityTypeConcurrencySlotSupplier) setLimits(limits map[string]int) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	s.limits = limits
+	s.notifyLocked()
+}
+
+// finishReservation records the permit returned by the wrapped supplier for a slot admitted by
+// ReserveSlot or TryReserveSlot, or gives the admission back if there is none.
+func (s *ActivityTypeConcurrencySlotSupplier) finishReservation(permit *SlotPermit) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+	if permit == nil {
+		s.pending--
+		s.notifyLocked()
+		return
+	}
+	s.reserved[permit] = struct{}{}
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) notifyLocked() {
+	close(s.changed)
+	s.changed = make(chan struct{})
+}
+
+func (s *ActivityTypeConcurrencySlotSupplier) MaxSlots() int {
+	return s.inner.MaxSlots()
+}
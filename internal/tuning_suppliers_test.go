@@ -0,0 +1,437 @@
+package internal
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"go.temporal.io/sdk/internal/common/metrics"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+func reserveInfo(taskQueue string) SlotReservationInfo {
+	return slotReserveInfoImpl{taskQueue: taskQueue, issuedSlots: &atomic.Int32{}}
+}
+
+func TestFixedSizeSlotSupplierResize(t *testing.T) {
+	supplier, err := NewFixedSizeSlotSupplier(2)
+	require.NoError(t, err)
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+
+	reserved := make(chan struct{})
+	go func() {
+		_, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+		require.NoError(t, err)
+		close(reserved)
+	}()
+	supplier.setNumSlots(3)
+	require.Equal(t, 3, supplier.MaxSlots())
+	select {
+	case <-reserved:
+	case <-time.After(time.Second):
+		t.Fatal("waiting reservation was not granted after the number of slots grew")
+	}
+
+	// Issued slots are kept when shrinking, new ones wait until enough are released.
+	supplier.setNumSlots(1)
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+}
+
+func TestFixedSizeSlotSupplierFirstComeFirstServed(t *testing.T) {
+	supplier, err := NewFixedSizeSlotSupplier(1)
+	require.NoError(t, err)
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+
+	granted := make(chan int, 3)
+	canceledCtx, cancel := context.WithCancel(context.Background())
+	for i := range 3 {
+		ctx := context.Background()
+		if i == 1 {
+			ctx = canceledCtx
+		}
+		go func() {
+			if _, err := supplier.ReserveSlot(ctx, reserveInfo("tq")); err == nil {
+				granted <- i
+			}
+		}()
+		require.Eventually(t, func() bool { return supplier.numWaiters() == i+1 }, time.Second, time.Millisecond)
+	}
+	cancel()
+	require.Eventually(t, func() bool { return supplier.numWaiters() == 2 }, time.Second, time.Millisecond)
+
+	// Waiting reservations are granted one at a time, in order, and before new ones.
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Equal(t, 0, <-granted)
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Equal(t, 2, <-granted)
+	require.Empty(t, granted)
+
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.PanicsWithValue(t, "FixedSizeSlotSupplier: released more slots than were issued", func() {
+		supplier.ReleaseSlot(slotReleaseContextImpl{})
+	})
+}
+
+func (f *FixedSizeSlotSupplier) numWaiters() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	return len(f.waiters)
+}
+
+func TestRateLimitedSlotSupplier(t *testing.T) {
+	inner, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	supplier, err := NewRateLimitedSlotSupplier(RateLimitedSlotSupplierOptions{
+		SlotSupplier:               inner,
+		ActivityTypeTasksPerSecond: map[string]float64{"Slow": 10},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 10, supplier.MaxSlots())
+
+	start := func(activityType string) time.Duration {
+		begin := time.Now()
+		permit, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+		require.NoError(t, err)
+		supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: activityType}})
+		supplier.ReleaseSlot(slotReleaseContextImpl{permit: permit})
+		return time.Since(begin)
+	}
+
+	// Unlimited types and the first task of a limited type start immediately.
+	require.Less(t, start("Fast"), 50*time.Millisecond)
+	require.Less(t, start("Fast"), 50*time.Millisecond)
+	require.Less(t, start("Slow"), 50*time.Millisecond)
+	// The next reservation waits for the bucket of the limited type to refill.
+	require.GreaterOrEqual(t, start("Slow"), 50*time.Millisecond)
+
+	// Marking a slot used never waits, even when the bucket is empty, and waiting reservations
+	// give up when their context is done.
+	permit, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+	require.NoError(t, err)
+	begin := time.Now()
+	supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: "Slow"}})
+	supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: "Slow"}})
+	require.Less(t, time.Since(begin), 50*time.Millisecond)
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = supplier.ReserveSlot(ctx, reserveInfo("tq"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// Other task queues are not held back by the limited type.
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("other")))
+
+	_, err = NewRateLimitedSlotSupplier(RateLimitedSlotSupplierOptions{})
+	require.EqualError(t, err, "SlotSupplier is required")
+	_, err = NewRateLimitedSlotSupplier(RateLimitedSlotSupplierOptions{
+		SlotSupplier:               inner,
+		ActivityTypeTasksPerSecond: map[string]float64{"Slow": 0},
+	})
+	require.EqualError(t, err, `tasks per second for activity type "Slow" must be positive`)
+}
+
+func TestRateLimitedSlotSupplierSlowTypeDoesNotDelayOtherTypes(t *testing.T) {
+	inner, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	supplier, err := NewRateLimitedSlotSupplier(RateLimitedSlotSupplierOptions{
+		SlotSupplier:               inner,
+		TasksPerSecond:             1000,
+		ActivityTypeTasksPerSecond: map[string]float64{"Batch": 0.1},
+	})
+	require.NoError(t, err)
+
+	// Exhaust the bucket of the batch type on its own task queue.
+	permit := supplier.TryReserveSlot(reserveInfo("batch"))
+	require.NotNil(t, permit)
+	supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: "Batch"}})
+	supplier.ReleaseSlot(slotReleaseContextImpl{permit: permit})
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("batch")))
+
+	// Tasks of another limited type keep starting at their own rate.
+	begin := time.Now()
+	for range 10 {
+		permit, err := supplier.ReserveSlot(context.Background(), reserveInfo("api"))
+		require.NoError(t, err)
+		supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: "Api"}})
+		supplier.ReleaseSlot(slotReleaseContextImpl{permit: permit})
+	}
+	require.Less(t, time.Since(begin), time.Second)
+}
+
+func TestPrioritySlotSupplier(t *testing.T) {
+	supplier, err := NewPrioritySlotSupplier(PrioritySlotSupplierOptions{
+		NumSlots:      4,
+		ReservedSlots: map[int]int{1: 1, 2: 1},
+		Priority: func(info SlotReservationInfo) Priority {
+			switch info.TaskQueue() {
+			case "critical":
+				return Priority{PriorityKey: 1}
+			case "high":
+				return Priority{PriorityKey: 2}
+			default:
+				return Priority{}
+			}
+		},
+	})
+	require.NoError(t, err)
+
+	// Default priority may only use the unreserved slots.
+	var permits []*SlotPermit
+	for i := 0; i < 2; i++ {
+		permit := supplier.TryReserveSlot(reserveInfo("batch"))
+		require.NotNil(t, permit)
+		permits = append(permits, permit)
+	}
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("batch")))
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("high")))
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("high")))
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("critical")))
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("critical")))
+
+	// Waiters are served in priority order regardless of arrival.
+	granted := make(chan string, 2)
+	reserve := func(taskQueue string) {
+		_, err := supplier.ReserveSlot(context.Background(), reserveInfo(taskQueue))
+		require.NoError(t, err)
+		granted <- taskQueue
+	}
+	go reserve("batch")
+	require.Eventually(t, func() bool { return supplier.numWaiters() == 1 }, time.Second, time.Millisecond)
+	go reserve("critical")
+	require.Eventually(t, func() bool { return supplier.numWaiters() == 2 }, time.Second, time.Millisecond)
+
+	supplier.ReleaseSlot(slotReleaseContextImpl{permit: permits[0]})
+	require.Equal(t, "critical", <-granted)
+	supplier.ReleaseSlot(slotReleaseContextImpl{permit: permits[1]})
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Equal(t, 1, supplier.numWaiters())
+	supplier.ReleaseSlot(slotReleaseContextImpl{})
+	require.Equal(t, "batch", <-granted)
+
+	// Cancelled reservations stop waiting.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = supplier.ReserveSlot(ctx, reserveInfo("batch"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	require.Equal(t, 0, supplier.numWaiters())
+
+	_, err = NewPrioritySlotSupplier(PrioritySlotSupplierOptions{})
+	require.EqualError(t, err, "NumSlots must be positive")
+	_, err = NewPrioritySlotSupplier(PrioritySlotSupplierOptions{NumSlots: 2, ReservedSlots: map[int]int{1: 2}})
+	require.EqualError(t, err, "ReservedSlots must total less than NumSlots")
+	_, err = NewPrioritySlotSupplier(PrioritySlotSupplierOptions{NumSlots: 2, ReservedSlots: map[int]int{0: 1}})
+	require.EqualError(t, err, "priority key 0 must be positive")
+}
+
+func (p *PrioritySlotSupplier) numWaiters() int {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+	return len(p.waiters)
+}
+
+func TestActivityTypeConcurrencySlotSupplier(t *testing.T) {
+	inner, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	supplier, err := NewActivityTypeConcurrencySlotSupplier(ActivityTypeConcurrencySlotSupplierOptions{
+		SlotSupplier:            inner,
+		MaxConcurrentExecutions: map[string]int{"Fragile": 2},
+	})
+	require.NoError(t, err)
+	require.Equal(t, "Fixed", getSlotSupplierKind(supplier))
+
+	markUsed := func(permit *SlotPermit, activityType string) {
+		supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: activityType}})
+	}
+
+	// Slots without a task yet count as possible tasks of the capped type.
+	first, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+	require.NoError(t, err)
+	second := supplier.TryReserveSlot(reserveInfo("tq"))
+	require.NotNil(t, second)
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+
+	// Tasks of other types free the room they were counted against.
+	markUsed(first, "Other")
+	third := supplier.TryReserveSlot(reserveInfo("tq"))
+	require.NotNil(t, third)
+
+	// Marking slots used never waits, and the capped type at its cap stops further reservations.
+	markUsed(second, "Fragile")
+	markUsed(third, "Fragile")
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = supplier.ReserveSlot(ctx, reserveInfo("tq"))
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	reserved := make(chan *SlotPermit)
+	go func() {
+		permit, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+		assert.NoError(t, err)
+		reserved <- permit
+	}()
+	select {
+	case <-reserved:
+		t.Fatal("slot reserved while an activity type was at its cap")
+	case <-time.After(50 * time.Millisecond):
+	}
+	supplier.ReleaseSlot(slotReleaseContextImpl{permit: second})
+	var fourth *SlotPermit
+	select {
+	case fourth = <-reserved:
+	case <-time.After(time.Second):
+		t.Fatal("slot not reserved after a task of the capped activity type finished")
+	}
+
+	// Slots released without a task free their room too.
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+	supplier.ReleaseSlot(slotReleaseContextImpl{permit: fourth})
+	require.NotNil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+
+	_, err = NewActivityTypeConcurrencySlotSupplier(ActivityTypeConcurrencySlotSupplierOptions{})
+	require.EqualError(t, err, "SlotSupplier is required")
+	_, err = NewActivityTypeConcurrencySlotSupplier(ActivityTypeConcurrencySlotSupplierOptions{
+		SlotSupplier:            inner,
+		MaxConcurrentExecutions: map[string]int{"Fragile": 0},
+	})
+	require.EqualError(t, err, `max concurrent executions for activity type "Fragile" must be positive`)
+}
+
+func TestActivityTypeConcurrencySlotSupplierSetLimits(t *testing.T) {
+	inner, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	supplier := newActivityTypeConcurrencySlotSupplier(inner, nil)
+	for range 2 {
+		permit := supplier.TryReserveSlot(reserveInfo("tq"))
+		require.NotNil(t, permit)
+		supplier.MarkSlotUsed(slotMarkUsedContextImpl{permit: permit, taskInfo: SlotTaskInfo{ActivityType: "Fragile"}})
+	}
+
+	// Caps apply to the tasks that were running before they were set.
+	supplier.setLimits(map[string]int{"Fragile": 2})
+	require.Nil(t, supplier.TryReserveSlot(reserveInfo("tq")))
+
+	reserved := make(chan *SlotPermit)
+	go func() {
+		permit, err := supplier.ReserveSlot(context.Background(), reserveInfo("tq"))
+		assert.NoError(t, err)
+		reserved <- permit
+	}()
+	supplier.setLimits(map[string]int{"Fragile": 3})
+	select {
+	case permit := <-reserved:
+		require.NotNil(t, permit)
+	case <-time.After(time.Second):
+		t.Fatal("slot not reserved after the cap was raised")
+	}
+}
+
+type recordingSlotReservationObserver struct {
+	events []SlotReservationEvent
+}
+
+func (o *recordingSlotReservationObserver) OnSlotReservation(event SlotReservationEvent) {
+	o.events = append(o.events, event)
+}
+
+func TestTrackingSlotSupplierReservationMetrics(t *testing.T) {
+	handler := metrics.NewCapturingHandler()
+	observer := &recordingSlotReservationObserver{}
+	inner, err := NewFixedSizeSlotSupplier(1)
+	require.NoError(t, err)
+	tuner, err := NewCompositeTuner(CompositeTunerOptions{SlotReservationObserver: observer})
+	require.NoError(t, err)
+	supplier := newTrackingSlotSupplier(inner, trackingSlotSupplierOptions{
+		logger:         ilog.NewNopLogger(),
+		metricsHandler: handler,
+		taskType:       WorkerTaskTypeActivity,
+		observer:       slotReservationObserverOf(tuner),
+	})
+	data := &slotReservationData{taskQueue: "tq"}
+
+	permit, err := supplier.ReserveSlot(context.Background(), data)
+	require.NoError(t, err)
+	require.Nil(t, supplier.TryReserveSlot(data))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = supplier.ReserveSlot(ctx, data)
+	require.ErrorIs(t, err, context.Canceled)
+	supplier.ReleaseSlot(permit, SlotReleaseReasonUnused)
+
+	require.Len(t, observer.events, 3)
+	require.Equal(t, SlotReservationEvent{
+		TaskType:         WorkerTaskTypeActivity,
+		SlotSupplierKind: "Fixed",
+		TaskQueue:        "tq",
+		Outcome:          SlotReservationOutcomeReserved,
+		WaitTime:         observer.events[0].WaitTime,
+		IssuedSlots:      1,
+		MaxSlots:         1,
+	}, observer.events[0])
+	require.True(t, observer.events[1].Eager)
+	require.Equal(t, SlotReservationOutcomeUnavailable, observer.events[1].Outcome)
+	require.Equal(t, SlotReservationOutcomeCanceled, observer.events[2].Outcome)
+	require.ErrorIs(t, observer.events[2].Err, context.Canceled)
+
+	denied := map[string]int64{}
+	for _, counter := range handler.Counters() {
+		if counter.Name == metrics.WorkerTaskSlotReservationDenied {
+			denied[counter.Tags[metrics.DenialReasonTagName]] += counter.Value()
+		}
+	}
+	// Canceled reservations are not denials.
+	require.Equal(t, map[string]int64{"unavailable": 1}, denied)
+	timers := map[string]int64{}
+	for _, timer := range handler.Timers() {
+		timers[timer.Name] += timer.Count()
+	}
+	require.Equal(t, int64(1), timers[metrics.WorkerTaskSlotReservationLatency])
+	require.Equal(t, int64(1), timers[metrics.WorkerTaskSlotsAtMaxDuration])
+}
+
+func TestTrackingSlotSupplierWaitReasons(t *testing.T) {
+	handler := metrics.NewCapturingHandler()
+	inner, err := NewFixedSizeSlotSupplier(10)
+	require.NoError(t, err)
+	rateLimited, err := NewRateLimitedSlotSupplier(RateLimitedSlotSupplierOptions{SlotSupplier: inner, TasksPerSecond: 10})
+	require.NoError(t, err)
+	supplier := newTrackingSlotSupplier(rateLimited, trackingSlotSupplierOptions{
+		logger:         ilog.NewNopLogger(),
+		metricsHandler: handler,
+		taskType:       WorkerTaskTypeActivity,
+	})
+	data := &slotReservationData{taskQueue: "tq"}
+
+	permit, err := supplier.ReserveSlot(context.Background(), data)
+	require.NoError(t, err)
+	supplier.MarkSlotUsed(permit, SlotTaskInfo{ActivityType: "Slow"})
+	require.Nil(t, supplier.TryReserveSlot(data))
+	_, err = supplier.ReserveSlot(context.Background(), data)
+	require.NoError(t, err)
+
+	denied := map[string]int64{}
+	for _, counter := range handler.Counters() {
+		if counter.Name == metrics.WorkerTaskSlotReservationDenied {
+			denied[counter.Tags[metrics.DenialReasonTagName]] += counter.Value()
+		}
+	}
+	require.Equal(t, map[string]int64{"rate_limit": 1}, denied)
+	waits := map[string]int64{}
+	for _, timer := range handler.Timers() {
+		if timer.Name == metrics.WorkerTaskSlotReservationLatency && timer.Count() > 0 {
+			waits[timer.Tags[metrics.WaitReasonTagName]] += timer.Count()
+		}
+	}
+	require.Equal(t, map[string]int64{"slots": 1, "rate_limit": 1}, waits)
+}
@@ -26,6 +26,10 @@ type (
 		maximumNumberOfPollers int
 		// minimumNumberOfPollers is the minimum number of pollers the worker is allowed scale down to.
 		minimumNumberOfPollers int
+		// backlogLatencyTarget enables scaling on the task queue backlog when positive.
+		backlogLatencyTarget time.Duration
+		// backlogCheckInterval is how often the task queue backlog is checked.
+		backlogCheckInterval time.Duration
 	}
 
 	// PollerBehavior is used to configure the behavior of the poller.
@@ -53,6 +57,29 @@ type (
 		//
 		// Default: 100
 		MaximumNumberOfPollers int
+
+		// BacklogLatencyTarget enables scaling on the backlog of the task queue in addition to the
+		// hints the server sends in poll responses. The worker periodically reads the backlog stats
+		// of its task queue with Client.DescribeTaskQueueEnhanced, for the Build ID of the worker if it
+		// is versioned or the unversioned queue otherwise, and while the approximate age of the oldest
+		// backlogged task exceeds this target it scales up pollers in proportion to how far behind it
+		// is. A ResourceBasedSlotSupplier used for the same tasks scales its slot target in the same
+		// proportion, issuing slots without ramping up while resources are below their targets, see
+		// worker.ResourceBasedSlotSupplier.
+		//
+		// Requires a server that supports DescribeTaskQueueEnhanced. Not supported with
+		// DeploymentOptions.UseVersioning, since the backlog of a deployment version cannot be read
+		// this way. Zero disables backlog scaling.
+		//
+		// NOTE: Experimental
+		BacklogLatencyTarget time.Duration
+
+		// BacklogCheckInterval is how often the backlog is checked when BacklogLatencyTarget is set.
+		//
+		// Default: 10s
+		//
+		// NOTE: Experimental
+		BacklogCheckInterval time.Duration
 	}
 
 	// PollerBehaviorSimpleMaximumOptions is the options for NewPollerBehaviorSimpleMaximum.
@@ -116,6 +143,33 @@ type (
 		// default: 100k
 		WorkerActivitiesPerSecond float64
 
+		// Optional: Limits the number of tasks of individual activity types that this worker runs at once,
+		// keyed by activity type. Limits can also be set when registering an activity with
+		// RegisterActivityOptions.MaxConcurrentExecutions, and the ones set here take precedence. Requires
+		// EnableActivityTypeConcurrencyLimits. The limits can be changed with Worker.Reconfigure.
+		//
+		// NOTE: Experimental
+		MaxConcurrentActivityTypeExecutionSize map[string]int
+
+		// Optional: Applies MaxConcurrentActivityTypeExecutionSize and the
+		// RegisterActivityOptions.MaxConcurrentExecutions of registered activities. Without it, the
+		// limits set at registration are ignored and a warning is logged when the worker starts.
+		//
+		// Activity tasks are dispatched to workers per task queue, not per activity type, so a worker only
+		// learns the type of a task after it has polled it. So that no polled task has to wait, the worker
+		// stops polling while the next task could exceed a limit, counting every poll in flight as a
+		// possible task of each limited type, and the rest of the backlog is dispatched to other workers.
+		// This reduces the throughput of every activity type of the task queue: while any limited type is
+		// at its limit, no task of any type is polled, and there are never more polls in flight than the
+		// smallest limit. Only enable this on workers of a task queue dedicated to the limited activity
+		// types. See also worker.ActivityTypeConcurrencySlotSupplier, which applies the same limits within
+		// a custom Tuner.
+		//
+		// default: false
+		//
+		// NOTE: Experimental
+		EnableActivityTypeConcurrencyLimits bool
+
 		// Optional: To set the maximum concurrent local activity executions this worker can have.
 		// The zero value of this uses the default value.
 		//
@@ -431,6 +485,65 @@ type (
 		// NOTE: Experimental
 		DisablePayloadErrorLimit bool
 	}
+
+	// WorkerReconfigureOptions are the settings of a running worker changed by Reconfigure. Zero
+	// values leave the corresponding setting unchanged.
+	//
+	// Exposed as: [go.temporal.io/sdk/worker.ReconfigureOptions]
+	//
+	// NOTE: Experimental
+	WorkerReconfigureOptions struct {
+		// Optional: The number of workflow task slots. Only supported when the worker's tuner uses a
+		// [FixedSizeSlotSupplier] for workflow tasks, which is the case when the tuner is created
+		// from the MaxConcurrent*ExecutionSize options. Reducing the number of slots does not
+		// interrupt tasks already running, new tasks are not started until the number of running
+		// tasks falls below the new value.
+		//
+		// NOTE: Slot suppliers shared with other workers are changed for all of them.
+		MaxConcurrentWorkflowTaskExecutionSize int
+
+		// Optional: The number of activity task slots, see MaxConcurrentWorkflowTaskExecutionSize.
+		MaxConcurrentActivityExecutionSize int
+
+		// Optional: The number of local activity slots, see MaxConcurrentWorkflowTaskExecutionSize.
+		MaxConcurrentLocalActivityExecutionSize int
+
+		// Optional: The number of nexus task slots, see MaxConcurrentWorkflowTaskExecutionSize.
+		MaxConcurrentNexusTaskExecutionSize int
+
+		// Optional: The poller behavior for workflow tasks. It must be of the same kind, simple
+		// maximum or autoscaling, as the one the worker was created with. The initial number of
+		// pollers and the backlog settings of an autoscaling behavior are ignored. Pollers above a
+		// reduced maximum stop once their current poll completes.
+		WorkflowTaskPollerBehavior PollerBehavior
+
+		// Optional: The poller behavior for activity tasks, see WorkflowTaskPollerBehavior.
+		ActivityTaskPollerBehavior PollerBehavior
+
+		// Optional: The poller behavior for nexus tasks, see WorkflowTaskPollerBehavior.
+		NexusTaskPollerBehavior PollerBehavior
+
+		// Optional: The rate limit on activities executed per second by the worker, see
+		// [WorkerOptions.WorkerActivitiesPerSecond].
+		WorkerActivitiesPerSecond float64
+
+		// Optional: The rate limit on local activities executed per second by the worker, see
+		// [WorkerOptions.WorkerLocalActivitiesPerSecond].
+		WorkerLocalActivitiesPerSecond float64
+
+		// Optional: The rate limit on activities of the task queue sent to the server with each
+		// poll, see [WorkerOptions.TaskQueueActivitiesPerSecond]. Eager activities remain enabled or
+		// disabled as they were when the worker was created.
+		TaskQueueActivitiesPerSecond float64
+
+		// Optional: Replaces the limits of [WorkerOptions.MaxConcurrentActivityTypeExecutionSize]. Limits
+		// set at registration still apply to activity types without an entry. Only supported when
+		// [WorkerOptions.EnableActivityTypeConcurrencyLimits] is set. Lowering a limit does not
+		// interrupt tasks already running. Nil leaves the limits unchanged.
+		//
+		// NOTE: Experimental
+		MaxConcurrentActivityTypeExecutionSize map[string]int
+	}
 )
 
 // WorkflowPanicPolicy is used for configuring how worker deals with workflow
@@ -544,9 +657,18 @@ func NewPollerBehaviorAutoscaling(
 	if maximumNumberOfPollers <= 0 {
 		maximumNumberOfPollers = defaultAutoscalingMaximumNumberOfPollers // Default maximum number of pollers.
 	}
+	var backlogCheckInterval time.Duration
+	if options.BacklogLatencyTarget > 0 {
+		backlogCheckInterval = options.BacklogCheckInterval
+		if backlogCheckInterval <= 0 {
+			backlogCheckInterval = defaultBacklogCheckInterval
+		}
+	}
 	return &pollerBehaviorAutoscaling{
 		initialNumberOfPollers: initialNumberOfPollers,
 		minimumNumberOfPollers: minimumNumberOfPollers,
 		maximumNumberOfPollers: maximumNumberOfPollers,
+		backlogLatencyTarget:   max(options.BacklogLatencyTarget, 0),
+		backlogCheckInterval:   backlogCheckInterval,
 	}
 }
@@ -327,6 +327,41 @@ type (
 		IsLocked() bool
 	}
 
+	// RWMutex must be used instead of native go sync.RWMutex by
+	// workflow code. Use workflow.NewRWMutex(ctx) method to create
+	// a new RWMutex instance
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.RWMutex]
+	//
+	// NOTE: Experimental
+	RWMutex interface {
+		// Lock blocks until the mutex is acquired for writing, when it is not
+		// held by a writer or any reader. Readers waiting for the mutex are not
+		// given it while a writer waits for it.
+		// Returns CanceledError if the ctx is canceled.
+		Lock(ctx Context) error
+		// TryLock tries to acquire the mutex for writing without blocking.
+		// Returns true if the mutex was acquired, otherwise false.
+		TryLock(ctx Context) bool
+		// Unlock releases the mutex held for writing.
+		// It is a run-time error if the mutex is not locked for writing on entry to Unlock.
+		Unlock()
+		// RLock blocks until the mutex is acquired for reading, when it is not
+		// held or waited for by a writer.
+		// Returns CanceledError if the ctx is canceled.
+		RLock(ctx Context) error
+		// TryRLock tries to acquire the mutex for reading without blocking.
+		// Returns true if the mutex was acquired, otherwise false.
+		TryRLock(ctx Context) bool
+		// RUnlock releases the mutex held for reading.
+		// It is a run-time error if the mutex is not locked for reading on entry to RUnlock.
+		RUnlock()
+		// IsLocked returns true if the mutex is currently locked for writing.
+		IsLocked() bool
+		// Readers returns the number of readers currently holding the mutex.
+		Readers() int
+	}
+
 	// Semaphore must be used instead of semaphore.Weighted by
 	// workflow code. Use workflow.NewSemaphore(ctx) method to create
 	// a new Semaphore instance
@@ -870,6 +905,16 @@ func NewMutex(ctx Context) Mutex {
 	return &mutexImpl{}
 }
 
+// NewRWMutex creates a new RWMutex instance.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewRWMutex]
+//
+// NOTE: Experimental
+func NewRWMutex(ctx Context) RWMutex {
+	assertNotInReadOnlyState(ctx)
+	return &rwMutexImpl{}
+}
+
 // NewSemaphore creates a new Semaphore instance with an initial weight.
 //
 // Exposed as: [go.temporal.io/sdk/workflow.NewSemaphore]
@@ -0,0 +1,103 @@
+package internal
+
+type (
+	// ErrGroup runs a group of coroutines working on subtasks of a common task, and collects the
+	// first error returned by one of them. It must be used instead of golang.org/x/sync/errgroup by
+	// workflow code. Use workflow.NewErrGroup(ctx, options) to create a new ErrGroup instance.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.ErrGroup]
+	//
+	// NOTE: Experimental
+	ErrGroup interface {
+		// Go calls f in a new coroutine with the Context of the group. It does not block: when the
+		// group already runs its limit of coroutines, f is queued and started once one of them
+		// returns, in the order Go was called.
+		//
+		// The first f to return a non-nil error cancels the Context of the group, and its error
+		// is returned by Wait. Queued functions that have not been started by then are not run.
+		Go(f func(ctx Context) error)
+
+		// Wait blocks until all the functions started or queued by Go have returned, and then
+		// returns the first non-nil error from them, if any. The Context of the group is canceled
+		// when Wait returns. Returns CanceledError if ctx is canceled while waiting.
+		Wait(ctx Context) error
+	}
+
+	// ErrGroupOptions are options for NewErrGroup.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.ErrGroupOptions]
+	//
+	// NOTE: Experimental
+	ErrGroupOptions struct {
+		// Limit is the maximum number of coroutines of the group running at a time. Zero means no
+		// limit.
+		Limit int
+	}
+
+	errGroupImpl struct {
+		ctx     Context
+		cancel  CancelFunc
+		limit   int
+		running int
+		// pending are the functions queued by Go while the group is at its limit
+		pending []func(ctx Context) error
+		err     error
+	}
+)
+
+// NewErrGroup creates a new ErrGroup and a Context derived from ctx that is canceled when a
+// function of the group returns an error, or when Wait returns.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewErrGroup]
+//
+// NOTE: Experimental
+func NewErrGroup(ctx Context, options ErrGroupOptions) (ErrGroup, Context) {
+	assertNotInReadOnlyState(ctx)
+	if options.Limit < 0 {
+		panic("workflow: ErrGroupOptions.Limit must not be negative")
+	}
+	groupCtx, cancel := WithCancel(ctx)
+	return &errGroupImpl{ctx: groupCtx, cancel: cancel, limit: options.Limit}, groupCtx
+}
+
+func (g *errGroupImpl) Go(f func(ctx Context) error) {
+	assertNotInReadOnlyState(g.ctx)
+	if g.err != nil {
+		return
+	}
+	if g.limit > 0 && g.running >= g.limit {
+		g.pending = append(g.pending, f)
+		return
+	}
+	g.start(f)
+}
+
+func (g *errGroupImpl) start(f func(ctx Context) error) {
+	g.running++
+	Go(g.ctx, func(ctx Context) {
+		if err := f(ctx); err != nil && g.err == nil {
+			g.err = err
+			g.pending = nil
+			g.cancel()
+		}
+		g.done()
+	})
+}
+
+// done starts the next queued function, if any, when a function of the group returns.
+func (g *errGroupImpl) done() {
+	g.running--
+	if len(g.pending) > 0 {
+		f := g.pending[0]
+		g.pending = g.pending[1:]
+		g.start(f)
+	}
+}
+
+func (g *errGroupImpl) Wait(ctx Context) error {
+	if err := Await(ctx, func() bool { return g.running == 0 && len(g.pending) == 0 }); err != nil {
+		return err
+	}
+	g.cancel()
+	return g.err
+}
@@ -0,0 +1,117 @@
+package internal
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestErrGroupLimit(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) ([]int, error) {
+		group, _ := NewErrGroup(ctx, ErrGroupOptions{Limit: 2})
+		var started []int
+		running, maxRunning := 0, 0
+		for i := 0; i < 5; i++ {
+			group.Go(func(ctx Context) error {
+				started = append(started, i)
+				running++
+				maxRunning = max(maxRunning, running)
+				defer func() { running-- }()
+				return Sleep(ctx, time.Duration(5-i)*time.Minute)
+			})
+		}
+		if err := group.Wait(ctx); err != nil {
+			return nil, err
+		}
+		if maxRunning != 2 {
+			return nil, errors.New("limit exceeded")
+		}
+		return started, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var started []int
+	require.NoError(t, env.GetWorkflowResult(&started))
+	require.Equal(t, []int{0, 1, 2, 3, 4}, started)
+}
+
+func TestErrGroupFirstError(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) (string, error) {
+		group, groupCtx := NewErrGroup(ctx, ErrGroupOptions{Limit: 2})
+		var siblingErr error
+		queuedRan := false
+		group.Go(func(ctx Context) error {
+			_ = Sleep(ctx, time.Second)
+			return errors.New("first")
+		})
+		group.Go(func(ctx Context) error {
+			siblingErr = Sleep(ctx, time.Hour)
+			return errors.New("second")
+		})
+		group.Go(func(ctx Context) error {
+			queuedRan = true
+			return nil
+		})
+		err := group.Wait(ctx)
+		switch {
+		case err == nil || err.Error() != "first":
+			return "", errors.New("expected first error")
+		case !errors.As(siblingErr, new(*CanceledError)):
+			return "", errors.New("expected sibling to be canceled")
+		case queuedRan:
+			return "", errors.New("expected queued function to not run")
+		case groupCtx.Err() == nil:
+			return "", errors.New("expected group context to be canceled")
+		case ctx.Err() != nil:
+			return "", errors.New("expected workflow context to not be canceled")
+		}
+		return "ok", nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
+
+func TestErrGroupNoLimit(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) (int, error) {
+		start := Now(ctx)
+		group, groupCtx := NewErrGroup(ctx, ErrGroupOptions{})
+		results := make([]int, 10)
+		for i := range results {
+			group.Go(func(ctx Context) error {
+				if err := Sleep(ctx, time.Minute); err != nil {
+					return err
+				}
+				results[i] = i
+				return nil
+			})
+		}
+		if err := group.Wait(ctx); err != nil {
+			return 0, err
+		}
+		if groupCtx.Err() == nil {
+			return 0, errors.New("expected group context to be canceled after Wait")
+		}
+		// All the functions ran at the same time
+		if Now(ctx).Sub(start) != time.Minute {
+			return 0, errors.New("expected functions to run concurrently")
+		}
+		sum := 0
+		for _, r := range results {
+			sum += r
+		}
+		return sum, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var sum int
+	require.NoError(t, env.GetWorkflowResult(&sum))
+	require.Equal(t, 45, sum)
+}
@@ -0,0 +1,122 @@
+package internal
+
+import (
+	"errors"
+	"time"
+)
+
+type (
+	// RateLimiter paces work of a workflow, such as starting activities or child workflows, with a
+	// token bucket. The bucket holds up to Burst tokens and gains one every Interval of workflow
+	// time. Use workflow.NewRateLimiter(ctx, options) to create a new RateLimiter instance.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.RateLimiter]
+	//
+	// NOTE: Experimental
+	RateLimiter interface {
+		// Wait blocks until a token is available and takes it. Callers get tokens in the order
+		// they call Wait, and wait on a workflow timer, so pacing is deterministic on replay.
+		// Returns CanceledError if the ctx is canceled, in which case no token is taken.
+		Wait(ctx Context) error
+
+		// Allow takes a token if one is available now, without blocking, and returns whether it
+		// did.
+		Allow(ctx Context) bool
+	}
+
+	// RateLimiterOptions are options for NewRateLimiter.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.RateLimiterOptions]
+	//
+	// NOTE: Experimental
+	RateLimiterOptions struct {
+		// Interval is the workflow time it takes to gain a token. Required.
+		Interval time.Duration
+
+		// Burst is the maximum number of tokens, which the bucket starts with. Defaults to 1.
+		Burst int
+	}
+
+	rateLimiterImpl struct {
+		interval time.Duration
+		burst    int
+		// tokens is the number of tokens at last. It is negative when waiters have reserved tokens
+		// that have yet to be gained.
+		tokens int
+		last   time.Time
+	}
+)
+
+// NewRateLimiter creates a new RateLimiter instance.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewRateLimiter]
+//
+// NOTE: Experimental
+func NewRateLimiter(ctx Context, options RateLimiterOptions) (RateLimiter, error) {
+	assertNotInReadOnlyState(ctx)
+	if options.Interval <= 0 {
+		return nil, errors.New("rate limiter Interval must be positive")
+	}
+	if options.Burst < 0 {
+		return nil, errors.New("rate limiter Burst must not be negative")
+	}
+	if options.Burst == 0 {
+		options.Burst = 1
+	}
+	return &rateLimiterImpl{
+		interval: options.Interval,
+		burst:    options.Burst,
+		tokens:   options.Burst,
+		last:     Now(ctx),
+	}, nil
+}
+
+// refill adds the tokens gained since last.
+func (r *rateLimiterImpl) refill(now time.Time) {
+	if r.tokens >= r.burst {
+		r.last = now
+		return
+	}
+	gained := int(now.Sub(r.last) / r.interval)
+	if gained <= 0 {
+		return
+	}
+	if r.tokens+gained >= r.burst {
+		r.tokens = r.burst
+		r.last = now
+		return
+	}
+	r.tokens += gained
+	r.last = r.last.Add(time.Duration(gained) * r.interval)
+}
+
+func (r *rateLimiterImpl) Wait(ctx Context) error {
+	assertNotInReadOnlyState(ctx)
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	now := Now(ctx)
+	r.refill(now)
+	r.tokens--
+	if r.tokens >= 0 {
+		return nil
+	}
+	// Reserve the token that will be gained after those reserved by the other waiters
+	delay := r.last.Add(time.Duration(-r.tokens) * r.interval).Sub(now)
+	if err := Sleep(ctx, delay); err != nil {
+		// Give back the reserved token
+		r.tokens++
+		return err
+	}
+	return nil
+}
+
+func (r *rateLimiterImpl) Allow(ctx Context) bool {
+	assertNotInReadOnlyState(ctx)
+	r.refill(Now(ctx))
+	if r.tokens <= 0 {
+		return false
+	}
+	r.tokens--
+	return true
+}
@@ -0,0 +1,98 @@
+package internal
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateLimiterWait(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) ([]time.Duration, error) {
+		start := Now(ctx)
+		limiter, err := NewRateLimiter(ctx, RateLimiterOptions{Interval: time.Second, Burst: 2})
+		if err != nil {
+			return nil, err
+		}
+		acquired := make([]time.Duration, 6)
+		wg := NewWaitGroup(ctx)
+		for i := 0; i < 5; i++ {
+			wg.Go(ctx, func(ctx Context) {
+				if err := limiter.Wait(ctx); err != nil {
+					panic(err)
+				}
+				acquired[i] = Now(ctx).Sub(start)
+			})
+		}
+		wg.Wait(ctx)
+		// The bucket refills up to Burst tokens while unused
+		_ = Sleep(ctx, time.Minute)
+		if !limiter.Allow(ctx) || !limiter.Allow(ctx) || limiter.Allow(ctx) {
+			panic("expected a full bucket")
+		}
+		if err := limiter.Wait(ctx); err != nil {
+			return nil, err
+		}
+		acquired[5] = Now(ctx).Sub(start)
+		return acquired, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var acquired []time.Duration
+	require.NoError(t, env.GetWorkflowResult(&acquired))
+	require.Equal(t, []time.Duration{0, 0, time.Second, 2 * time.Second, 3 * time.Second, 64 * time.Second}, acquired)
+}
+
+func TestRateLimiterWaitCanceled(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) (time.Duration, error) {
+		start := Now(ctx)
+		limiter, err := NewRateLimiter(ctx, RateLimiterOptions{Interval: time.Minute})
+		if err != nil {
+			return 0, err
+		}
+		if !limiter.Allow(ctx) {
+			panic("expected a token")
+		}
+		canceledCtx, cancel := WithCancel(ctx)
+		Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, time.Second)
+			cancel()
+		})
+		if err := limiter.Wait(canceledCtx); err == nil {
+			panic("expected canceled wait")
+		}
+		if err := limiter.Wait(canceledCtx); err == nil {
+			panic("expected canceled wait")
+		}
+		// The token reserved by the canceled wait is given back
+		if err := limiter.Wait(ctx); err != nil {
+			return 0, err
+		}
+		return Now(ctx).Sub(start), nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var acquired time.Duration
+	require.NoError(t, env.GetWorkflowResult(&acquired))
+	require.Equal(t, time.Minute, acquired)
+}
+
+func TestRateLimiterOptions(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) error {
+		if _, err := NewRateLimiter(ctx, RateLimiterOptions{}); err == nil {
+			panic("expected error for missing interval")
+		}
+		if _, err := NewRateLimiter(ctx, RateLimiterOptions{Interval: time.Second, Burst: -1}); err == nil {
+			panic("expected error for negative burst")
+		}
+		return nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+}
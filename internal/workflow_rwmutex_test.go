@@ -0,0 +1,73 @@
+package internal
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestRWMutex(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) ([]string, error) {
+		start := Now(ctx)
+		mutex := NewRWMutex(ctx)
+		var events []string
+		record := func(ctx Context, event string) {
+			events = append(events, fmt.Sprintf("%v %s", Now(ctx).Sub(start), event))
+		}
+		hold := func(ctx Context, name string, lock func(Context) error, unlock func()) {
+			if err := lock(ctx); err != nil {
+				panic(err)
+			}
+			record(ctx, name+" locked")
+			_ = Sleep(ctx, time.Minute)
+			record(ctx, name+" unlocking")
+			unlock()
+		}
+		wg := NewWaitGroup(ctx)
+		wg.Go(ctx, func(ctx Context) { hold(ctx, "reader1", mutex.RLock, mutex.RUnlock) })
+		wg.Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, 10*time.Second)
+			hold(ctx, "reader2", mutex.RLock, mutex.RUnlock)
+		})
+		wg.Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, 20*time.Second)
+			hold(ctx, "writer", mutex.Lock, mutex.Unlock)
+		})
+		wg.Go(ctx, func(ctx Context) {
+			_ = Sleep(ctx, 30*time.Second)
+			if mutex.TryRLock(ctx) {
+				panic("reader locked while a writer is waiting")
+			}
+			hold(ctx, "reader3", mutex.RLock, mutex.RUnlock)
+		})
+		wg.Wait(ctx)
+		if mutex.IsLocked() || mutex.Readers() != 0 || !mutex.TryLock(ctx) || mutex.TryRLock(ctx) {
+			panic("unexpected mutex state")
+		}
+		return events, nil
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var events []string
+	require.NoError(t, env.GetWorkflowResult(&events))
+	require.Equal(t, []string{
+		"0s reader1 locked",
+		"10s reader2 locked",
+		"1m0s reader1 unlocking",
+		"1m10s reader2 unlocking",
+		"1m10s writer locked",
+		"2m10s writer unlocking",
+		"2m10s reader3 locked",
+		"3m10s reader3 unlocking",
+	}, events)
+}
+
+func TestRWMutexUnlockPanics(t *testing.T) {
+	m := &rwMutexImpl{}
+	require.Panics(t, m.Unlock)
+	require.Panics(t, m.RUnlock)
+}
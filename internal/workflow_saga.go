@@ -0,0 +1,165 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type (
+	// Saga registers compensations for the steps of a workflow, and runs them to undo the steps
+	// when the workflow fails or is canceled. Use workflow.NewSaga(ctx, options) to create a new
+	// Saga instance.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.Saga]
+	//
+	// NOTE: Experimental
+	Saga interface {
+		// AddCompensation registers f to undo a step that completed. Register it after the step
+		// succeeded, or before starting the step when f can undo a step that may have partially
+		// completed.
+		AddCompensation(f func(ctx Context) error)
+
+		// Compensate runs the registered compensations in the reverse order of their registration,
+		// or all at once when SagaOptions.ParallelCompensation is set, and then forgets them. They
+		// run on a Context disconnected from ctx, so that they run even when ctx is canceled.
+		//
+		// Returns a *SagaCompensationError with the errors of the compensations that failed, if
+		// any.
+		Compensate(ctx Context) error
+
+		// CompensateOnError runs Compensate when *errPtr is not nil or ctx is canceled, to be
+		// deferred with a named error result of the workflow:
+		//
+		//	func MyWorkflow(ctx workflow.Context) (err error) {
+		//		saga := workflow.NewSaga(ctx, workflow.SagaOptions{})
+		//		defer saga.CompensateOnError(ctx, &err)
+		//		...
+		//	}
+		//
+		// Compensation is skipped when *errPtr is a *ContinueAsNewError. When compensation fails,
+		// *errPtr is set to an error joining the original error with the *SagaCompensationError.
+		// Canceled workflows don't report their error, so the compensation failure is also logged
+		// when the workflow is canceled.
+		CompensateOnError(ctx Context, errPtr *error)
+	}
+
+	// SagaOptions are options for NewSaga.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SagaOptions]
+	//
+	// NOTE: Experimental
+	SagaOptions struct {
+		// ParallelCompensation runs the compensations concurrently rather than one after the other
+		// in the reverse order of their registration.
+		ParallelCompensation bool
+
+		// ContinueWithError continues running the remaining compensations when one fails. By
+		// default, compensation stops at the first one that fails. Concurrent compensations are
+		// always all run.
+		ContinueWithError bool
+	}
+
+	// SagaCompensationError is returned by Saga.Compensate when compensations failed. Errors are
+	// ordered in the reverse order of the registration of the compensations that returned them.
+	//
+	// Exposed as: [go.temporal.io/sdk/workflow.SagaCompensationError]
+	//
+	// NOTE: Experimental
+	SagaCompensationError struct {
+		Errors []error
+	}
+
+	sagaImpl struct {
+		options       SagaOptions
+		compensations []func(ctx Context) error
+	}
+)
+
+// NewSaga creates a new Saga instance.
+//
+// Exposed as: [go.temporal.io/sdk/workflow.NewSaga]
+//
+// NOTE: Experimental
+func NewSaga(ctx Context, options SagaOptions) Saga {
+	assertNotInReadOnlyState(ctx)
+	return &sagaImpl{options: options}
+}
+
+func (s *sagaImpl) AddCompensation(f func(ctx Context) error) {
+	s.compensations = append(s.compensations, f)
+}
+
+func (s *sagaImpl) Compensate(ctx Context) error {
+	assertNotInReadOnlyState(ctx)
+	compensations := s.compensations
+	s.compensations = nil
+	if len(compensations) == 0 {
+		return nil
+	}
+	disconnectedCtx, cancel := NewDisconnectedContext(ctx)
+	defer cancel()
+
+	// Errors of the compensations, in reverse order of registration
+	errs := make([]error, len(compensations))
+	if s.options.ParallelCompensation {
+		wg := NewWaitGroup(disconnectedCtx)
+		for i := len(compensations) - 1; i >= 0; i-- {
+			wg.Go(disconnectedCtx, func(ctx Context) {
+				errs[len(compensations)-1-i] = compensations[i](ctx)
+			})
+		}
+		wg.Wait(disconnectedCtx)
+	} else {
+		for i := len(compensations) - 1; i >= 0; i-- {
+			err := compensations[i](disconnectedCtx)
+			errs[len(compensations)-1-i] = err
+			if err != nil && !s.options.ContinueWithError {
+				break
+			}
+		}
+	}
+
+	var failures []error
+	for _, err := range errs {
+		if err != nil {
+			failures = append(failures, err)
+		}
+	}
+	if len(failures) == 0 {
+		return nil
+	}
+	return &SagaCompensationError{Errors: failures}
+}
+
+func (s *sagaImpl) CompensateOnError(ctx Context, errPtr *error) {
+	var continueAsNewErr *ContinueAsNewError
+	if (*errPtr == nil && ctx.Err() == nil) || errors.As(*errPtr, &continueAsNewErr) {
+		return
+	}
+	err := s.Compensate(ctx)
+	if err == nil {
+		return
+	}
+	var canceledErr *CanceledError
+	if errors.As(*errPtr, &canceledErr) {
+		GetLogger(ctx).Error("Saga compensation failed on workflow cancellation.", tagError, err)
+	}
+	*errPtr = errors.Join(*errPtr, err)
+}
+
+func (e *SagaCompensationError) Error() string {
+	if len(e.Errors) == 1 {
+		return "saga compensation failed: " + e.Errors[0].Error()
+	}
+	messages := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		messages[i] = err.Error()
+	}
+	return fmt.Sprintf("saga compensation failed with %d errors: %s", len(e.Errors), strings.Join(messages, "; "))
+}
+
+// Unwrap returns the errors of the compensations that failed.
+func (e *SagaCompensationError) Unwrap() []error {
+	return e.Errors
+}
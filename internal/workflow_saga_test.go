@@ -0,0 +1,138 @@
+package internal
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	ilog "go.temporal.io/sdk/internal/log"
+)
+
+func runSagaWorkflow(t *testing.T, options SagaOptions, failing map[int]bool) ([]int, error) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	var compensated []int
+	var compensateErr error
+	env.ExecuteWorkflow(func(ctx Context) error {
+		saga := NewSaga(ctx, options)
+		for i := 0; i < 4; i++ {
+			saga.AddCompensation(func(ctx Context) error {
+				// Later compensations take less time so that parallel ones complete in order
+				if err := Sleep(ctx, time.Duration(i+1)*time.Second); err != nil {
+					return err
+				}
+				compensated = append(compensated, i)
+				if failing[i] {
+					return fmt.Errorf("compensation %d failed", i)
+				}
+				return nil
+			})
+		}
+		compensateErr = saga.Compensate(ctx)
+		// Compensations run once
+		return saga.Compensate(ctx)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	return compensated, compensateErr
+}
+
+func TestSagaCompensate(t *testing.T) {
+	compensated, err := runSagaWorkflow(t, SagaOptions{}, nil)
+	require.NoError(t, err)
+	require.Equal(t, []int{3, 2, 1, 0}, compensated)
+}
+
+func TestSagaCompensateStopsOnError(t *testing.T) {
+	compensated, err := runSagaWorkflow(t, SagaOptions{}, map[int]bool{2: true, 1: true})
+	require.Equal(t, []int{3, 2}, compensated)
+	var sagaErr *SagaCompensationError
+	require.ErrorAs(t, err, &sagaErr)
+	require.Len(t, sagaErr.Errors, 1)
+	require.EqualError(t, err, "saga compensation failed: compensation 2 failed")
+}
+
+func TestSagaCompensateContinueWithError(t *testing.T) {
+	compensated, err := runSagaWorkflow(t, SagaOptions{ContinueWithError: true}, map[int]bool{2: true, 1: true})
+	require.Equal(t, []int{3, 2, 1, 0}, compensated)
+	require.EqualError(t, err, "saga compensation failed with 2 errors: compensation 2 failed; compensation 1 failed")
+}
+
+func TestSagaCompensateParallel(t *testing.T) {
+	compensated, err := runSagaWorkflow(t, SagaOptions{ParallelCompensation: true}, map[int]bool{0: true, 3: true})
+	require.Equal(t, []int{0, 1, 2, 3}, compensated)
+	var sagaErr *SagaCompensationError
+	require.ErrorAs(t, err, &sagaErr)
+	require.Len(t, sagaErr.Errors, 2)
+	require.EqualError(t, sagaErr.Errors[0], "compensation 3 failed")
+	require.EqualError(t, sagaErr.Errors[1], "compensation 0 failed")
+}
+
+func TestSagaCompensateOnCancel(t *testing.T) {
+	var s WorkflowTestSuite
+	logger := ilog.NewMemoryLogger()
+	s.SetLogger(logger)
+	env := s.NewTestWorkflowEnvironment()
+	compensated := false
+	env.RegisterDelayedCallback(env.CancelWorkflow, time.Minute)
+	env.ExecuteWorkflow(func(ctx Context) (err error) {
+		saga := NewSaga(ctx, SagaOptions{})
+		defer saga.CompensateOnError(ctx, &err)
+		saga.AddCompensation(func(ctx Context) error {
+			if err := Sleep(ctx, time.Second); err != nil {
+				return err
+			}
+			compensated = true
+			return errors.New("compensation failed")
+		})
+		return Sleep(ctx, time.Hour)
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.True(t, compensated)
+	err := env.GetWorkflowError()
+	require.Error(t, err)
+	var canceledErr *CanceledError
+	require.ErrorAs(t, err, &canceledErr)
+	// The compensation error is dropped with the CanceledError, so it is logged
+	require.True(t, slices.ContainsFunc(logger.Lines(), func(line string) bool {
+		return strings.Contains(line, "compensation failed")
+	}))
+}
+
+func TestSagaCompensateOnContinueAsNew(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	compensated := false
+	env.ExecuteWorkflow(func(ctx Context) (err error) {
+		saga := NewSaga(ctx, SagaOptions{})
+		defer saga.CompensateOnError(ctx, &err)
+		saga.AddCompensation(func(ctx Context) error {
+			compensated = true
+			return nil
+		})
+		return NewContinueAsNewError(ctx, "SagaWorkflow")
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	require.False(t, compensated)
+	var continueAsNewErr *ContinueAsNewError
+	require.ErrorAs(t, env.GetWorkflowError(), &continueAsNewErr)
+}
+
+func TestSagaCompensateOnError(t *testing.T) {
+	var s WorkflowTestSuite
+	env := s.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx Context) (err error) {
+		saga := NewSaga(ctx, SagaOptions{})
+		defer saga.CompensateOnError(ctx, &err)
+		saga.AddCompensation(func(ctx Context) error { return errors.New("compensation failed") })
+		return errors.New("step failed")
+	})
+	require.True(t, env.IsWorkflowCompleted())
+	err := env.GetWorkflowError()
+	require.ErrorContains(t, err, "step failed")
+	require.ErrorContains(t, err, "compensation failed")
+}
@@ -0,0 +1,76 @@
+package temporal
+
+type (
+	// SignalDefinition is the name and payload type of a workflow signal. Share it between the
+	// workflow and its callers, e.g. in an API package, so that both sides agree on them. Create with
+	// [NewSignalDefinition].
+	//
+	// See [go.temporal.io/sdk/workflow.GetTypedSignalChannel] and
+	// [go.temporal.io/sdk/client.SignalTypedWorkflow].
+	//
+	// NOTE: Experimental
+	SignalDefinition[T any] struct {
+		name string
+	}
+
+	// QueryDefinition is the name, argument type and result type of a workflow query. Create with
+	// [NewQueryDefinition].
+	//
+	// See [go.temporal.io/sdk/workflow.SetTypedQueryHandler] and
+	// [go.temporal.io/sdk/client.QueryTypedWorkflow].
+	//
+	// NOTE: Experimental
+	QueryDefinition[I, O any] struct {
+		name string
+	}
+
+	// UpdateDefinition is the name, argument type and result type of a workflow update. Create with
+	// [NewUpdateDefinition].
+	//
+	// See [go.temporal.io/sdk/workflow.SetTypedUpdateHandler] and
+	// [go.temporal.io/sdk/client.UpdateTypedWorkflow].
+	//
+	// NOTE: Experimental
+	UpdateDefinition[I, O any] struct {
+		name string
+	}
+)
+
+// NewSignalDefinition creates a definition of the signal with the given name and payload type.
+//
+// NOTE: Experimental
+func NewSignalDefinition[T any](name string) SignalDefinition[T] {
+	return SignalDefinition[T]{name: name}
+}
+
+// Name returns the name of the signal.
+func (d SignalDefinition[T]) Name() string {
+	return d.name
+}
+
+// NewQueryDefinition creates a definition of the query with the given name, argument type and result
+// type. Use struct{} as the argument type of queries that take no argument.
+//
+// NOTE: Experimental
+func NewQueryDefinition[I, O any](name string) QueryDefinition[I, O] {
+	return QueryDefinition[I, O]{name: name}
+}
+
+// Name returns the name of the query.
+func (d QueryDefinition[I, O]) Name() string {
+	return d.name
+}
+
+// NewUpdateDefinition creates a definition of the update with the given name, argument type and
+// result type. Use struct{} as the argument or result type of updates that take no argument or
+// return no result.
+//
+// NOTE: Experimental
+func NewUpdateDefinition[I, O any](name string) UpdateDefinition[I, O] {
+	return UpdateDefinition[I, O]{name: name}
+}
+
+// Name returns the name of the update.
+func (d UpdateDefinition[I, O]) Name() string {
+	return d.name
+}
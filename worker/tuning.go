@@ -22,6 +22,9 @@ type SlotReservationInfo = internal.SlotReservationInfo
 // SlotSupplier.MarkSlotUsed calls.
 type SlotMarkUsedInfo = internal.SlotMarkUsedInfo
 
+// SlotTaskInfo describes the task a slot is being used for. See SlotMarkUsedInfo.TaskInfo.
+type SlotTaskInfo = internal.SlotTaskInfo
+
 // SlotReleaseInfo contains information that SlotSupplier instances can use during
 // SlotSupplier.ReleaseSlot calls.
 type SlotReleaseInfo = internal.SlotReleaseInfo
@@ -32,6 +35,51 @@ type FixedSizeTunerOptions = internal.FixedSizeTunerOptions
 // CompositeTunerOptions are the options used by NewCompositeTuner.
 type CompositeTunerOptions = internal.CompositeTunerOptions
 
+// SlotReservationObserver is an optional interface that WorkerTuner implementations can
+// implement to receive an event for every slot reservation decision made by the workers using
+// them. CompositeTunerOptions.SlotReservationObserver adds one to a composite tuner.
+//
+// Reservations are also recorded by the temporal_worker_task_slot_reservation_latency timer,
+// tagged with wait_reason ("slots", "rate_limit" or "activity_type_limit"), the
+// temporal_worker_task_slot_reservation_denied counter, tagged with denial_reason, which does not
+// count reservations canceled on shutdown, and the temporal_worker_task_slots_at_max_duration
+// timer.
+//
+// NOTE: Experimental
+type SlotReservationObserver = internal.SlotReservationObserver
+
+// SlotReservationEvent describes a decision made on a slot reservation by a worker.
+//
+// NOTE: Experimental
+type SlotReservationEvent = internal.SlotReservationEvent
+
+// SlotReservationOutcome is the decision made on a slot reservation.
+//
+// NOTE: Experimental
+type SlotReservationOutcome = internal.SlotReservationOutcome
+
+const (
+	// SlotReservationOutcomeReserved means a slot was reserved.
+	//
+	// NOTE: Experimental
+	SlotReservationOutcomeReserved = internal.SlotReservationOutcomeReserved
+	// SlotReservationOutcomeUnavailable means no slot was available for a reservation that does
+	// not wait, such as for an eager workflow or activity.
+	//
+	// NOTE: Experimental
+	SlotReservationOutcomeUnavailable = internal.SlotReservationOutcomeUnavailable
+	// SlotReservationOutcomeCanceled means the worker stopped waiting for a slot, typically
+	// because it is shutting down.
+	//
+	// NOTE: Experimental
+	SlotReservationOutcomeCanceled = internal.SlotReservationOutcomeCanceled
+	// SlotReservationOutcomeFailed means the SlotSupplier returned an error. The reservation is
+	// retried.
+	//
+	// NOTE: Experimental
+	SlotReservationOutcomeFailed = internal.SlotReservationOutcomeFailed
+)
+
 // NewFixedSizeTuner creates a WorkerTuner that uses fixed size slot suppliers.
 func NewFixedSizeTuner(options FixedSizeTunerOptions) (WorkerTuner, error) {
 	return internal.NewFixedSizeTuner(options)
@@ -55,6 +103,14 @@ type SysInfoProvider = internal.SysInfoProvider
 // SysInfoContext provides context for SysInfoProvider calls.
 type SysInfoContext = internal.SysInfoContext
 
+// PressureInfoProvider is an optional interface that SysInfoProvider implementations can implement
+// to report resource pressure, such as Linux pressure stall information (PSI). It is required to
+// set the pressure thresholds of ResourceBasedTunerOptions and ResourceControllerOptions.
+// contrib/sysinfo.SysInfoProvider() implements it.
+//
+// NOTE: Experimental
+type PressureInfoProvider = internal.PressureInfoProvider
+
 // HasSysInfoProvider is an optional interface that SlotSupplier implementations can implement
 // to expose their SysInfoProvider.
 type HasSysInfoProvider = internal.HasSysInfoProvider
@@ -75,6 +131,9 @@ func NewResourceBasedTuner(opts ResourceBasedTunerOptions) (WorkerTuner, error)
 type ResourceBasedSlotSupplierOptions = internal.ResourceBasedSlotSupplierOptions
 
 // ResourceBasedSlotSupplier is a SlotSupplier that issues slots based on system resource usage.
+// While the task queue backlog is behind the BacklogLatencyTarget of an autoscaling poller
+// behavior, it also has a slot target scaled on the backlog age, below which slots are issued
+// without ramping up as long as resources are below their targets.
 type ResourceBasedSlotSupplier = internal.ResourceBasedSlotSupplier
 
 // NewResourceBasedSlotSupplier creates a ResourceBasedSlotSupplier given the provided
@@ -122,3 +181,112 @@ func DefaultWorkflowResourceBasedSlotSupplierOptions() ResourceBasedSlotSupplier
 func DefaultActivityResourceBasedSlotSupplierOptions() ResourceBasedSlotSupplierOptions {
 	return internal.DefaultActivityResourceBasedSlotSupplierOptions()
 }
+
+// RateLimitedSlotSupplierOptions are the options used by NewRateLimitedSlotSupplier.
+//
+// NOTE: Experimental
+type RateLimitedSlotSupplierOptions = internal.RateLimitedSlotSupplierOptions
+
+// RateLimitedSlotSupplier is a SlotSupplier that limits the rate at which tasks of each activity
+// type start. Slots are issued by a wrapped SlotSupplier. While an activity type is over its rate,
+// slot reservations for the task queues it was polled from wait, so their workers stop polling and
+// tasks are not held after being polled. Reservations for other task queues are not held back, so
+// give each rate limited activity type a task queue of its own.
+//
+// NOTE: Experimental
+type RateLimitedSlotSupplier = internal.RateLimitedSlotSupplier
+
+// NewRateLimitedSlotSupplier creates a RateLimitedSlotSupplier with the given options.
+//
+// NOTE: Experimental
+func NewRateLimitedSlotSupplier(options RateLimitedSlotSupplierOptions) (*RateLimitedSlotSupplier, error) {
+	return internal.NewRateLimitedSlotSupplier(options)
+}
+
+// PrioritySlotSupplierOptions are the options used by NewPrioritySlotSupplier.
+//
+// NOTE: Experimental
+type PrioritySlotSupplierOptions = internal.PrioritySlotSupplierOptions
+
+// PrioritySlotSupplier is a SlotSupplier that issues a fixed number of slots while holding some
+// of them back for higher priority reservations. Priorities are assigned at reservation time,
+// before a task is polled, typically by task queue.
+//
+// NOTE: Experimental
+type PrioritySlotSupplier = internal.PrioritySlotSupplier
+
+// NewPrioritySlotSupplier creates a PrioritySlotSupplier with the given options.
+//
+// NOTE: Experimental
+func NewPrioritySlotSupplier(options PrioritySlotSupplierOptions) (*PrioritySlotSupplier, error) {
+	return internal.NewPrioritySlotSupplier(options)
+}
+
+// ActivityTypeConcurrencySlotSupplierOptions are the options used by
+// NewActivityTypeConcurrencySlotSupplier.
+//
+// NOTE: Experimental
+type ActivityTypeConcurrencySlotSupplierOptions = internal.ActivityTypeConcurrencySlotSupplierOptions
+
+// ActivityTypeConcurrencySlotSupplier is a SlotSupplier that caps the number of tasks of each
+// activity type that run at once. Slots are issued by a wrapped SlotSupplier. Since the type of a
+// task is only known once it is polled, slots are not reserved, and so the worker stops polling,
+// while the next task could exceed a cap, whichever activity type it would be. Workers with
+// Options.EnableActivityTypeConcurrencyLimits set apply Options.MaxConcurrentActivityTypeExecutionSize
+// and RegisterActivityOptions.MaxConcurrentExecutions this way.
+//
+// NOTE: Experimental
+type ActivityTypeConcurrencySlotSupplier = internal.ActivityTypeConcurrencySlotSupplier
+
+// NewActivityTypeConcurrencySlotSupplier creates an ActivityTypeConcurrencySlotSupplier with the
+// given options.
+//
+// NOTE: Experimental
+func NewActivityTypeConcurrencySlotSupplier(
+	options ActivityTypeConcurrencySlotSupplierOptions,
+) (*ActivityTypeConcurrencySlotSupplier, error) {
+	return internal.NewActivityTypeConcurrencySlotSupplier(options)
+}
+
+// SlotPoolOptions are the options used by NewSlotPool.
+//
+// NOTE: Experimental
+type SlotPoolOptions = internal.SlotPoolOptions
+
+// SlotPool is a budget of slots shared by several workers in the same process. Each worker draws
+// from it through its own SlotPoolSupplier, which is guaranteed its MinSlots and receives a
+// share of the remaining slots proportional to its Weight when workers contend for them.
+//
+// NOTE: Experimental
+type SlotPool = internal.SlotPool
+
+// NewSlotPool creates a SlotPool with the given options.
+//
+// NOTE: Experimental
+func NewSlotPool(options SlotPoolOptions) (*SlotPool, error) {
+	return internal.NewSlotPool(options)
+}
+
+// SlotPoolSupplierOptions are the options used by NewSlotPoolSupplier.
+//
+// NOTE: Experimental
+type SlotPoolSupplierOptions = internal.SlotPoolSupplierOptions
+
+// SlotPoolSupplier is a SlotSupplier that issues slots from a SlotPool. Its metrics are tagged
+// with slot_pool_member to show how much of the pool each worker consumes. Close it once its
+// worker has stopped for good to give its MinSlots back to the pool and free its name.
+//
+// NOTE: Experimental
+type SlotPoolSupplier = internal.SlotPoolSupplier
+
+// NewSlotPoolSupplier adds a member to the pool and returns the SlotSupplier that issues its
+// slots. Give each member to the tuner of a single worker, for example:
+//
+//	pool, _ := worker.NewSlotPool(worker.SlotPoolOptions{NumSlots: 200})
+//	orders, _ := worker.NewSlotPoolSupplier(pool, worker.SlotPoolSupplierOptions{Name: "orders", Weight: 3, MinSlots: 20})
+//	reports, _ := worker.NewSlotPoolSupplier(pool, worker.SlotPoolSupplierOptions{Name: "reports"})
+//
+// NOTE: Experimental
+func NewSlotPoolSupplier(pool *SlotPool, options SlotPoolSupplierOptions) (*SlotPoolSupplier, error) {
+	return internal.NewSlotPoolSupplier(pool, options)
+}
@@ -3,6 +3,7 @@ package worker
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/nexus-rpc/sdk-go/nexus"
 	historypb "go.temporal.io/api/history/v1"
@@ -232,6 +233,41 @@ type (
 
 	// ReplayWorkflowHistoryOptions are options for replaying a workflow.
 	ReplayWorkflowHistoryOptions = internal.ReplayWorkflowHistoryOptions
+
+	// ReconfigureOptions are the settings of a running worker changed by Reconfigure.
+	//
+	// NOTE: Experimental
+	ReconfigureOptions = internal.WorkerReconfigureOptions
+
+	// TaskType is a kind of task run by a worker, used to choose what Pause and Resume apply to.
+	//
+	// NOTE: Experimental
+	TaskType = internal.WorkerTaskType
+
+	// RunningTask describes a task that a worker is running.
+	//
+	// NOTE: Experimental
+	RunningTask = internal.WorkerRunningTask
+
+	// DrainResult describes the work that a worker was still doing when Drain returned.
+	//
+	// NOTE: Experimental
+	DrainResult = internal.WorkerDrainResult
+
+	// HealthSnapshot describes the health of a worker at a point in time, see Health.
+	//
+	// NOTE: Experimental
+	HealthSnapshot = internal.WorkerHealthSnapshot
+
+	// PollerHealth describes the pollers of a worker for one kind of poll.
+	//
+	// NOTE: Experimental
+	PollerHealth = internal.WorkerPollerHealth
+
+	// SlotHealth describes the slots of a worker for one task type.
+	//
+	// NOTE: Experimental
+	SlotHealth = internal.WorkerSlotHealth
 )
 
 var _ WorkflowRegistry = (WorkflowReplayer)(nil)
@@ -248,6 +284,26 @@ const (
 	FailWorkflow = internal.FailWorkflow
 )
 
+const (
+	// TaskTypeWorkflow is the type of workflow tasks.
+	//
+	// NOTE: Experimental
+	TaskTypeWorkflow = internal.WorkerTaskTypeWorkflow
+	// TaskTypeActivity is the type of activity tasks, including the activities of sessions.
+	//
+	// NOTE: Experimental
+	TaskTypeActivity = internal.WorkerTaskTypeActivity
+	// TaskTypeLocalActivity is the type of local activities. They are not polled for, so they
+	// cannot be paused, and are started only by running workflow tasks.
+	//
+	// NOTE: Experimental
+	TaskTypeLocalActivity = internal.WorkerTaskTypeLocalActivity
+	// TaskTypeNexus is the type of nexus tasks.
+	//
+	// NOTE: Experimental
+	TaskTypeNexus = internal.WorkerTaskTypeNexus
+)
+
 // New creates an instance of worker for managing workflow and activity executions.
 //
 //	client    - the client for use by the worker
@@ -314,6 +370,83 @@ func InterruptCh() <-chan interface{} {
 	return internal.InterruptCh()
 }
 
+// Reconfigure changes the slot counts, poller behaviors and rate limits of a worker created with
+// New while it is running, without interrupting tasks in progress. All changes are validated
+// before any is applied, so an error leaves the worker unchanged. Workers that were not created
+// with New, and the session activities of a worker, are not supported.
+//
+// NOTE: Experimental
+func Reconfigure(w Worker, options ReconfigureOptions) error {
+	r, ok := w.(interface {
+		Reconfigure(internal.WorkerReconfigureOptions) error
+	})
+	if !ok {
+		return fmt.Errorf("worker of type %T cannot be reconfigured", w)
+	}
+	return r.Reconfigure(options)
+}
+
+// Pause stops a worker created with New from polling for the given task types, or all of them if
+// none are given. Tasks that are running, and tasks delivered by polls already in progress, are
+// run to completion. Cached workflows are kept, so they can continue on this worker once resumed.
+//
+// NOTE: Experimental
+func Pause(w Worker, taskTypes ...TaskType) error {
+	p, ok := w.(interface {
+		Pause(...internal.WorkerTaskType) error
+	})
+	if !ok {
+		return fmt.Errorf("worker of type %T cannot be paused", w)
+	}
+	return p.Pause(taskTypes...)
+}
+
+// Resume starts a worker paused with Pause or Drain polling again for the given task types, or all
+// of them if none are given.
+//
+// NOTE: Experimental
+func Resume(w Worker, taskTypes ...TaskType) error {
+	r, ok := w.(interface {
+		Resume(...internal.WorkerTaskType) error
+	})
+	if !ok {
+		return fmt.Errorf("worker of type %T cannot be resumed", w)
+	}
+	return r.Resume(taskTypes...)
+}
+
+// Drain stops a worker created with New from polling for any task type, and waits until the
+// tasks it is running have completed. Polls in progress are not waited for, since a long poll
+// that gets no task takes about a minute, see DrainResult.PendingPolls. If the context is done
+// first, it returns the context error with a DrainResult describing the work still in progress.
+// The worker stays paused, so it can be stopped without waiting for WorkerStopTimeout, or resumed.
+//
+// NOTE: Experimental
+func Drain(ctx context.Context, w Worker) (DrainResult, error) {
+	d, ok := w.(interface {
+		Drain(context.Context) (internal.WorkerDrainResult, error)
+	})
+	if !ok {
+		return DrainResult{}, fmt.Errorf("worker of type %T cannot be drained", w)
+	}
+	return d.Drain(ctx)
+}
+
+// Health returns a snapshot of the health of a worker created with New: whether its polls are
+// succeeding, how its slots are used, the occupancy of the sticky cache and how many workflow
+// tasks failed on potential deadlocks. It is meant to back health probes and admin pages.
+//
+// NOTE: Experimental
+func Health(w Worker) (HealthSnapshot, error) {
+	h, ok := w.(interface {
+		Health() internal.WorkerHealthSnapshot
+	})
+	if !ok {
+		return HealthSnapshot{}, fmt.Errorf("worker of type %T does not report its health", w)
+	}
+	return h.Health(), nil
+}
+
 // NewPollerBehaviorSimpleMaximum creates a PollerBehavior that allows the worker to start up to a maximum number of pollers.
 func NewPollerBehaviorSimpleMaximum(
 	options PollerBehaviorSimpleMaximumOptions,
@@ -40,10 +40,56 @@ type (
 	// Use [workflow.NewMutex] method to create a Mutex instance.
 	Mutex = internal.Mutex
 
+	// RWMutex is a reader/writer mutual exclusion lock.
+	// RWMutex must be used instead of native go RWMutex by workflow code.
+	// Use [workflow.NewRWMutex] method to create a RWMutex instance.
+	//
+	// NOTE: Experimental
+	RWMutex = internal.RWMutex
+
 	// Semaphore is a counting semaphore.
 	// Use [workflow.NewSemaphore] method to create a Semaphore instance.
 	Semaphore = internal.Semaphore
 
+	// ErrGroup runs a group of coroutines and collects the first error returned by one of them.
+	// Use [workflow.NewErrGroup] method to create an ErrGroup instance.
+	//
+	// NOTE: Experimental
+	ErrGroup = internal.ErrGroup
+
+	// ErrGroupOptions are options for [NewErrGroup].
+	//
+	// NOTE: Experimental
+	ErrGroupOptions = internal.ErrGroupOptions
+
+	// Saga registers compensations for the steps of a workflow, and runs them to undo the steps
+	// when the workflow fails or is canceled. Use [workflow.NewSaga] method to create a Saga
+	// instance.
+	//
+	// NOTE: Experimental
+	Saga = internal.Saga
+
+	// SagaOptions are options for [NewSaga].
+	//
+	// NOTE: Experimental
+	SagaOptions = internal.SagaOptions
+
+	// SagaCompensationError is returned by Saga.Compensate when compensations failed.
+	//
+	// NOTE: Experimental
+	SagaCompensationError = internal.SagaCompensationError
+
+	// RateLimiter paces work of a workflow with a token bucket driven by workflow timers.
+	// Use [workflow.NewRateLimiter] method to create a RateLimiter instance.
+	//
+	// NOTE: Experimental
+	RateLimiter = internal.RateLimiter
+
+	// RateLimiterOptions are options for [NewRateLimiter].
+	//
+	// NOTE: Experimental
+	RateLimiterOptions = internal.RateLimiterOptions
+
 	// TimerOptions are options for [NewTimerWithOptions]
 	//
 	// NOTE: Experimental
@@ -155,11 +201,78 @@ func NewMutex(ctx Context) Mutex {
 	return internal.NewMutex(ctx)
 }
 
+// NewRWMutex creates a new RWMutex instance. A RWMutex can be used when
+// coroutines of a workflow, such as update handlers, may read state concurrently
+// across blocking calls but must have exclusive access to change it.
+//
+// NOTE: Experimental
+func NewRWMutex(ctx Context) RWMutex {
+	return internal.NewRWMutex(ctx)
+}
+
 // NewSemaphore creates a new Semaphore instance.
 func NewSemaphore(ctx Context, n int64) Semaphore {
 	return internal.NewSemaphore(ctx, n)
 }
 
+// NewErrGroup creates a new ErrGroup instance and a Context derived from ctx. The Context is
+// canceled when a function of the group returns an error, or when Wait returns. Use it to fan out
+// work with error propagation, cancellation of siblings and a bound on concurrency:
+//
+//	group, _ := workflow.NewErrGroup(ctx, workflow.ErrGroupOptions{Limit: 10})
+//	for _, item := range items {
+//		group.Go(func(ctx workflow.Context) error {
+//			return workflow.ExecuteActivity(ctx, ProcessItem, item).Get(ctx, nil)
+//		})
+//	}
+//	err := group.Wait(ctx)
+//
+// Functions are started in the order they are passed to Go, so the group is deterministic on
+// replay. The returned Context is the one passed to the functions.
+//
+// NOTE: Experimental
+func NewErrGroup(ctx Context, options ErrGroupOptions) (ErrGroup, Context) {
+	return internal.NewErrGroup(ctx, options)
+}
+
+// NewSaga creates a new Saga instance. Register a compensation for each step of the workflow, and
+// have them run when the workflow fails or is canceled:
+//
+//	func TransferWorkflow(ctx workflow.Context, transfer Transfer) (err error) {
+//		saga := workflow.NewSaga(ctx, workflow.SagaOptions{})
+//		defer saga.CompensateOnError(ctx, &err)
+//
+//		if err := workflow.ExecuteActivity(ctx, Withdraw, transfer).Get(ctx, nil); err != nil {
+//			return err
+//		}
+//		saga.AddCompensation(func(ctx workflow.Context) error {
+//			return workflow.ExecuteActivity(ctx, Deposit, transfer.Reverse()).Get(ctx, nil)
+//		})
+//		return workflow.ExecuteActivity(ctx, Deposit, transfer).Get(ctx, nil)
+//	}
+//
+// NOTE: Experimental
+func NewSaga(ctx Context, options SagaOptions) Saga {
+	return internal.NewSaga(ctx, options)
+}
+
+// NewRateLimiter creates a new RateLimiter instance. Use it to pace the starts of activities or
+// child workflows, e.g. from many concurrent update handlers:
+//
+//	limiter, err := workflow.NewRateLimiter(ctx, workflow.RateLimiterOptions{Interval: time.Second, Burst: 5})
+//	...
+//	if err := limiter.Wait(ctx); err != nil {
+//		return err
+//	}
+//	err := workflow.ExecuteActivity(ctx, CallPartnerAPI, request).Get(ctx, nil)
+//
+// The limiter is driven by workflow time and timers, so it is deterministic on replay.
+//
+// NOTE: Experimental
+func NewRateLimiter(ctx Context, options RateLimiterOptions) (RateLimiter, error) {
+	return internal.NewRateLimiter(ctx, options)
+}
+
 // Go creates a new coroutine. It has similar semantics to a goroutine, but in the context of the workflow.
 func Go(ctx Context, f func(ctx Context)) {
 	internal.Go(ctx, f)
@@ -0,0 +1,211 @@
+package workflow
+
+import (
+	"context"
+	"time"
+
+	"go.temporal.io/sdk/temporal"
+)
+
+// TypedFuture is a [Future] of a result of type T.
+//
+// NOTE: Experimental
+type TypedFuture[T any] interface {
+	// Get blocks until the future is ready and returns its result. When the future failed, the
+	// returned error is the failure and the result is the zero value of T unless the failure
+	// carries details that were decoded into it.
+	Get(ctx Context) (T, error)
+
+	// IsReady returns true when the future is ready and Get will not block.
+	IsReady() bool
+
+	// Future returns the untyped Future backing this future, for use with Selector.AddFuture.
+	Future() Future
+}
+
+// TypedChildWorkflowFuture is a [ChildWorkflowFuture] of a result of type T.
+//
+// NOTE: Experimental
+type TypedChildWorkflowFuture[T any] interface {
+	TypedFuture[T]
+
+	// GetChildWorkflowExecution returns a future that will be ready when the child workflow
+	// execution has started. See [ChildWorkflowFuture].
+	GetChildWorkflowExecution() Future
+
+	// SignalChildWorkflow sends a signal to the child workflow. See [ChildWorkflowFuture].
+	SignalChildWorkflow(ctx Context, signalName string, data interface{}) Future
+}
+
+// NewTypedFuture returns a TypedFuture decoding the result of future into T. Useful for the futures
+// of activities and child workflows that don't follow the single argument - single return type
+// signature required by [ExecuteTypedActivity] and [ExecuteTypedChildWorkflow].
+//
+//	future := workflow.NewTypedFuture[string](workflow.ExecuteActivity(ctx, a.Greet, "Temporal", "!"))
+//	greeting, err := future.Get(ctx)
+//
+// NOTE: Experimental
+func NewTypedFuture[T any](future Future) TypedFuture[T] {
+	return typedFuture[T]{future: future}
+}
+
+// ExecuteTypedActivity requests activity execution like [ExecuteActivity], checking the input and
+// result types of the activity at compile time. The activity must take a context.Context and a
+// single argument, and return a result and an error.
+//
+//	future := workflow.ExecuteTypedActivity(ctx, a.Greet, GreetInput{Name: "Temporal"})
+//	output, err := future.Get(ctx) // output is a GreetOutput
+//
+// The activity is resolved by function reference as it would be when passed to ExecuteActivity, so
+// it must be registered under the same name on the activity worker.
+//
+// NOTE: Experimental
+func ExecuteTypedActivity[I, O any, A func(context.Context, I) (O, error)](ctx Context, activity A, arg I) TypedFuture[O] {
+	return NewTypedFuture[O](ExecuteActivity(ctx, activity, arg))
+}
+
+// ExecuteTypedLocalActivity requests local activity execution like [ExecuteLocalActivity],
+// checking the input and result types of the activity at compile time. The activity must take a
+// context.Context and a single argument, and return a result and an error.
+//
+// NOTE: Experimental
+func ExecuteTypedLocalActivity[I, O any, A func(context.Context, I) (O, error)](ctx Context, activity A, arg I) TypedFuture[O] {
+	return NewTypedFuture[O](ExecuteLocalActivity(ctx, activity, arg))
+}
+
+// ExecuteTypedChildWorkflow requests child workflow execution like [ExecuteChildWorkflow], checking
+// the input and result types of the child workflow at compile time. The child workflow must take a
+// Context and a single argument, and return a result and an error.
+//
+//	future := workflow.ExecuteTypedChildWorkflow(ctx, ProcessOrder, order)
+//	receipt, err := future.Get(ctx) // receipt is a Receipt
+//
+// NOTE: Experimental
+func ExecuteTypedChildWorkflow[I, O any, WF func(Context, I) (O, error)](ctx Context, childWorkflow WF, arg I) TypedChildWorkflowFuture[O] {
+	future := ExecuteChildWorkflow(ctx, childWorkflow, arg)
+	return typedChildWorkflowFuture[O]{typedFuture: typedFuture[O]{future: future}, child: future}
+}
+
+// TypedReceiveChannel is a [ReceiveChannel] of values of type T.
+//
+// NOTE: Experimental
+type TypedReceiveChannel[T any] interface {
+	// Receive blocks until it receives a value, and then returns it. more is false when the channel
+	// is closed. See [ReceiveChannel].
+	Receive(ctx Context) (value T, more bool)
+
+	// ReceiveWithTimeout blocks up to timeout until it receives a value, and then returns it. ok is
+	// false when no value was received before the timeout. See [ReceiveChannel].
+	ReceiveWithTimeout(ctx Context, timeout time.Duration) (value T, ok, more bool)
+
+	// ReceiveAsync tries to receive a value without blocking. ok is false when there is no value to
+	// receive. See [ReceiveChannel].
+	ReceiveAsync() (value T, ok bool)
+
+	// Len returns the number of buffered values in the channel.
+	Len() int
+
+	// Channel returns the untyped ReceiveChannel backing this channel, for use with
+	// Selector.AddReceive.
+	Channel() ReceiveChannel
+}
+
+// GetTypedSignalChannel returns the channel of the signal defined by signal, like
+// [GetSignalChannel].
+//
+//	// In an API package shared with the callers of the workflow
+//	var ApproveSignal = temporal.NewSignalDefinition[Approval]("approve")
+//
+//	// In the workflow
+//	approval, _ := workflow.GetTypedSignalChannel(ctx, api.ApproveSignal).Receive(ctx)
+//
+// NOTE: Experimental
+func GetTypedSignalChannel[T any](ctx Context, signal temporal.SignalDefinition[T]) TypedReceiveChannel[T] {
+	return typedReceiveChannel[T]{channel: GetSignalChannel(ctx, signal.Name())}
+}
+
+// SignalTypedExternalWorkflow sends the signal defined by signal to a workflow, like
+// [SignalExternalWorkflow].
+//
+// NOTE: Experimental
+func SignalTypedExternalWorkflow[T any](ctx Context, workflowID, runID string, signal temporal.SignalDefinition[T], arg T) Future {
+	return SignalExternalWorkflow(ctx, workflowID, runID, signal.Name(), arg)
+}
+
+// SetTypedQueryHandler sets the handler of the query defined by query, like [SetQueryHandler].
+//
+// NOTE: Experimental
+func SetTypedQueryHandler[I, O any](ctx Context, query temporal.QueryDefinition[I, O], handler func(I) (O, error)) error {
+	return SetQueryHandler(ctx, query.Name(), handler)
+}
+
+// SetTypedUpdateHandler sets the handler of the update defined by update, like
+// [SetUpdateHandlerWithOptions]. A Validator set in opts must be a func(Context, I) error.
+//
+// NOTE: Experimental
+func SetTypedUpdateHandler[I, O any](
+	ctx Context,
+	update temporal.UpdateDefinition[I, O],
+	handler func(Context, I) (O, error),
+	opts UpdateHandlerOptions,
+) error {
+	return SetUpdateHandlerWithOptions(ctx, update.Name(), handler, opts)
+}
+
+type typedFuture[T any] struct {
+	future Future
+}
+
+func (f typedFuture[T]) Get(ctx Context) (T, error) {
+	var result T
+	err := f.future.Get(ctx, &result)
+	return result, err
+}
+
+func (f typedFuture[T]) IsReady() bool {
+	return f.future.IsReady()
+}
+
+func (f typedFuture[T]) Future() Future {
+	return f.future
+}
+
+type typedChildWorkflowFuture[T any] struct {
+	typedFuture[T]
+	child ChildWorkflowFuture
+}
+
+func (f typedChildWorkflowFuture[T]) GetChildWorkflowExecution() Future {
+	return f.child.GetChildWorkflowExecution()
+}
+
+func (f typedChildWorkflowFuture[T]) SignalChildWorkflow(ctx Context, signalName string, data interface{}) Future {
+	return f.child.SignalChildWorkflow(ctx, signalName, data)
+}
+
+type typedReceiveChannel[T any] struct {
+	channel ReceiveChannel
+}
+
+func (c typedReceiveChannel[T]) Receive(ctx Context) (value T, more bool) {
+	more = c.channel.Receive(ctx, &value)
+	return value, more
+}
+
+func (c typedReceiveChannel[T]) ReceiveWithTimeout(ctx Context, timeout time.Duration) (value T, ok, more bool) {
+	ok, more = c.channel.ReceiveWithTimeout(ctx, timeout, &value)
+	return value, ok, more
+}
+
+func (c typedReceiveChannel[T]) ReceiveAsync() (value T, ok bool) {
+	ok = c.channel.ReceiveAsync(&value)
+	return value, ok
+}
+
+func (c typedReceiveChannel[T]) Len() int {
+	return c.channel.Len()
+}
+
+func (c typedReceiveChannel[T]) Channel() ReceiveChannel {
+	return c.channel
+}
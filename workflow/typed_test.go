@@ -0,0 +1,169 @@
+package workflow_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.temporal.io/sdk/temporal"
+	"go.temporal.io/sdk/testsuite"
+	"go.temporal.io/sdk/workflow"
+)
+
+type greetInput struct {
+	Name string
+}
+
+type greetOutput struct {
+	Greeting string
+}
+
+func greetActivity(_ context.Context, in greetInput) (greetOutput, error) {
+	if in.Name == "" {
+		return greetOutput{}, errors.New("name required")
+	}
+	return greetOutput{Greeting: "Hello " + in.Name}, nil
+}
+
+func shoutWorkflow(ctx workflow.Context, in greetInput) (string, error) {
+	ctx = workflow.WithActivityOptions(ctx, workflow.ActivityOptions{StartToCloseTimeout: time.Minute})
+	out, err := workflow.ExecuteTypedActivity(ctx, greetActivity, in).Get(ctx)
+	if err != nil {
+		return "", err
+	}
+	return strings.ToUpper(out.Greeting), nil
+}
+
+func typedParentWorkflow(ctx workflow.Context, name string) ([]string, error) {
+	var results []string
+	lctx := workflow.WithLocalActivityOptions(ctx, workflow.LocalActivityOptions{StartToCloseTimeout: time.Minute})
+	local, err := workflow.ExecuteTypedLocalActivity(lctx, greetActivity, greetInput{Name: name}).Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, local.Greeting)
+
+	child := workflow.ExecuteTypedChildWorkflow(ctx, shoutWorkflow, greetInput{Name: name})
+	if err := child.GetChildWorkflowExecution().Get(ctx, nil); err != nil {
+		return nil, err
+	}
+	shout, err := child.Get(ctx)
+	if err != nil {
+		return nil, err
+	}
+	results = append(results, shout)
+
+	// A failed activity returns the zero value and the error
+	actx := workflow.WithActivityOptions(ctx, workflow.ActivityOptions{
+		StartToCloseTimeout: time.Minute,
+		RetryPolicy:         &temporal.RetryPolicy{MaximumAttempts: 1},
+	})
+	failed := workflow.ExecuteTypedActivity(actx, greetActivity, greetInput{})
+	var ready bool
+	workflow.NewSelector(ctx).AddFuture(failed.Future(), func(workflow.Future) { ready = failed.IsReady() }).Select(ctx)
+	out, err := failed.Get(ctx)
+	if err == nil || out != (greetOutput{}) || !ready {
+		return nil, errors.New("expected failed activity")
+	}
+	return results, nil
+}
+
+func TestTypedHelpers(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.RegisterWorkflow(shoutWorkflow)
+	env.RegisterActivity(greetActivity)
+
+	env.ExecuteWorkflow(typedParentWorkflow, "Temporal")
+	require.True(t, env.IsWorkflowCompleted())
+	require.NoError(t, env.GetWorkflowError())
+	var results []string
+	require.NoError(t, env.GetWorkflowResult(&results))
+	require.Equal(t, []string{"Hello Temporal", "HELLO TEMPORAL"}, results)
+}
+
+func TestNewTypedFuture(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+	env.ExecuteWorkflow(func(ctx workflow.Context) (int, error) {
+		future, settable := workflow.NewFuture(ctx)
+		settable.Set(42, nil)
+		return workflow.NewTypedFuture[int](future).Get(ctx)
+	})
+	require.NoError(t, env.GetWorkflowError())
+	var result int
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 42, result)
+}
+
+var (
+	approveSignal = temporal.NewSignalDefinition[greetInput]("approve")
+	statusQuery   = temporal.NewQueryDefinition[string, string]("status")
+	addUpdate     = temporal.NewUpdateDefinition[int, int]("add")
+)
+
+func typedHandlersWorkflow(ctx workflow.Context) (int, error) {
+	total := 0
+	approvedBy := ""
+	if err := workflow.SetTypedQueryHandler(ctx, statusQuery, func(prefix string) (string, error) {
+		return prefix + approvedBy, nil
+	}); err != nil {
+		return 0, err
+	}
+	if err := workflow.SetTypedUpdateHandler(ctx, addUpdate, func(ctx workflow.Context, n int) (int, error) {
+		total += n
+		return total, nil
+	}, workflow.UpdateHandlerOptions{
+		Validator: func(ctx workflow.Context, n int) error {
+			if n < 0 {
+				return errors.New("negative")
+			}
+			return nil
+		},
+	}); err != nil {
+		return 0, err
+	}
+	approval, _ := workflow.GetTypedSignalChannel(ctx, approveSignal).Receive(ctx)
+	approvedBy = approval.Name
+	if err := workflow.Await(ctx, func() bool { return total >= 5 }); err != nil {
+		return 0, err
+	}
+	return total, nil
+}
+
+func TestTypedDefinitions(t *testing.T) {
+	var suite testsuite.WorkflowTestSuite
+	env := suite.NewTestWorkflowEnvironment()
+
+	env.RegisterDelayedCallback(func() {
+		env.SignalWorkflow(approveSignal.Name(), greetInput{Name: "Temporal"})
+	}, time.Second)
+	env.RegisterDelayedCallback(func() {
+		value, err := env.QueryWorkflow(statusQuery.Name(), "approved by ")
+		require.NoError(t, err)
+		var status string
+		require.NoError(t, value.Get(&status))
+		require.Equal(t, "approved by Temporal", status)
+
+		env.UpdateWorkflow(addUpdate.Name(), "rejected", &testsuite.TestUpdateCallback{
+			OnReject: func(err error) { require.ErrorContains(t, err, "negative") },
+			OnAccept: func() { t.Error("update accepted") },
+		}, -1)
+		env.UpdateWorkflow(addUpdate.Name(), "accepted", &testsuite.TestUpdateCallback{
+			OnReject: func(err error) { t.Error(err) },
+			OnComplete: func(result interface{}, err error) {
+				require.NoError(t, err)
+				require.Equal(t, 5, result)
+			},
+		}, 5)
+	}, 2*time.Second)
+
+	env.ExecuteWorkflow(typedHandlersWorkflow)
+	require.NoError(t, env.GetWorkflowError())
+	var result int
+	require.NoError(t, env.GetWorkflowResult(&result))
+	require.Equal(t, 5, result)
+}